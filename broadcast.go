@@ -1,6 +1,14 @@
 package gorgonia
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/chewxy/hm"
+	"github.com/pkg/errors"
+)
 
 const (
 	bcAllowableAxes = 4
@@ -59,6 +67,7 @@ func (bcpat BroadcastPattern) on() (retVal [2][]int) {
 }
 
 // Broadcast works somewhat like Numpy's broadcast, except it's now exposed as a function.
+// Note that Add, Sub, HadamardProd, HadamardDiv, Pow and the comparison functions already broadcast their operands automatically.
 func Broadcast(binOp ʘBinaryOperatorType, a, b *Node, pattern BroadcastPattern) (retVal *Node, err error) {
 	broadcastOn := pattern.on()
 
@@ -98,3 +107,268 @@ func Broadcast(binOp ʘBinaryOperatorType, a, b *Node, pattern BroadcastPattern)
 	op := newElemBinOp(binOp, x, y)
 	return applyOp(op, x, y)
 }
+
+// broadcastShape returns the shape that a and b broadcast to, following NumPy's rules:
+// the shapes are aligned at their trailing axes, and each pair of sizes must either be equal or have one of them be 1.
+func broadcastShape(a, b tensor.Shape) (retVal tensor.Shape, err error) {
+	dims := len(a)
+	if len(b) > dims {
+		dims = len(b)
+	}
+
+	pa := padShape(a, dims)
+	pb := padShape(b, dims)
+	retVal = make(tensor.Shape, dims)
+	for i := range retVal {
+		switch {
+		case pa[i] == pb[i]:
+			retVal[i] = pa[i]
+		case pa[i] == 1:
+			retVal[i] = pb[i]
+		case pb[i] == 1:
+			retVal[i] = pa[i]
+		default:
+			return nil, errors.Errorf("Shapes %v and %v cannot be broadcast together", a, b)
+		}
+	}
+	return
+}
+
+// padShape left pads a shape with 1s so that it has the given number of dimensions
+func padShape(s tensor.Shape, dims int) tensor.Shape {
+	retVal := make(tensor.Shape, dims)
+	pad := dims - len(s)
+	for i := range retVal {
+		if i < pad {
+			retVal[i] = 1
+			continue
+		}
+		retVal[i] = s[i-pad]
+	}
+	return retVal
+}
+
+// sameShape checks that two shapes are exactly the same. Unlike Shape.Eq, a vector is not the same as a column or row vector.
+func sameShape(a, b tensor.Shape) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcastOperands broadcasts a and b to a common shape, following NumPy's rules.
+// Operands are only broadcast when both of their shapes are known and are not exactly the same, so a vector and a column vector
+// of the same length are broadcast to a matrix. Scalars, operands whose shapes are not known yet, and operands with the same shape
+// are returned as is, as the elementwise ops handle them natively.
+func broadcastOperands(a, b *Node) (x, y *Node, err error) {
+	if a.IsScalar() || b.IsScalar() || len(a.shape) == 0 || len(b.shape) == 0 || sameShape(a.shape, b.shape) {
+		return a, b, nil
+	}
+
+	var to tensor.Shape
+	if to, err = broadcastShape(a.shape, b.shape); err != nil {
+		return nil, nil, err
+	}
+
+	if x, err = broadcastTo(a, to); err != nil {
+		return nil, nil, err
+	}
+	if y, err = broadcastTo(b, to); err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+func broadcastTo(a *Node, to tensor.Shape) (*Node, error) {
+	if sameShape(a.shape, to) {
+		return a, nil
+	}
+	op := broadcastOp{from: a.shape.Clone(), to: to.Clone(), d: a.Dims()}
+	return applyOp(op, a)
+}
+
+// broadcastOp expands a tensor to a larger shape, following NumPy's broadcasting rules.
+// The input is left padded with size 1 axes, and then repeated along every size 1 axis that is bigger in the target shape.
+type broadcastOp struct {
+	from, to tensor.Shape
+	d        int // dims of the input
+}
+
+func (op broadcastOp) Arity() int { return 1 }
+
+// broadcastOp has this type:
+//		op :: Tensor-d a → Tensor-n a
+// where n is the number of dims of the target shape
+func (op broadcastOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(newTensorType(op.d, a), newTensorType(len(op.to), a))
+}
+
+func (op broadcastOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.to.Clone(), nil
+}
+
+func (op broadcastOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(*tensor.Dense)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "broadcastOp.Do", inputs[0])
+	}
+
+	var ret tensor.Tensor
+	if t.IsMaterializable() {
+		ret = t.Materialize()
+	} else {
+		ret = t.Clone().(*tensor.Dense)
+	}
+
+	padded := padShape(op.from, len(op.to))
+	if err = ret.Reshape(padded...); err != nil {
+		return nil, errors.Wrapf(err, reshapeFail, padded, ret.DataSize())
+	}
+
+	for i, size := range op.to {
+		if padded[i] == size {
+			continue
+		}
+		if ret, err = tensor.Repeat(ret, i, size); err != nil {
+			return nil, errors.Wrapf(err, doFail, op)
+		}
+	}
+	return ret, nil
+}
+
+func (op broadcastOp) ReturnsPtr() bool     { return false }
+func (op broadcastOp) CallsExtern() bool    { return false }
+func (op broadcastOp) OverwritesInput() int { return -1 }
+
+func (op broadcastOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "broadcast%v→%v", op.from, op.to)
+}
+
+func (op broadcastOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op broadcastOp) String() string { return fmt.Sprintf("Broadcast%v→%v", op.from, op.to) }
+
+func (op broadcastOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op broadcastOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(broadcastDiffOp{op}, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op broadcastOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = op.reduce(ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	_, err = add.UnsafeDo(xdv.d, d)
+	return
+}
+
+// reduce sums the broadcast gradient back down to the shape of the input of the broadcastOp.
+func (op broadcastOp) reduce(grad Value) (retVal Value, err error) {
+	t, ok := grad.(*tensor.Dense)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "broadcastOp.reduce", grad)
+	}
+
+	padded := padShape(op.from, len(op.to))
+	ret := t
+	// sum along the broadcast axes from the last to the first, so that the axes yet to be summed keep their positions
+	for i := len(op.to) - 1; i >= 0; i-- {
+		if padded[i] == op.to[i] {
+			continue
+		}
+		if ret, err = ret.Sum(i); err != nil {
+			return nil, errors.Wrapf(err, doFail, op)
+		}
+	}
+
+	if ret == t {
+		ret = t.Clone().(*tensor.Dense)
+	}
+	if err = ret.Reshape(op.from...); err != nil {
+		return nil, errors.Wrapf(err, reshapeFail, op.from, ret.DataSize())
+	}
+	return ret, nil
+}
+
+// broadcastDiffOp reduces the gradient of a broadcastOp back to the shape of its input by summing along the broadcast axes.
+type broadcastDiffOp struct {
+	broadcastOp
+}
+
+// broadcastDiffOp has this type:
+//		op :: Tensor-n a → Tensor-d a
+func (op broadcastDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(newTensorType(len(op.to), a), newTensorType(op.d, a))
+}
+
+func (op broadcastDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.from.Clone(), nil
+}
+
+func (op broadcastDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	return op.reduce(inputs[0])
+}
+
+func (op broadcastDiffOp) WriteHash(h hash.Hash) {
+	op.broadcastOp.WriteHash(h)
+	fmt.Fprintf(h, "Diff")
+}
+
+func (op broadcastDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op broadcastDiffOp) String() string { return fmt.Sprintf("∂%v", op.broadcastOp) }
+
+func (op broadcastDiffOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+func (op broadcastDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op broadcastDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
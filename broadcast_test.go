@@ -2,6 +2,7 @@ package gorgonia
 
 import (
 	"io/ioutil"
+	"math"
 	"testing"
 
 	"github.com/chewxy/gorgonia/tensor"
@@ -81,3 +82,139 @@ func TestBroadcast2(t *testing.T) {
 	assert.Equal([]float64{100, 101, 102, 203, 204, 205}, extractF64s(z.Value()))
 
 }
+
+func TestAutoBroadcast(t *testing.T) {
+	assert := assert.New(t)
+
+	bcs := []struct {
+		name         string
+		fn           func(a, b *Node) (*Node, error)
+		aShape       tensor.Shape
+		bShape       tensor.Shape
+		aBack, bBack []float64
+
+		correctShape tensor.Shape
+		correct      []float64
+		correctGradA []float64
+		correctGradB []float64
+	}{
+		{"Add (2,3)+(3)", Add, tensor.Shape{2, 3}, tensor.Shape{3}, []float64{0, 1, 2, 3, 4, 5}, []float64{10, 20, 30},
+			tensor.Shape{2, 3}, []float64{10, 21, 32, 13, 24, 35}, []float64{1, 1, 1, 1, 1, 1}, []float64{2, 2, 2}},
+		{"Sub (3,1)-(1,2)", Sub, tensor.Shape{3, 1}, tensor.Shape{1, 2}, []float64{1, 2, 3}, []float64{10, 20},
+			tensor.Shape{3, 2}, []float64{-9, -19, -8, -18, -7, -17}, []float64{2, 2, 2}, []float64{-3, -3}},
+		{"HadamardProd (2,1,2)*(3,1)", HadamardProd, tensor.Shape{2, 1, 2}, tensor.Shape{3, 1}, []float64{1, 2, 3, 4}, []float64{1, 2, 3},
+			tensor.Shape{2, 3, 2}, []float64{1, 2, 2, 4, 3, 6, 3, 4, 6, 8, 9, 12}, []float64{6, 6, 6, 6}, []float64{10, 10, 10}},
+		{"HadamardDiv (2,2)/(2)", HadamardDiv, tensor.Shape{2, 2}, tensor.Shape{2}, []float64{2, 4, 6, 8}, []float64{1, 2},
+			tensor.Shape{2, 2}, []float64{2, 2, 6, 4}, []float64{1, 0.5, 1, 0.5}, []float64{-8, -3}},
+		{"Pow (2)^(2,1)", Pow, tensor.Shape{2}, tensor.Shape{2, 1}, []float64{2, 3}, []float64{1, 2},
+			tensor.Shape{2, 2}, []float64{2, 3, 4, 9}, []float64{1 + 4, 1 + 6}, []float64{2*math.Log(2) + 3*math.Log(3), 4*math.Log(2) + 9*math.Log(3)}},
+		{"Pow (2)^(3,1)", Pow, tensor.Shape{2}, tensor.Shape{3, 1}, []float64{2, 3}, []float64{1, 2, 3},
+			tensor.Shape{3, 2}, []float64{2, 3, 4, 9, 8, 27}, []float64{1 + 4 + 12, 1 + 6 + 27},
+			[]float64{2*math.Log(2) + 3*math.Log(3), 4*math.Log(2) + 9*math.Log(3), 8*math.Log(2) + 27*math.Log(3)}},
+	}
+
+	for _, bc := range bcs {
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			a := NewTensor(g, Float64, bc.aShape.Dims(), WithShape(bc.aShape...), WithName("a"), WithValue(tensor.New(tensor.WithShape(bc.aShape...), tensor.WithBacking(bc.aBack))))
+			b := NewTensor(g, Float64, bc.bShape.Dims(), WithShape(bc.bShape...), WithName("b"), WithValue(tensor.New(tensor.WithShape(bc.bShape...), tensor.WithBacking(bc.bBack))))
+			z, err := bc.fn(a, b)
+			if err != nil {
+				t.Fatalf("%v: %+v", bc.name, err)
+			}
+			assert.Equal(bc.correctShape, z.Shape(), bc.name)
+			cost := Must(Sum(z))
+
+			// the tape machine may reuse the register of z when computing the gradients, so its value is read as it is computed
+			var zVal Value
+			Read(z, &zVal)
+
+			var m VM
+			if i == 0 {
+				if _, err = Grad(cost, a, b); err != nil {
+					t.Fatalf("%v: %+v", bc.name, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", bc.name, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err = m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", bc.name, err)
+			}
+
+			assert.InDeltaSlice(bc.correct, extractF64s(zVal), 1e-10, bc.name)
+
+			var ga, gb Value
+			if ga, err = a.Grad(); err != nil {
+				t.Fatalf("%v: %+v", bc.name, err)
+			}
+			if gb, err = b.Grad(); err != nil {
+				t.Fatalf("%v: %+v", bc.name, err)
+			}
+			assert.Equal(bc.aShape, ga.Shape(), bc.name)
+			assert.Equal(bc.bShape, gb.Shape(), bc.name)
+			assert.InDeltaSlice(bc.correctGradA, extractF64s(ga), 1e-10, bc.name)
+			assert.InDeltaSlice(bc.correctGradB, extractF64s(gb), 1e-10, bc.name)
+		}
+	}
+
+	// comparisons
+	g := NewGraph()
+	a := NewMatrix(g, Float64, WithShape(2, 3), WithName("a"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{0, 1, 2, 3, 4, 5}))))
+	b := NewVector(g, Float64, WithShape(3), WithName("b"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{1, 1, 4}))))
+	gt := Must(Gt(a, b, false))
+	eq := Must(Eq(b, a, true))
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]bool{false, false, false, true, true, true}, gt.Value().Data())
+	assert.Equal([]float64{0, 1, 0, 0, 0, 0}, extractF64s(eq.Value()))
+
+	// incompatible shapes
+	c := NewVector(g, Float64, WithShape(2), WithName("c"))
+	if _, err := Add(a, c); err == nil {
+		t.Error("Expected an error when adding shapes (2, 3) and (2)")
+	}
+}
+
+// TestAutoBroadcast_VectorShapes checks that vectors are broadcast against column vectors like NumPy does,
+// and that nodes without a shape are not broadcast
+func TestAutoBroadcast_VectorShapes(t *testing.T) {
+	assert := assert.New(t)
+
+	// a vector and a column vector of the same length are broadcast to a square matrix
+	g := NewGraph()
+	a := NewVector(g, Float64, WithShape(3), WithName("a"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{1, 2, 3}))))
+	b := NewMatrix(g, Float64, WithShape(3, 1), WithName("b"), WithValue(tensor.New(tensor.WithShape(3, 1), tensor.WithBacking([]float64{10, 20, 30}))))
+	z := Must(Add(a, b))
+	assert.Equal(tensor.Shape{3, 3}, z.Shape())
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]float64{11, 12, 13, 21, 22, 23, 31, 32, 33}, extractF64s(z.Value()))
+
+	// a node built without a shape is not broadcast
+	g = NewGraph()
+	x := NewMatrix(g, Float64, WithName("x"))
+	y := NewMatrix(g, Float64, WithShape(2, 2), WithName("y"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float64{2, 2, 2, 2}))))
+	z = Must(HadamardProd(x, y))
+	for _, n := range z.children {
+		if _, ok := n.op.(broadcastOp); ok {
+			t.Errorf("Expected no broadcast of a node without a shape")
+		}
+	}
+
+	Let(x, tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float64{1, 2, 3, 4})))
+	m = NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]float64{2, 4, 6, 8}, extractF64s(z.Value()))
+}
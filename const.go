@@ -60,6 +60,8 @@ const (
 	addFail             = "Failed to carry Add()"
 	signFail            = "Failed to carry Sign()"
 	softplusFail        = "Failed to carry Softplus()"
+	gatherRowsFail      = "Failed to gather the rows of a row sparse gradient"
+	scatterRowsFail     = "Failed to scatter the rows of a row sparse update"
 	incrErr             = "increment couldn't be done. Safe op was performed instead"
 	bindFail            = "Failed to bind"
 	anyToValueFail      = "Failed to convert %v(%T) into a Value"
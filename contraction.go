@@ -0,0 +1,301 @@
+package gorgonia
+
+import (
+	"strings"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/pkg/errors"
+)
+
+// TensorDot contracts a and b along the given axes: axesA[i] of a is paired with axesB[i] of b, and the products are summed along the pairs.
+// The result has the remaining axes of a followed by the remaining axes of b, like Numpy's tensordot.
+//
+// The contraction is lowered into transposes, reshapes and a batched matrix multiplication, so the gradients come from the transposed contractions.
+func TensorDot(a, b *Node, axesA, axesB []int) (retVal *Node, err error) {
+	if len(axesA) != len(axesB) {
+		return nil, errors.Errorf("TensorDot expects the same number of axes for both nodes. Got %v and %v", axesA, axesB)
+	}
+
+	var freeA, freeB []int
+	if freeA, err = remainingAxes(a, axesA); err != nil {
+		return nil, err
+	}
+	if freeB, err = remainingAxes(b, axesB); err != nil {
+		return nil, err
+	}
+	for i := range axesA {
+		if a.shape[axesA[i]] != b.shape[axesB[i]] {
+			return nil, errors.Errorf("Cannot contract axis %d of %v with axis %d of %v", axesA[i], a.shape, axesB[i], b.shape)
+		}
+	}
+	return contract(a, b, nil, freeA, axesA, nil, freeB, axesB)
+}
+
+// Einsum evaluates the Einstein summation convention on the nodes, following a spec such as "bij,bjk->bik".
+// Each operand has one letter per axis. Letters that are not in the output are summed over. If the spec has no "->",
+// the output is made of the letters that appear only once, in alphabetical order.
+// Repeated letters within an operand (i.e. diagonals) and ellipses are not supported.
+//
+// The operands are contracted pairwise from left to right, with each contraction lowered into transposes, reshapes and a batched matrix multiplication.
+func Einsum(spec string, nodes ...*Node) (retVal *Node, err error) {
+	var subs []string
+	var out string
+	if subs, out, err = parseEinsum(spec, nodes); err != nil {
+		return nil, err
+	}
+
+	cur, curSub := nodes[0], subs[0]
+	for i := 1; i < len(nodes); i++ {
+		needed := out + strings.Join(subs[i+1:], "")
+		next, nextSub := nodes[i], subs[i]
+
+		// sum the letters that are in neither the other operand nor what's needed later
+		if cur, curSub, err = sumLetters(cur, curSub, nextSub+needed); err != nil {
+			return nil, err
+		}
+		if next, nextSub, err = sumLetters(next, nextSub, curSub+needed); err != nil {
+			return nil, err
+		}
+
+		if cur, curSub, err = contractLetters(cur, next, curSub, nextSub, needed); err != nil {
+			return nil, err
+		}
+	}
+
+	if cur, curSub, err = sumLetters(cur, curSub, out); err != nil {
+		return nil, err
+	}
+	if len(out) < 2 {
+		return cur, nil
+	}
+	pattern := make([]int, len(out))
+	for i, l := range out {
+		pattern[i] = strings.IndexRune(curSub, l)
+	}
+	return Transpose(cur, pattern...)
+}
+
+// parseEinsum parses and checks an einsum spec, returning the subscripts of each operand and the output.
+func parseEinsum(spec string, nodes []*Node) (subs []string, out string, err error) {
+	spec = strings.Replace(spec, " ", "", -1)
+	in := spec
+	explicit := strings.Contains(spec, "->")
+	if explicit {
+		parts := strings.Split(spec, "->")
+		if len(parts) != 2 {
+			return nil, "", errors.Errorf("Invalid einsum spec %q", spec)
+		}
+		in, out = parts[0], parts[1]
+	}
+
+	subs = strings.Split(in, ",")
+	if len(subs) != len(nodes) || len(nodes) == 0 {
+		return nil, "", errors.Errorf("Einsum spec %q expects %d operands. Got %d instead", spec, len(subs), len(nodes))
+	}
+
+	sizes := make(map[rune]int)
+	counts := make(map[rune]int)
+	for i, sub := range subs {
+		if len(sub) != nodes[i].Dims() {
+			return nil, "", errors.Errorf("Einsum spec %q has %d subscripts for operand %d, which has shape %v", spec, len(sub), i, nodes[i].shape)
+		}
+		for j, l := range sub {
+			if !isEinsumLetter(l) {
+				return nil, "", errors.Errorf("Invalid subscript %q in einsum spec %q", l, spec)
+			}
+			if strings.IndexRune(sub, l) != j {
+				return nil, "", errors.Errorf(nyiFail, "Einsum with repeated subscripts in an operand", spec)
+			}
+			if s, ok := sizes[l]; ok && s != nodes[i].shape[j] {
+				return nil, "", errors.Errorf("Subscript %q of einsum spec %q has sizes %d and %d", l, spec, s, nodes[i].shape[j])
+			}
+			sizes[l] = nodes[i].shape[j]
+			counts[l]++
+		}
+	}
+
+	if !explicit {
+		for l := 'A'; l <= 'z'; l++ {
+			if counts[l] == 1 {
+				out += string(l)
+			}
+		}
+		return subs, out, nil
+	}
+
+	for i, l := range out {
+		if _, ok := sizes[l]; !ok {
+			return nil, "", errors.Errorf("Output subscript %q of einsum spec %q is not in any of the operands", l, spec)
+		}
+		if strings.IndexRune(out, l) != i {
+			return nil, "", errors.Errorf("Output subscript %q of einsum spec %q is repeated", l, spec)
+		}
+	}
+	return subs, out, nil
+}
+
+func isEinsumLetter(l rune) bool { return (l >= 'a' && l <= 'z') || (l >= 'A' && l <= 'Z') }
+
+// sumLetters sums n along the axes whose letters in sub are not in keep.
+func sumLetters(n *Node, sub, keep string) (retVal *Node, retSub string, err error) {
+	var along []int
+	for i, l := range sub {
+		if strings.ContainsRune(keep, l) {
+			retSub += string(l)
+			continue
+		}
+		along = append(along, i)
+	}
+	switch {
+	case len(along) == 0:
+		return n, sub, nil
+	case len(retSub) == 0:
+		if retVal, err = Sum(n); err != nil {
+			return nil, "", errors.Wrap(err, operationError)
+		}
+		return retVal, retSub, nil
+	}
+
+	// the summed axes are moved to the end and flattened, so that a single axis of a matrix is summed
+	var kept []int
+	for i := range sub {
+		if strings.ContainsRune(retSub, rune(sub[i])) {
+			kept = append(kept, i)
+		}
+	}
+	shape := make(tensor.Shape, len(kept))
+	for i, a := range kept {
+		shape[i] = n.shape[a]
+	}
+
+	if retVal, err = transposeAxes(n, kept, along); err != nil {
+		return nil, "", err
+	}
+	if retVal, err = reshape(retVal, tensor.Shape{axesSize(n, kept), axesSize(n, along)}); err != nil {
+		return nil, "", errors.Wrap(err, operationError)
+	}
+	if retVal, err = Sum(retVal, 1); err != nil {
+		return nil, "", errors.Wrap(err, operationError)
+	}
+	if retVal, err = reshape(retVal, shape); err != nil {
+		return nil, "", errors.Wrap(err, operationError)
+	}
+	return retVal, retSub, nil
+}
+
+// contractLetters contracts a and b, whose axes are labelled by subA and subB. Shared letters that are needed later are batch axes,
+// and the other shared letters are summed over. The letters of the result are the batch letters, then the letters of a, then the letters of b.
+func contractLetters(a, b *Node, subA, subB, needed string) (retVal *Node, sub string, err error) {
+	if a.IsScalar() || b.IsScalar() {
+		if retVal, err = HadamardProd(a, b); err != nil {
+			return nil, "", errors.Wrap(err, operationError)
+		}
+		return retVal, subA + subB, nil
+	}
+
+	var batchA, freeA, contractA, batchB, freeB, contractB []int
+	var batch, free string
+	for i, l := range subA {
+		j := strings.IndexRune(subB, l)
+		switch {
+		case j < 0:
+			freeA = append(freeA, i)
+			free += string(l)
+		case strings.ContainsRune(needed, l):
+			batchA = append(batchA, i)
+			batchB = append(batchB, j)
+			batch += string(l)
+		default:
+			contractA = append(contractA, i)
+			contractB = append(contractB, j)
+		}
+	}
+	for i, l := range subB {
+		if !strings.ContainsRune(subA, l) {
+			freeB = append(freeB, i)
+			free += string(l)
+		}
+	}
+
+	if retVal, err = contract(a, b, batchA, freeA, contractA, batchB, freeB, contractB); err != nil {
+		return nil, "", err
+	}
+	return retVal, batch + free, nil
+}
+
+// contract lowers a contraction into transposes, reshapes and a batched matrix multiplication.
+// The batch and contracted axes of a are paired with the ones of b. The result has the batch axes, then the free axes of a, then the free axes of b.
+func contract(a, b *Node, batchA, freeA, contractA, batchB, freeB, contractB []int) (retVal *Node, err error) {
+	batch, m, k := axesSize(a, batchA), axesSize(a, freeA), axesSize(a, contractA)
+	n := axesSize(b, freeB)
+
+	var x, y *Node
+	if x, err = transposeAxes(a, batchA, freeA, contractA); err != nil {
+		return nil, err
+	}
+	if y, err = transposeAxes(b, batchB, contractB, freeB); err != nil {
+		return nil, err
+	}
+
+	// a contraction without batch axes is done as a batch of 1, as batched matrix multiplications don't have to deal with the vector shapes
+	// that the free or contracted axes may reshape into
+	if x, err = reshape(x, tensor.Shape{batch, m, k}); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if y, err = reshape(y, tensor.Shape{batch, k, n}); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if retVal, err = BatchedMatMul(x, y, false, false); err != nil {
+		return nil, err
+	}
+
+	shape := make(tensor.Shape, 0, len(batchA)+len(freeA)+len(freeB))
+	for _, ax := range batchA {
+		shape = append(shape, a.shape[ax])
+	}
+	for _, ax := range freeA {
+		shape = append(shape, a.shape[ax])
+	}
+	for _, ax := range freeB {
+		shape = append(shape, b.shape[ax])
+	}
+	return reshape(retVal, shape)
+}
+
+// transposeAxes transposes n so that its axes are in the order of the given groups of axes.
+func transposeAxes(n *Node, groups ...[]int) (*Node, error) {
+	var pattern []int
+	for _, g := range groups {
+		pattern = append(pattern, g...)
+	}
+	if len(pattern) < 2 {
+		return n, nil
+	}
+	return Transpose(n, pattern...)
+}
+
+// axesSize is the product of the sizes of the given axes of n.
+func axesSize(n *Node, along []int) int {
+	size := 1
+	for _, a := range along {
+		size *= n.shape[a]
+	}
+	return size
+}
+
+// remainingAxes checks that the given axes of n are valid and unique, and returns the other axes of n in order.
+func remainingAxes(n *Node, along []int) (retVal []int, err error) {
+	seen := make([]bool, n.Dims())
+	for _, a := range along {
+		if a < 0 || a >= len(seen) || seen[a] {
+			return nil, errors.Errorf("Invalid axes %v for a node of shape %v", along, n.shape)
+		}
+		seen[a] = true
+	}
+	for i, s := range seen {
+		if !s {
+			retVal = append(retVal, i)
+		}
+	}
+	return
+}
@@ -0,0 +1,225 @@
+package gorgonia
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveEinsum is the reference for Einsum. It loops over every combination of the letters.
+func naiveEinsum(subs []string, out string, shapes []tensor.Shape, data [][]float64) []float64 {
+	sizes := make(map[rune]int)
+	var letters []rune
+	for i, sub := range subs {
+		for j, l := range sub {
+			if _, ok := sizes[l]; !ok {
+				letters = append(letters, l)
+			}
+			sizes[l] = shapes[i][j]
+		}
+	}
+
+	index := func(sub string, shape []int, at map[rune]int) (retVal int) {
+		for j, l := range sub {
+			retVal = retVal*shape[j] + at[l]
+		}
+		return
+	}
+	outShape := make([]int, len(out))
+	outSize := 1
+	for i, l := range out {
+		outShape[i] = sizes[l]
+		outSize *= sizes[l]
+	}
+
+	retVal := make([]float64, outSize)
+	at := make(map[rune]int)
+	var loop func(int)
+	loop = func(d int) {
+		if d == len(letters) {
+			prod := 1.0
+			for i, sub := range subs {
+				prod *= data[i][index(sub, shapes[i], at)]
+			}
+			retVal[index(out, outShape, at)] += prod
+			return
+		}
+		for i := 0; i < sizes[letters[d]]; i++ {
+			at[letters[d]] = i
+			loop(d + 1)
+		}
+	}
+	loop(0)
+	return retVal
+}
+
+var einsumTests = []struct {
+	spec   string
+	subs   []string
+	out    string
+	shapes []tensor.Shape
+}{
+	{"bij,bjk->bik", []string{"bij", "bjk"}, "bik", []tensor.Shape{{2, 3, 4}, {2, 4, 5}}},
+	{"ij,jk", []string{"ij", "jk"}, "ik", []tensor.Shape{{3, 4}, {4, 2}}},
+	{"ij->ji", []string{"ij"}, "ji", []tensor.Shape{{3, 4}}},
+	{"ij->", []string{"ij"}, "", []tensor.Shape{{3, 4}}},
+	{"ij,j->i", []string{"ij", "j"}, "i", []tensor.Shape{{3, 4}, {4}}},
+	{"i,j->ij", []string{"i", "j"}, "ij", []tensor.Shape{{3}, {4}}},
+	{"i,i", []string{"i", "i"}, "", []tensor.Shape{{4}, {4}}},
+	{"ij,jk,kl->il", []string{"ij", "jk", "kl"}, "il", []tensor.Shape{{2, 3}, {3, 4}, {4, 2}}},
+	{"bhqd,bhkd->bhqk", []string{"bhqd", "bhkd"}, "bhqk", []tensor.Shape{{2, 2, 3, 4}, {2, 2, 5, 4}}},
+	{"ijk,kj->ki", []string{"ijk", "kj"}, "ki", []tensor.Shape{{2, 3, 4}, {4, 3}}},
+	{"abc,cd->", []string{"abc", "cd"}, "", []tensor.Shape{{2, 3, 4}, {4, 2}}},
+}
+
+func TestEinsum(t *testing.T) {
+	assert := assert.New(t)
+	for _, et := range einsumTests {
+		data := make([][]float64, len(et.shapes))
+		for i, s := range et.shapes {
+			data[i] = make([]float64, s.TotalSize())
+			for j := range data[i] {
+				data[i][j] = float64((j*7+i*3)%11) - 5
+			}
+		}
+		correct := naiveEinsum(et.subs, et.out, et.shapes, data)
+
+		// the cost is Σ z ⊙ c, which is multilinear in the operands, so central differences give the exact gradients
+		c := make([]float64, len(correct))
+		for i := range c {
+			c[i] = float64(i%5) - 2
+		}
+		cost := func() (retVal float64) {
+			for i, v := range naiveEinsum(et.subs, et.out, et.shapes, data) {
+				retVal += v * c[i]
+			}
+			return
+		}
+		correctGrads := make([][]float64, len(data))
+		for i := range data {
+			correctGrads[i] = make([]float64, len(data[i]))
+			for j := range data[i] {
+				orig := data[i][j]
+				data[i][j] = orig + 1
+				fp := cost()
+				data[i][j] = orig - 1
+				fm := cost()
+				data[i][j] = orig
+				correctGrads[i][j] = (fp - fm) / 2
+			}
+		}
+
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			nodes := make(Nodes, len(et.shapes))
+			for j, s := range et.shapes {
+				nodes[j] = NewTensor(g, Float64, s.Dims(), WithShape(s...), WithName(fmt.Sprintf("x%d", j)), WithValue(tensor.New(tensor.WithShape(s...), tensor.WithBacking(data[j]))))
+			}
+			z, err := Einsum(et.spec, nodes...)
+			if err != nil {
+				t.Fatalf("%v: %+v", et.spec, err)
+			}
+
+			var sum *Node
+			if len(et.out) == 0 {
+				assert.True(z.IsScalar(), et.spec)
+				sum = Must(HadamardProd(z, NewConstant(c[0])))
+			} else {
+				outShape := make(tensor.Shape, len(et.out))
+				for j, l := range et.out {
+					for k, sub := range et.subs {
+						if p := strings.IndexRune(sub, l); p >= 0 {
+							outShape[j] = et.shapes[k][p]
+						}
+					}
+				}
+				assert.Equal(outShape, z.Shape(), et.spec)
+				cT := tensor.New(tensor.WithShape(outShape...), tensor.WithBacking(c))
+				sum = Must(Sum(Must(HadamardProd(z, NewConstant(cT)))))
+			}
+
+			var m VM
+			if i == 0 {
+				if _, err = Grad(sum, nodes...); err != nil {
+					t.Fatalf("%v: %+v", et.spec, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", et.spec, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err = m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", et.spec, err)
+			}
+
+			if len(et.out) == 0 {
+				assert.InDelta(correct[0], extractF64(z.Value()), 1e-10, et.spec)
+			} else {
+				// z may be a lazily transposed tensor
+				assert.InDeltaSlice(correct, extractF64s(z.Value().(tensor.Tensor).Materialize()), 1e-10, et.spec)
+			}
+			for j, n := range nodes {
+				grad, err := n.Grad()
+				if err != nil {
+					t.Fatalf("%v: %+v", et.spec, err)
+				}
+				assert.Equal(et.shapes[j], grad.Shape(), "%v: operand %d", et.spec, j)
+				assert.InDeltaSlice(correctGrads[j], extractF64s(grad), 1e-10, "%v: operand %d", et.spec, j)
+			}
+		}
+	}
+
+	g := NewGraph()
+	a := NewMatrix(g, Float64, WithShape(2, 3), WithName("a"))
+	b := NewMatrix(g, Float64, WithShape(4, 3), WithName("b"))
+	bad := []string{"ij,jk->ik", "ij->k", "ii", "ijk", "ij,kj->ii", "i...->i"}
+	for _, spec := range bad {
+		var err error
+		if strings.Count(spec, ",") == 1 {
+			_, err = Einsum(spec, a, b)
+		} else {
+			_, err = Einsum(spec, a)
+		}
+		assert.Error(err, spec)
+	}
+}
+
+func TestTensorDot(t *testing.T) {
+	assert := assert.New(t)
+	aBack := tensor.Range(tensor.Float64, 0, 24).([]float64)
+	bBack := tensor.Range(tensor.Float64, 0, 60).([]float64)
+	correct := naiveEinsum([]string{"ijk", "kjl"}, "il", []tensor.Shape{{2, 3, 4}, {4, 3, 5}}, [][]float64{aBack, bBack})
+
+	g := NewGraph()
+	a := NewTensor(g, Float64, 3, WithShape(2, 3, 4), WithName("a"), WithValue(tensor.New(tensor.WithShape(2, 3, 4), tensor.WithBacking(aBack))))
+	b := NewTensor(g, Float64, 3, WithShape(4, 3, 5), WithName("b"), WithValue(tensor.New(tensor.WithShape(4, 3, 5), tensor.WithBacking(bBack))))
+	z := Must(TensorDot(a, b, []int{1, 2}, []int{1, 0}))
+	assert.Equal(tensor.Shape{2, 5}, z.Shape())
+
+	// a single pair of axes
+	outer := Must(TensorDot(a, b, []int{2}, []int{0}))
+	assert.Equal(tensor.Shape{2, 3, 3, 5}, outer.Shape())
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal(correct, extractF64s(z.Value()))
+	assert.Equal(naiveEinsum([]string{"ijk", "klm"}, "ijlm", []tensor.Shape{{2, 3, 4}, {4, 3, 5}}, [][]float64{aBack, bBack}), extractF64s(outer.Value()))
+
+	if _, err := TensorDot(a, b, []int{1}, []int{0}); err == nil {
+		t.Error("Expected an error when contracting axes of different sizes")
+	}
+	if _, err := TensorDot(a, b, []int{1, 1}, []int{1, 0}); err == nil {
+		t.Error("Expected an error for repeated axes")
+	}
+	if _, err := TensorDot(a, b, []int{1}, []int{1, 0}); err == nil {
+		t.Error("Expected an error for mismatched numbers of axes")
+	}
+}
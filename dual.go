@@ -99,6 +99,12 @@ func constantDV(val Value) *dualValue {
 	retVal := borrowDV()
 	retVal.Value = val
 
+	// the derivative of a lazily transposed or sliced tensor is not a view, as the ops that increment derivatives do not handle views
+	if t, ok := val.(*tensor.Dense); ok && t.IsMaterializable() {
+		retVal.d = tensor.New(tensor.Of(t.Dtype()), tensor.WithShape(t.Shape().Clone()...))
+		return retVal
+	}
+
 	var err error
 	if retVal.d, err = CloneValue(val); err != nil {
 		panic(err)
@@ -112,8 +112,11 @@ func NewConstant(v interface{}, opts ...NodeConsOpt) *Node {
 // UniformRandomNode creates an input node that has a random op so everytime the node is passed, random values will be plucked from
 // a uniform distribution. The type of the node depends on the
 // shape passed in. To get a scalar value at run time, don't pass in any shapes
+//
+// The values are drawn from the RNG of the graph (see WithGraphSeed), in both training and evaluation mode, unless the node is
+// marked with MeanInEvalMode.
 func UniformRandomNode(g *ExprGraph, dt tensor.Dtype, low, high float64, shape ...int) *Node {
-	op := makeRandomOp(uniform, dt, low, high, shape...)
+	op := makeRandomOp(uniform, dt, low, high, g.RNG(), shape...)
 	s := tensor.Shape(shape)
 
 	var t hm.Type
@@ -130,8 +133,11 @@ func UniformRandomNode(g *ExprGraph, dt tensor.Dtype, low, high float64, shape .
 // GaussianRandomNode creates an input node that has a random op so everytime the node is passed, random values will be plucked from
 // a gaussian distribution with the mean and stdev provided. The type of the node depends on the
 // shape passed in. To get a scalar value at run time, don't pass in any shapes
+//
+// The values are drawn from the RNG of the graph (see WithGraphSeed), in both training and evaluation mode, unless the node is
+// marked with MeanInEvalMode.
 func GaussianRandomNode(g *ExprGraph, dt tensor.Dtype, mean, stdev float64, shape ...int) *Node {
-	op := makeRandomOp(gaussian, dt, mean, stdev, shape...)
+	op := makeRandomOp(gaussian, dt, mean, stdev, g.RNG(), shape...)
 	s := tensor.Shape(shape)
 
 	var t hm.Type
@@ -151,8 +157,11 @@ func GaussianRandomNode(g *ExprGraph, dt tensor.Dtype, mean, stdev float64, shap
 //
 // Whilst technically the number of trials of a binomal distribution should be a discrete value (you can't have half a trial), to keep with
 // API uniformity, trials is passed in as a float64, but will be truncated to an int at runtime.
+//
+// The values are drawn from the RNG of the graph (see WithGraphSeed), in both training and evaluation mode, unless the node is
+// marked with MeanInEvalMode.
 func BinomialRandomNode(g *ExprGraph, dt tensor.Dtype, trials, prob float64, shape ...int) *Node {
-	op := makeRandomOp(binomial, dt, trials, prob, shape...)
+	op := makeRandomOp(binomial, dt, trials, prob, g.RNG(), shape...)
 	s := tensor.Shape(shape)
 
 	var t hm.Type
@@ -3,6 +3,7 @@ package gorgonia
 import (
 	"bytes"
 	"fmt"
+	"time"
 	"unsafe"
 
 	"github.com/awalterschulze/gographviz"
@@ -23,6 +24,8 @@ type ExprGraph struct {
 	leaves    Nodes
 	constants Nodes
 	roots     Nodes
+
+	rng *RNG
 }
 
 type graphconopt func(g *ExprGraph)
@@ -44,6 +47,8 @@ func NewGraph(opts ...graphconopt) *ExprGraph {
 
 		leaves:    make(Nodes, 0),
 		constants: make(Nodes, 0),
+
+		rng: NewRNG(time.Now().UnixNano()),
 	}
 
 	for _, opt := range opts {
@@ -493,6 +498,8 @@ func (g *ExprGraph) subgraph(ns Nodes, opts ...Nodes) *ExprGraph {
 		leaves:    g.leaves,
 		constants: g.constants,
 		roots:     roots,
+
+		rng: g.rng,
 	}
 
 	return retVal
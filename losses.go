@@ -0,0 +1,416 @@
+package gorgonia
+
+import (
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/pkg/errors"
+)
+
+// This file provides the loss functions. Every loss function computes the loss of each sample of a batch, where the samples are
+// along the first axis, and then reduces them as specified by the LossOpts. By default, the mean of the losses is returned.
+
+// Reduction is the way a loss function reduces the losses of the samples of a batch.
+type Reduction byte
+
+const (
+	// MeanReduction averages the losses of the samples. When there are sample weights, it is the weighted average Σ w·l / Σ w.
+	MeanReduction Reduction = iota
+	// SumReduction sums the (weighted) losses of the samples.
+	SumReduction
+	// NoReduction returns the (weighted) loss of each sample, as a vector.
+	NoReduction
+)
+
+// cosineEps keeps the cosine similarity finite when one of the vectors is zero.
+const cosineEps = 1e-12
+
+type lossConfig struct {
+	reduction Reduction
+	weights   *Node
+}
+
+// LossOpt is an option of a loss function.
+type LossOpt func(c *lossConfig)
+
+// WithReduction is a LossOpt that sets how the losses of the samples are reduced. The default is MeanReduction.
+func WithReduction(r Reduction) LossOpt {
+	f := func(c *lossConfig) {
+		c.reduction = r
+	}
+	return f
+}
+
+// WithSampleWeights is a LossOpt that weighs the loss of each sample. weights has to be a vector with one weight per sample.
+// The weights are not expected to be differentiated.
+func WithSampleWeights(weights *Node) LossOpt {
+	f := func(c *lossConfig) {
+		c.weights = weights
+	}
+	return f
+}
+
+// MSE computes the mean squared error between output and target, which have to be of the same shape:
+//		(output - target)²
+// The loss of a sample is the mean over all the axes but the first.
+func MSE(output, target *Node, opts ...LossOpt) (retVal *Node, err error) {
+	var diff *Node
+	if diff, err = lossDiff("MSE", output, target); err != nil {
+		return
+	}
+	if retVal, err = Square(diff); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return reduceLoss("MSE", retVal, opts)
+}
+
+// MAE computes the mean absolute error between output and target, which have to be of the same shape:
+//		|output - target|
+// The loss of a sample is the mean over all the axes but the first.
+func MAE(output, target *Node, opts ...LossOpt) (retVal *Node, err error) {
+	var diff *Node
+	if diff, err = lossDiff("MAE", output, target); err != nil {
+		return
+	}
+	if retVal, err = Abs(diff); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return reduceLoss("MAE", retVal, opts)
+}
+
+// Huber computes the Huber loss between output and target, which have to be of the same shape. With d = |output - target|,
+//		0.5·d²             if d <= delta
+//		delta·(d - 0.5·delta) otherwise
+// It is computed as 0.5·q² + delta·(d - q), where q is d clamped to [0, delta], so the gradient is continuous.
+// The loss of a sample is the mean over all the axes but the first.
+func Huber(output, target *Node, delta float64, opts ...LossOpt) (retVal *Node, err error) {
+	if delta <= 0 {
+		return nil, errors.Errorf("Huber expects a positive delta. Got %v instead", delta)
+	}
+	var dt tensor.Dtype
+	if dt, err = checkLossInputs("Huber", output, target); err != nil {
+		return
+	}
+	var diff, d, q, quad, lin *Node
+	if diff, err = Sub(output, target); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if d, err = Abs(diff); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if q, err = Clamp(d, 0.0, delta); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if quad, err = Square(q); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if quad, err = HadamardProd(lossConstant(dt, 0.5), quad); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if lin, err = Sub(d, q); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if lin, err = HadamardProd(lossConstant(dt, delta), lin); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if retVal, err = Add(quad, lin); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return reduceLoss("Huber", retVal, opts)
+}
+
+// Hinge computes the hinge loss of output, given targets of -1 or 1 of the same shape:
+//		max(0, 1 - output·target)
+// The loss of a sample is the mean over all the axes but the first.
+func Hinge(output, target *Node, opts ...LossOpt) (retVal *Node, err error) {
+	if retVal, err = hinge("Hinge", output, target); err != nil {
+		return
+	}
+	return reduceLoss("Hinge", retVal, opts)
+}
+
+// SquaredHinge computes the squared hinge loss of output, given targets of -1 or 1 of the same shape:
+//		max(0, 1 - output·target)²
+// The loss of a sample is the mean over all the axes but the first.
+func SquaredHinge(output, target *Node, opts ...LossOpt) (retVal *Node, err error) {
+	if retVal, err = hinge("SquaredHinge", output, target); err != nil {
+		return
+	}
+	if retVal, err = Square(retVal); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return reduceLoss("SquaredHinge", retVal, opts)
+}
+
+func hinge(fn string, output, target *Node) (retVal *Node, err error) {
+	var dt tensor.Dtype
+	if dt, err = checkLossInputs(fn, output, target); err != nil {
+		return
+	}
+	if retVal, err = HadamardProd(output, target); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if retVal, err = Sub(lossConstant(dt, 1), retVal); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return Rectify(retVal)
+}
+
+// KLDivergence computes the Kullback-Leibler divergence of the softmax of logits from targets, which are probability distributions:
+//		Σ t·(log(t) - log(softmax(logits)))
+// The classes are on axis 1 of logits, which is a (batch, classes, ...) tensor. The targets are either of the same shape, or
+// Int classes of the shape of logits without the axis, in which case the loss is the same as CategoricalCrossEntropy.
+//
+// It is computed from the logits with the log-sum-exp trick, so confident predictions do not produce NaNs.
+// The loss of a sample is the mean over all the axes but the first and the class axis. The targets are not differentiable.
+func KLDivergence(logits, targets *Node, opts ...LossOpt) (retVal *Node, err error) {
+	if err = checkClassInputs("KLDivergence", logits); err != nil {
+		return
+	}
+	if retVal, err = softmaxXent("KLDivergence", logits, targets, 1, 0, true); err != nil {
+		return
+	}
+	return reduceLoss("KLDivergence", retVal, opts)
+}
+
+// CategoricalCrossEntropy computes the cross entropy between the softmax of logits and targets. It is SoftmaxCrossEntropy with
+// the classes on axis 1 of logits, which is a (batch, classes, ...) tensor. The targets are either of the same shape (one-hot
+// or any other distribution over the classes), or Int classes of the shape of logits without the axis.
+//
+// The loss of a sample is the mean over all the axes but the first and the class axis. The targets are not differentiable.
+func CategoricalCrossEntropy(logits, targets *Node, opts ...LossOpt) (retVal *Node, err error) {
+	if err = checkClassInputs("CategoricalCrossEntropy", logits); err != nil {
+		return
+	}
+	if retVal, err = softmaxXent("CategoricalCrossEntropy", logits, targets, 1, 0, false); err != nil {
+		return
+	}
+	return reduceLoss("CategoricalCrossEntropy", retVal, opts)
+}
+
+// NLLLoss computes the negative log likelihood of targets, given log probabilities:
+//		-Σ t·logProbs
+// The classes are on axis 1 of logProbs, which is a (batch, classes, ...) tensor. The targets are either of the same shape, or
+// Int classes of the shape of logProbs without the axis. Classes that are not targets do not contribute to the loss, even if
+// their log probability is -Inf.
+//
+// The loss of a sample is the mean over all the axes but the first and the class axis. The targets are not differentiable.
+func NLLLoss(logProbs, targets *Node, opts ...LossOpt) (retVal *Node, err error) {
+	if err = checkClassInputs("NLLLoss", logProbs); err != nil {
+		return
+	}
+
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(targets.t); err != nil {
+		return nil, errors.Wrap(err, dtypeOfFail)
+	}
+	op := nllOp{softmaxXentOp{
+		axis:       1,
+		dims:       logProbs.Dims(),
+		intTargets: dt == Int,
+	}}
+	if retVal, err = applyOp(op, logProbs, targets); err != nil {
+		return
+	}
+	return reduceLoss("NLLLoss", retVal, opts)
+}
+
+// CosineEmbeddingLoss computes the loss of the cosine similarity of the rows of a and b, which are (batch, features) matrices,
+// given a vector y of labels that are 1 when the rows should be similar and -1 when they should not:
+//		1 - cos(a, b)              if y = 1
+//		max(0, cos(a, b) - margin) if y = -1
+// The norms are kept away from zero by a small epsilon, so zero vectors do not produce NaNs.
+func CosineEmbeddingLoss(a, b, y *Node, margin float64, opts ...LossOpt) (retVal *Node, err error) {
+	var dt tensor.Dtype
+	if dt, err = checkLossInputs("CosineEmbeddingLoss", a, b); err != nil {
+		return
+	}
+	if a.Dims() != 2 {
+		return nil, errors.Errorf("CosineEmbeddingLoss expects matrices. Got %v instead", a.Shape())
+	}
+	if err = checkSampleVector("CosineEmbeddingLoss", "labels", a, y); err != nil {
+		return
+	}
+
+	var dot, aa, bb, norm, cos *Node
+	if dot, err = HadamardProd(a, b); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if dot, err = Sum(dot, 1); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if aa, err = Square(a); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if aa, err = Sum(aa, 1); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if bb, err = Square(b); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if bb, err = Sum(bb, 1); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if norm, err = HadamardProd(aa, bb); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if norm, err = Add(norm, lossConstant(dt, cosineEps)); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if norm, err = Sqrt(norm); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if cos, err = HadamardDiv(dot, norm); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+
+	// the labels select the loss: (1+y)/2 is 1 for similar pairs, and (1-y)/2 is 1 for dissimilar pairs
+	one, half := lossConstant(dt, 1), lossConstant(dt, 0.5)
+	var sim, dissim, pos, neg *Node
+	if sim, err = Sub(one, cos); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if dissim, err = Sub(cos, lossConstant(dt, margin)); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if dissim, err = Rectify(dissim); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if pos, err = Add(one, y); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if pos, err = HadamardProd(half, pos); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if neg, err = Sub(one, y); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if neg, err = HadamardProd(half, neg); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if sim, err = HadamardProd(pos, sim); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if dissim, err = HadamardProd(neg, dissim); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if retVal, err = Add(sim, dissim); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return reduceLoss("CosineEmbeddingLoss", retVal, opts)
+}
+
+// lossDiff checks the inputs of a regression loss, and returns output - target.
+func lossDiff(fn string, output, target *Node) (retVal *Node, err error) {
+	if _, err = checkLossInputs(fn, output, target); err != nil {
+		return
+	}
+	if retVal, err = Sub(output, target); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return
+}
+
+// checkLossInputs checks that output is a float tensor of at least one dimension, and that target has the same shape and Dtype.
+// It returns the Dtype.
+func checkLossInputs(fn string, output, target *Node) (dt tensor.Dtype, err error) {
+	if err = checkFloatDtype(fn, output); err != nil {
+		return
+	}
+	if output.IsScalar() {
+		return dt, errors.Errorf("%v expects a batch of at least one dimension. Got a scalar instead", fn)
+	}
+	if !sameShape(output.Shape(), target.Shape()) {
+		return dt, errors.Errorf("%v expects the target to have the shape %v. Got %v instead", fn, output.Shape(), target.Shape())
+	}
+	if err = checkSameDtype(fn, "target", output, target); err != nil {
+		return
+	}
+	return dtypeOf(output.t)
+}
+
+// checkSameDtype checks that v has the Dtype of x.
+func checkSameDtype(fn, what string, x, v *Node) error {
+	xdt, err := dtypeOf(x.t)
+	if err != nil {
+		return errors.Wrap(err, dtypeOfFail)
+	}
+	vdt, err := dtypeOf(v.t)
+	if err != nil {
+		return errors.Wrap(err, dtypeOfFail)
+	}
+	if xdt != vdt {
+		return errors.Errorf("%v expects the %v to be of %v. Got %v instead", fn, what, xdt, vdt)
+	}
+	return nil
+}
+
+// checkClassInputs checks that x is a float tensor of (batch, classes, ...).
+func checkClassInputs(fn string, x *Node) error {
+	if err := checkFloatDtype(fn, x); err != nil {
+		return err
+	}
+	if x.Dims() < 2 {
+		return errors.Errorf("%v expects a (batch, classes, ...) tensor. Got %v instead", fn, x.Shape())
+	}
+	return nil
+}
+
+// checkSampleVector checks that v is a vector of the Dtype of x, with one value per sample of x.
+func checkSampleVector(fn, what string, x, v *Node) error {
+	if !v.IsVector() || v.Shape().TotalSize() != x.Shape()[0] {
+		return errors.Errorf("%v expects the %v to be a vector of %d. Got %v instead", fn, what, x.Shape()[0], v.Shape())
+	}
+	return checkSameDtype(fn, what, x, v)
+}
+
+// reduceLoss averages the losses over all the axes but the first, to get the loss of each sample, which is then weighted and reduced.
+func reduceLoss(fn string, losses *Node, opts []LossOpt) (retVal *Node, err error) {
+	var c lossConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	retVal = losses
+	if losses.Dims() > 1 {
+		if retVal, err = Mean(losses, intRange(1, losses.Dims())...); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+	}
+
+	if c.weights != nil {
+		if err = checkSampleVector(fn, "sample weights", losses, c.weights); err != nil {
+			return nil, err
+		}
+		if retVal, err = HadamardProd(retVal, c.weights); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+	}
+
+	switch c.reduction {
+	case NoReduction:
+		return retVal, nil
+	case SumReduction:
+		return Sum(retVal)
+	case MeanReduction:
+		if c.weights == nil {
+			return Mean(retVal)
+		}
+		var total *Node
+		if retVal, err = Sum(retVal); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+		if total, err = Sum(c.weights); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+		return HadamardDiv(retVal, total)
+	default:
+		return nil, errors.Errorf("%v: unknown reduction %d", fn, c.reduction)
+	}
+}
+
+// lossConstant returns a scalar constant of the float Dtype.
+func lossConstant(dt tensor.Dtype, v float64) *Node {
+	if dt == Float32 {
+		return NewConstant(float32(v))
+	}
+	return NewConstant(v)
+}
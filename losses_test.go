@@ -0,0 +1,363 @@
+package gorgonia
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	lossOutput = []float64{0.5, -1.2, 2.0, 0.3, 1.5, 0.1, -0.7, 2.2, -0.4, 0.9, 1.1, -1.6}
+	lossTarget = []float64{0.1, -0.2, 1.4, 1.1, 0.2, 0.6, -0.3, 0.4, 0.5, -0.3, 1.3, -1.0}
+	lossSigns  = []float64{1, -1, 1, -1, 1, 1, -1, -1, -1, 1, 1, -1}
+	lossProbs  = []float64{0.1, 0.2, 0.3, 0.4, 0, 0, 1, 0, 0.25, 0.25, 0.25, 0.25}
+	lossLabels = []int{2, 0, 3}
+)
+
+// perSample applies fn to each (batch, 4) pair of values and averages the results of each sample.
+func perSample(a, b []float64, fn func(a, b float64) float64) []float64 {
+	retVal := make([]float64, len(a)/4)
+	for i := range a {
+		retVal[i/4] += fn(a[i], b[i]) / 4
+	}
+	return retVal
+}
+
+func logSoftmaxRow(x []float64) []float64 {
+	max := math.Inf(-1)
+	for _, v := range x {
+		max = math.Max(max, v)
+	}
+	var sum float64
+	for _, v := range x {
+		sum += math.Exp(v - max)
+	}
+	retVal := make([]float64, len(x))
+	for i, v := range x {
+		retVal[i] = v - max - math.Log(sum)
+	}
+	return retVal
+}
+
+var lossTests = []struct {
+	name string
+	loss func(a *Node, opts ...LossOpt) (*Node, error)
+	ref  func(a []float64) []float64
+}{
+	{"MSE",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return MSE(a, lossMatrix(a.g, "t", lossTarget), opts...)
+		},
+		func(a []float64) []float64 {
+			return perSample(a, lossTarget, func(a, b float64) float64 { return (a - b) * (a - b) })
+		}},
+	{"MAE",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return MAE(a, lossMatrix(a.g, "t", lossTarget), opts...)
+		},
+		func(a []float64) []float64 {
+			return perSample(a, lossTarget, func(a, b float64) float64 { return math.Abs(a - b) })
+		}},
+	{"Huber",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return Huber(a, lossMatrix(a.g, "t", lossTarget), 0.7, opts...)
+		},
+		func(a []float64) []float64 {
+			return perSample(a, lossTarget, func(a, b float64) float64 {
+				if d := math.Abs(a - b); d > 0.7 {
+					return 0.7 * (d - 0.35)
+				}
+				return 0.5 * (a - b) * (a - b)
+			})
+		}},
+	{"Hinge",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return Hinge(a, lossMatrix(a.g, "t", lossSigns), opts...)
+		},
+		func(a []float64) []float64 {
+			return perSample(a, lossSigns, func(a, b float64) float64 { return math.Max(0, 1-a*b) })
+		}},
+	{"SquaredHinge",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return SquaredHinge(a, lossMatrix(a.g, "t", lossSigns), opts...)
+		},
+		func(a []float64) []float64 {
+			return perSample(a, lossSigns, func(a, b float64) float64 { return math.Pow(math.Max(0, 1-a*b), 2) })
+		}},
+	{"KLDivergence",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return KLDivergence(a, lossMatrix(a.g, "t", lossProbs), opts...)
+		},
+		func(a []float64) []float64 {
+			retVal := make([]float64, 3)
+			for s := range retVal {
+				logp := logSoftmaxRow(a[s*4 : s*4+4])
+				for k, t := range lossProbs[s*4 : s*4+4] {
+					if t > 0 {
+						retVal[s] += t * (math.Log(t) - logp[k])
+					}
+				}
+			}
+			return retVal
+		}},
+	{"CategoricalCrossEntropy",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return CategoricalCrossEntropy(a, lossMatrix(a.g, "t", lossProbs), opts...)
+		},
+		func(a []float64) []float64 {
+			retVal := make([]float64, 3)
+			for s := range retVal {
+				logp := logSoftmaxRow(a[s*4 : s*4+4])
+				for k, t := range lossProbs[s*4 : s*4+4] {
+					retVal[s] -= t * logp[k]
+				}
+			}
+			return retVal
+		}},
+	{"CategoricalCrossEntropy, classes",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return CategoricalCrossEntropy(a, lossClasses(a.g), opts...)
+		},
+		func(a []float64) []float64 {
+			retVal := make([]float64, 3)
+			for s, c := range lossLabels {
+				retVal[s] = -logSoftmaxRow(a[s*4 : s*4+4])[c]
+			}
+			return retVal
+		}},
+	{"NLLLoss",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return NLLLoss(a, lossMatrix(a.g, "t", lossProbs), opts...)
+		},
+		func(a []float64) []float64 {
+			retVal := make([]float64, 3)
+			for i, t := range lossProbs {
+				retVal[i/4] -= t * a[i]
+			}
+			return retVal
+		}},
+	{"NLLLoss, classes",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			return NLLLoss(a, lossClasses(a.g), opts...)
+		},
+		func(a []float64) []float64 {
+			retVal := make([]float64, 3)
+			for s, c := range lossLabels {
+				retVal[s] = -a[s*4+c]
+			}
+			return retVal
+		}},
+	{"CosineEmbeddingLoss",
+		func(a *Node, opts ...LossOpt) (*Node, error) {
+			y := NewVector(a.g, Float64, WithShape(3), WithName("y"), WithValue(tensor.New(tensor.WithBacking([]float64{1, -1, -1}))))
+			return CosineEmbeddingLoss(a, lossMatrix(a.g, "t", lossTarget), y, 0.1, opts...)
+		},
+		func(a []float64) []float64 {
+			y := []float64{1, -1, -1}
+			retVal := make([]float64, 3)
+			for s := range retVal {
+				var dot, aa, bb float64
+				for k := s * 4; k < s*4+4; k++ {
+					dot += a[k] * lossTarget[k]
+					aa += a[k] * a[k]
+					bb += lossTarget[k] * lossTarget[k]
+				}
+				cos := dot / math.Sqrt(aa*bb)
+				if y[s] > 0 {
+					retVal[s] = 1 - cos
+				} else {
+					retVal[s] = math.Max(0, cos-0.1)
+				}
+			}
+			return retVal
+		}},
+}
+
+func lossMatrix(g *ExprGraph, name string, data []float64) *Node {
+	return NewMatrix(g, Float64, WithShape(3, 4), WithName(name), WithValue(tensor.New(tensor.WithShape(3, 4), tensor.WithBacking(data))))
+}
+
+func lossClasses(g *ExprGraph) *Node {
+	return NewVector(g, Int, WithShape(3), WithName("classes"), WithValue(tensor.New(tensor.WithBacking(lossLabels))))
+}
+
+func TestLosses(t *testing.T) {
+	assert := assert.New(t)
+	weights := []float64{0.5, 2, 1}
+	reductions := []struct {
+		name   string
+		opts   func(g *ExprGraph) []LossOpt
+		reduce func(l []float64) float64
+	}{
+		{"mean", func(g *ExprGraph) []LossOpt { return nil },
+			func(l []float64) float64 { return (l[0] + l[1] + l[2]) / 3 }},
+		{"sum", func(g *ExprGraph) []LossOpt { return []LossOpt{WithReduction(SumReduction)} },
+			func(l []float64) float64 { return l[0] + l[1] + l[2] }},
+		{"none", func(g *ExprGraph) []LossOpt { return []LossOpt{WithReduction(NoReduction)} },
+			func(l []float64) float64 { return l[0] + l[1] + l[2] }},
+		{"weighted mean", func(g *ExprGraph) []LossOpt {
+			w := NewVector(g, Float64, WithShape(3), WithName("w"), WithValue(tensor.New(tensor.WithBacking(weights))))
+			return []LossOpt{WithSampleWeights(w)}
+		}, func(l []float64) float64 { return (0.5*l[0] + 2*l[1] + l[2]) / 3.5 }},
+		{"weighted, none", func(g *ExprGraph) []LossOpt {
+			w := NewVector(g, Float64, WithShape(3), WithName("w"), WithValue(tensor.New(tensor.WithBacking(weights))))
+			return []LossOpt{WithSampleWeights(w), WithReduction(NoReduction)}
+		}, func(l []float64) float64 { return 0.5*l[0] + 2*l[1] + l[2] }},
+	}
+
+	for _, lt := range lossTests {
+		for _, red := range reductions {
+			name := fmt.Sprintf("%v, %v", lt.name, red.name)
+
+			// central differences of the reference
+			a := make([]float64, len(lossOutput))
+			copy(a, lossOutput)
+			correct := red.reduce(lt.ref(a))
+			correctGrad := make([]float64, len(a))
+			for i := range a {
+				orig := a[i]
+				a[i] = orig + 1e-6
+				fp := red.reduce(lt.ref(a))
+				a[i] = orig - 1e-6
+				fm := red.reduce(lt.ref(a))
+				a[i] = orig
+				correctGrad[i] = (fp - fm) / 2e-6
+			}
+
+			for i := 0; i < 2; i++ {
+				g := NewGraph()
+				x := lossMatrix(g, "x", lossOutput)
+				loss, err := lt.loss(x, red.opts(g)...)
+				if err != nil {
+					t.Fatalf("%v: %+v", name, err)
+				}
+				cost := loss
+				if red.name == "none" || red.name == "weighted, none" {
+					assert.Equal(tensor.Shape{3}, loss.Shape(), name)
+					cost = Must(Sum(loss))
+				} else {
+					assert.True(loss.IsScalar(), name)
+				}
+
+				var m VM
+				if i == 0 {
+					if _, err = Grad(cost, x); err != nil {
+						t.Fatalf("%v: %+v", name, err)
+					}
+					prog, locMap, err := Compile(g)
+					if err != nil {
+						t.Fatalf("%v: %+v", name, err)
+					}
+					m = NewTapeMachine(prog, locMap, BindDualValues(x))
+				} else {
+					m = NewLispMachine(g)
+				}
+				if err = m.RunAll(); err != nil {
+					t.Fatalf("%v: %+v", name, err)
+				}
+
+				if i == 1 {
+					// the tape machine may reuse the register of the cost
+					assert.InDelta(correct, extractF64(cost.Value()), 1e-10, name)
+				}
+				grad, err := x.Grad()
+				if err != nil {
+					t.Fatalf("%v: %+v", name, err)
+				}
+				assert.InDeltaSlice(correctGrad, extractF64s(grad), 1e-6, name)
+			}
+		}
+	}
+}
+
+func TestLosses_Stability(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGraph()
+	logits := NewMatrix(g, Float64, WithShape(2, 3), WithName("logits"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{1000, 0, -1000, 0, 0, 0}))))
+	targets := NewMatrix(g, Float64, WithShape(2, 3), WithName("targets"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{1, 0, 0, 0.5, 0.5, 0}))))
+	kl := Must(KLDivergence(logits, targets, WithReduction(NoReduction)))
+
+	logProbs := NewMatrix(g, Float64, WithShape(2, 3), WithName("logProbs"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{0, math.Inf(-1), math.Inf(-1), math.Log(0.5), math.Log(0.5), math.Inf(-1)}))))
+	nll := Must(NLLLoss(logProbs, targets, WithReduction(NoReduction)))
+
+	zero := NewMatrix(g, Float64, WithShape(2, 2), WithName("zero"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float64{0, 0, 0, 0}))))
+	b := NewMatrix(g, Float64, WithShape(2, 2), WithName("b"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float64{1, 2, 0, 0}))))
+	y := NewVector(g, Float64, WithShape(2), WithName("y"), WithValue(tensor.New(tensor.WithBacking([]float64{1, -1}))))
+	cos := Must(CosineEmbeddingLoss(zero, b, y, 0, WithReduction(NoReduction)))
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.InDeltaSlice([]float64{0, math.Log(1.5)}, extractF64s(kl.Value()), 1e-10)
+	assert.InDeltaSlice([]float64{0, math.Log(2)}, extractF64s(nll.Value()), 1e-10)
+	assert.Equal([]float64{1, 0}, extractF64s(cos.Value()))
+}
+
+func TestLosses_Float32(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGraph()
+	x := NewMatrix(g, Float32, WithShape(2, 2), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float32{1, 2, 3, 4}))))
+	y := NewMatrix(g, Float32, WithShape(2, 2), WithName("y"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float32{1, 0, 1, 2}))))
+	signs := NewMatrix(g, Float32, WithShape(2, 2), WithName("signs"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float32{1, -1, -1, 1}))))
+	huber := Must(Huber(x, y, 1))
+	hinge := Must(Hinge(x, signs, WithReduction(SumReduction)))
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal(float32(1.125), huber.Value().Data())
+	assert.Equal(float32(3.5), hinge.Value().Data())
+}
+
+func TestLosses_ColumnVector(t *testing.T) {
+	g := NewGraph()
+	output := NewMatrix(g, Float64, WithShape(3, 1), WithName("output"), WithValue(tensor.New(tensor.WithShape(3, 1), tensor.WithBacking([]float64{1, 2, 3}))))
+	target := NewMatrix(g, Float64, WithShape(3, 1), WithName("target"), WithValue(tensor.New(tensor.WithShape(3, 1), tensor.WithBacking([]float64{1, 2, 3}))))
+	mse := Must(MSE(output, target))
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal(t, 0.0, extractF64(mse.Value()))
+}
+
+func TestLosses_Errors(t *testing.T) {
+	g := NewGraph()
+	a := NewMatrix(g, Float64, WithShape(3, 4), WithName("a"))
+	b := NewMatrix(g, Float64, WithShape(4, 3), WithName("b"))
+	v := NewVector(g, Float64, WithShape(4), WithName("v"))
+	w := NewVector(g, Float64, WithShape(4), WithName("w"))
+	f32 := NewMatrix(g, Float32, WithShape(3, 4), WithName("f32"))
+
+	if _, err := MSE(a, b); err == nil {
+		t.Error("Expected an error for targets of a different shape")
+	}
+	col := NewMatrix(g, Float64, WithShape(4, 1), WithName("col"))
+	if _, err := MSE(col, v); err == nil {
+		t.Error("Expected an error for a column vector output and a vector target, which would be broadcast")
+	}
+	if _, err := MAE(a, f32); err == nil {
+		t.Error("Expected an error for targets of a different Dtype")
+	}
+	if _, err := Huber(a, a, 0); err == nil {
+		t.Error("Expected an error for a delta of 0")
+	}
+	if _, err := Hinge(a, a, WithSampleWeights(w)); err == nil {
+		t.Error("Expected an error for the wrong number of sample weights")
+	}
+	if _, err := CategoricalCrossEntropy(v, v); err == nil {
+		t.Error("Expected an error for logits without a batch axis")
+	}
+	if _, err := CosineEmbeddingLoss(a, a, w, 0); err == nil {
+		t.Error("Expected an error for the wrong number of labels")
+	}
+	if _, err := MSE(a, a, WithReduction(Reduction(42))); err == nil {
+		t.Error("Expected an error for an unknown reduction")
+	}
+}
@@ -66,3 +66,104 @@ func _softplusf32(x float32) float32 {
 	}
 	return float32(math.Log1p(math.Exp(float64(x))))
 }
+
+/* ACTIVATION FUNCTIONS */
+
+// These are the scalar versions of the activation functions. Values are computed by the generated kernels in package tensor.
+
+// the constants of SELU, from Klambauer et al. (2017) - Self-Normalizing Neural Networks
+const (
+	seluLambda = 1.0507009873554804934193349852946
+	seluAlpha  = 1.6732632423543772848170429916717
+)
+
+const (
+	sqrt2         = 1.41421356237309504880168872420969808
+	sqrt2OverPi   = 0.79788456080286535587989211986876373 // √(2/π)
+	geluTanhCoeff = 0.044715
+)
+
+// defaultLeakyReluSlope is the slope of the negative part of a leaky ReLU, when none is given.
+const defaultLeakyReluSlope = 0.01
+
+func _leakyReluf64(x float64) float64 {
+	if x > 0 {
+		return x
+	}
+	return defaultLeakyReluSlope * x
+}
+
+func _leakyReluf32(x float32) float32 {
+	if x > 0 {
+		return x
+	}
+	return defaultLeakyReluSlope * x
+}
+
+func _eluf64(x float64) float64 {
+	if x > 0 {
+		return x
+	}
+	return math.Expm1(x)
+}
+
+func _eluf32(x float32) float32 {
+	if x > 0 {
+		return x
+	}
+	return math32.Expm1(x)
+}
+
+func _seluf64(x float64) float64 {
+	if x > 0 {
+		return seluLambda * x
+	}
+	return seluLambda * seluAlpha * math.Expm1(x)
+}
+
+func _seluf32(x float32) float32 {
+	if x > 0 {
+		return seluLambda * x
+	}
+	return seluLambda * seluAlpha * math32.Expm1(x)
+}
+
+// gelu(x) = x Φ(x), where Φ is the CDF of the standard normal distribution. erfc is used instead of erf, as it is accurate for large negative x
+func _geluf64(x float64) float64 { return 0.5 * x * math.Erfc(-x/sqrt2) }
+func _geluf32(x float32) float32 { return 0.5 * x * math32.Erfc(-x/sqrt2) }
+
+// the tanh approximation of gelu, from Hendrycks and Gimpel (2016) - Gaussian Error Linear Units (GELUs)
+func _geluTanhf64(x float64) float64 {
+	return 0.5 * x * (1 + math.Tanh(sqrt2OverPi*(x+geluTanhCoeff*x*x*x)))
+}
+
+func _geluTanhf32(x float32) float32 {
+	return 0.5 * x * (1 + math32.Tanh(sqrt2OverPi*(x+geluTanhCoeff*x*x*x)))
+}
+
+func _swishf64(x float64) float64 { return x * _sigmoidf64(x) }
+func _swishf32(x float32) float32 { return x * _sigmoidf32(x) }
+
+func _mishf64(x float64) float64 { return x * math.Tanh(_softplusf64(x)) }
+func _mishf32(x float32) float32 { return x * math32.Tanh(_softplusf32(x)) }
+
+// hardSigmoid is the piecewise linear approximation of sigmoid: relu6(x+3)/6
+func _hardSigmoidf64(x float64) float64 {
+	switch {
+	case x <= -3:
+		return 0
+	case x >= 3:
+		return 1
+	}
+	return x/6 + 0.5
+}
+
+func _hardSigmoidf32(x float32) float32 {
+	switch {
+	case x <= -3:
+		return 0
+	case x >= 3:
+		return 1
+	}
+	return x/6 + 0.5
+}
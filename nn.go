@@ -1,6 +1,8 @@
 package gorgonia
 
 import (
+	"sort"
+
 	"github.com/chewxy/gorgonia/tensor"
 	"github.com/pkg/errors"
 )
@@ -58,44 +60,120 @@ func BinaryXent(output, target *Node) (retVal *Node, err error) {
 	return Neg(retVal)
 }
 
-// Dropout is a convenience function to implement dropout.
-// It uses randomly zeroes out a *Tensor with a probability drawn from
-// a uniform distribution
-func Dropout(x *Node, prob float64) (retVal *Node, err error) {
-	if prob == 0.0 {
-		return x, nil
+// SoftmaxCrossEntropy computes the cross entropy between the softmax of logits along the given axis, and the targets.
+// It returns the loss of each sample, which has the shape of logits without the axis.
+//
+// The targets may either be of the same shape as the logits (i.e. one-hot encoded, or any other distribution over the classes),
+// or a tensor of Int classes, with the shape of the logits without the axis.
+//
+// Unlike SoftMax followed by Log, this is computed with the log-sum-exp trick, so confident predictions do not produce NaNs.
+// The gradient wrt logits is (softmax - target). The targets are not differentiable.
+func SoftmaxCrossEntropy(logits, targets *Node, axis int) (retVal *Node, err error) {
+	return softmaxXent("SoftmaxCrossEntropy", logits, targets, axis, 0, false)
+}
+
+// SmoothedSoftmaxCrossEntropy is SoftmaxCrossEntropy with label smoothing. The targets are smoothed to
+//		(1 - smoothing) * targets + smoothing / classes
+// before the cross entropy is computed. smoothing has to be in [0, 1).
+func SmoothedSoftmaxCrossEntropy(logits, targets *Node, axis int, smoothing float64) (retVal *Node, err error) {
+	if smoothing < 0 || smoothing >= 1 {
+		return nil, errors.Errorf("SmoothedSoftmaxCrossEntropy expects smoothing to be in [0, 1). Got %v instead", smoothing)
+	}
+	return softmaxXent("SmoothedSoftmaxCrossEntropy", logits, targets, axis, smoothing, false)
+}
+
+func softmaxXent(fn string, logits, targets *Node, axis int, smoothing float64, kl bool) (retVal *Node, err error) {
+	if err = checkFloatDtype(fn, logits); err != nil {
+		return
+	}
+	if axis < 0 || axis >= logits.Dims() {
+		return nil, errors.Errorf("%v: invalid axis %d for logits of shape %v", fn, axis, logits.Shape())
 	}
 
 	var dt tensor.Dtype
-	if dt, err = dtypeOf(x.t); err != nil {
+	if dt, err = dtypeOf(targets.t); err != nil {
 		return nil, errors.Wrap(err, dtypeOfFail)
 	}
 
-	var opp, pr Value // opp = 1 per p
+	op := softmaxXentOp{
+		axis:       axis,
+		dims:       logits.Dims(),
+		intTargets: dt == Int,
+		smoothing:  smoothing,
+		kl:         kl,
+	}
+	return applyOp(op, logits, targets)
+}
+
+// checkFloatDtype returns an error if x is not a Float64 or Float32 node.
+func checkFloatDtype(fn string, x *Node) error {
+	dt, err := dtypeOf(x.t)
+	if err != nil {
+		return errors.Wrap(err, dtypeOfFail)
+	}
 	switch dt {
-	case Float64:
-		opp, _ = anyToScalar(1.0 / prob)
-		pr, _ = anyToScalar(prob)
-	case Float32:
-		opp, _ = anyToScalar(float32(1.0 / prob))
-		pr, _ = anyToScalar(float32(prob))
+	case Float64, Float32:
+		return nil
 	default:
-		return nil, errors.Errorf(nyiTypeFail, "Dropout()", dt)
+		return errors.Errorf(nyiFail, fn, dt)
 	}
+}
 
-	p := NewConstant(pr)
-	c := NewConstant(opp)
-
-	m := UniformRandomNode(x.g, dt, 0, 1, x.shape...)
-	if retVal, err = Gt(m, p, true); err != nil {
-		return nil, errors.Wrap(err, "Greater Than failed")
+// Dropout randomly zeroes out the values of x with probability prob, and scales the values that are kept by 1/(1-prob),
+// so that the expected value of the result is x.
+//
+// Dropout is only applied when the VM runs in training mode. In evaluation mode (see EvalMode), x is returned unchanged,
+// so the same graph may be used for training and for inference.
+//
+// The values to zero out are drawn from the RNG of the graph (see WithGraphSeed). The mask is kept in the op for the backwards pass,
+// so the graph can't be run by two VMs at the same time.
+func Dropout(x *Node, prob float64) (retVal *Node, err error) {
+	if prob == 0.0 {
+		return x, nil
+	}
+	if prob < 0 || prob >= 1 {
+		return nil, errors.Errorf("Dropout expects a probability in [0, 1). Got %v instead", prob)
+	}
+	if err = checkFloatDtype("Dropout", x); err != nil {
+		return nil, err
 	}
 
-	if retVal, err = HadamardProd(x, retVal); err != nil {
-		return nil, errors.Wrap(err, mulFail)
+	op := newDropoutOp(prob, x.Dims(), x.g.RNG())
+	return applyOp(op, x)
+}
+
+// IfTraining returns train when the VM runs in training mode, and eval when it runs in evaluation mode (see EvalMode).
+// The gradient only flows to the node that was returned. Both nodes are computed regardless of the mode.
+//
+// This may be used for layers that are computed differently for inference. For example, multiplicative gaussian noise, whose mean
+// is 1, may be skipped altogether:
+//		noisy := Must(HadamardProd(x, GaussianRandomNode(g, Float64, 1, 0.1, x.Shape()...)))
+//		y := Must(IfTraining(noisy, x))
+// To use the mean of a random node for inference instead, see MeanInEvalMode.
+func IfTraining(train, eval *Node) (retVal *Node, err error) {
+	for _, n := range []*Node{train, eval} {
+		if err = checkFloatDtype("IfTraining", n); err != nil {
+			return nil, err
+		}
 	}
+	if !sameShape(train.shape, eval.shape) {
+		return nil, errors.Errorf("IfTraining expects both nodes to be of the same shape. Got %v and %v instead", train.shape, eval.shape)
+	}
+
+	op := newIfTrainingOp(train.Dims())
+	return applyOp(op, train, eval)
+}
 
-	return HadamardDiv(retVal, c)
+// MeanInEvalMode marks the random node n (created by UniformRandomNode, GaussianRandomNode or BinomialRandomNode) so that in
+// evaluation mode (see EvalMode) it is the mean of its distribution instead of a sample. By default, random nodes are sampled in
+// both modes. n is returned, marked.
+func MeanInEvalMode(n *Node) (retVal *Node, err error) {
+	op, ok := n.op.(*randomOp)
+	if !ok {
+		return nil, errors.Errorf("MeanInEvalMode expects a random node. Got %v instead", n)
+	}
+	op.evalMean = true
+	return n, nil
 }
 
 // Rectify is a convenience function for creating rectified linear units activation functions.
@@ -121,9 +199,278 @@ func Rectify(x *Node) (retVal *Node, err error) {
 	cmp := newElemBinOp(gteOpType, x, zero)
 	cmp.retSame = true
 
-	if retVal, err = applyOp(cmp, x); err != nil {
+	if retVal, err = applyOp(cmp, x, zero); err != nil {
 		return nil, errors.Wrap(err, applyOpFail)
 	}
 
 	return HadamardProd(x, retVal)
 }
+
+// Conv2d performs a 2D convolution of x with filter. Both x and filter are expected to be 4D tensors in the NCHW format:
+// x is a (batch, channels, height, width) tensor, and filter is a (outChannels, channels, kernelHeight, kernelWidth) tensor.
+//
+// kernelShape must match the last two dimensions of the filter. pad, stride and dilation are given as (height, width) pairs.
+// A nil pad defaults to (0, 0), and a nil stride or dilation defaults to (1, 1).
+func Conv2d(x, filter *Node, kernelShape, pad, stride, dilation []int) (retVal *Node, err error) {
+	if pad == nil {
+		pad = []int{0, 0}
+	}
+	if stride == nil {
+		stride = []int{1, 1}
+	}
+	if dilation == nil {
+		dilation = []int{1, 1}
+	}
+
+	for _, p := range [][]int{kernelShape, pad, stride, dilation} {
+		if len(p) != 2 {
+			return nil, errors.Errorf("Conv2d expects kernel shape, pad, stride and dilation to have 2 values each. Got %v, %v, %v and %v", kernelShape, pad, stride, dilation)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if kernelShape[i] <= 0 || stride[i] <= 0 || dilation[i] <= 0 || pad[i] < 0 {
+			return nil, errors.Errorf("Conv2d: invalid kernel shape %v, pad %v, stride %v or dilation %v", kernelShape, pad, stride, dilation)
+		}
+	}
+
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(x.t); err != nil {
+		return nil, errors.Wrap(err, dtypeOfFail)
+	}
+	switch dt {
+	case Float64, Float32:
+	default:
+		return nil, errors.Errorf(nyiFail, "Conv2d", dt)
+	}
+
+	op := makeConv2dOp(kernelShape, pad, stride, dilation)
+	return applyOp(op, x, filter)
+}
+
+// MaxPool2D performs a 2D max pooling over x, which is expected to be a 4D tensor in the NCHW format.
+// kernel, pad and stride are given as (height, width) pairs. A nil pad defaults to (0, 0), and a nil stride defaults to the kernel shape.
+func MaxPool2D(x *Node, kernel, pad, stride []int) (retVal *Node, err error) {
+	if pad, stride, err = checkPool2dParams("MaxPool2D", x, kernel, pad, stride); err != nil {
+		return nil, err
+	}
+	return applyOp(newMaxPool2dOp(kernel, pad, stride), x)
+}
+
+// AvgPool2D performs a 2D average pooling over x, which is expected to be a 4D tensor in the NCHW format.
+// kernel, pad and stride are given as (height, width) pairs. A nil pad defaults to (0, 0), and a nil stride defaults to the kernel shape.
+//
+// Padded values are counted as zeroes when averaging.
+func AvgPool2D(x *Node, kernel, pad, stride []int) (retVal *Node, err error) {
+	if pad, stride, err = checkPool2dParams("AvgPool2D", x, kernel, pad, stride); err != nil {
+		return nil, err
+	}
+	return applyOp(makeAvgPool2dOp(kernel, pad, stride), x)
+}
+
+// checkPool2dParams fills in the default values of pad and stride, and checks that the parameters of a pooling function are sensible.
+func checkPool2dParams(fn string, x *Node, kernel, pad, stride []int) (retPad, retStride []int, err error) {
+	if pad == nil {
+		pad = []int{0, 0}
+	}
+	if stride == nil {
+		stride = kernel
+	}
+
+	for _, p := range [][]int{kernel, pad, stride} {
+		if len(p) != 2 {
+			return nil, nil, errors.Errorf("%s expects kernel shape, pad and stride to have 2 values each. Got %v, %v and %v", fn, kernel, pad, stride)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if kernel[i] <= 0 || stride[i] <= 0 || pad[i] < 0 || pad[i] >= kernel[i] {
+			return nil, nil, errors.Errorf("%s: invalid kernel shape %v, pad %v or stride %v", fn, kernel, pad, stride)
+		}
+	}
+
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(x.t); err != nil {
+		return nil, nil, errors.Wrap(err, dtypeOfFail)
+	}
+	switch dt {
+	case Float64, Float32:
+	default:
+		return nil, nil, errors.Errorf(nyiFail, fn, dt)
+	}
+	return pad, stride, nil
+}
+
+// BatchNorm performs batch normalization on x, whose channels are expected to be on axis 1 (i.e. x is a NC or NCHW tensor).
+// scale and bias are vectors with one value per channel.
+//
+// When training, x is normalized with the mean and variance of the batch, and the running mean and variance are updated in place:
+//		running = momentum * running + (1 - momentum) * batch
+// When doing inference, x is normalized with the running mean and variance instead. The mode is set on the VM - see EvalMode and TrainMode.
+//
+// runningMean and runningVar are vectors with one value per channel, which must have values bound to them.
+// They typically start as zeroes and ones respectively:
+//		runningMean := NewVector(g, Float64, WithShape(c), WithName("runningMean"), WithInit(Zeroes()))
+//		runningVar := NewVector(g, Float64, WithShape(c), WithName("runningVar"), WithValue(tensor.Ones(Float64, c)))
+// As they are owned by the caller, the statistics of a trained model may be read from their values, saved, and restored with Let.
+//
+// eps is added to the variance for numerical stability.
+func BatchNorm(x, scale, bias, runningMean, runningVar *Node, momentum, eps float64) (retVal *Node, err error) {
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(x.t); err != nil {
+		return nil, errors.Wrap(err, dtypeOfFail)
+	}
+	switch dt {
+	case Float64, Float32:
+	default:
+		return nil, errors.Errorf(nyiFail, "BatchNorm", dt)
+	}
+
+	if x.Dims() < 2 {
+		return nil, errors.Errorf("BatchNorm expects the input to have at least 2 dimensions. Got %v instead", x.Shape())
+	}
+	if momentum < 0 || momentum > 1 {
+		return nil, errors.Errorf("BatchNorm expects momentum to be between 0 and 1. Got %v instead", momentum)
+	}
+	for _, n := range []*Node{runningMean, runningVar} {
+		if !n.isInput() {
+			return nil, errors.Errorf("BatchNorm expects the running statistics to be input nodes, as they are updated in place. Got %v instead", n)
+		}
+	}
+
+	op := newBatchNormOp(momentum, eps, x.Dims())
+	return applyOp(op, x, scale, bias, runningMean, runningVar)
+}
+
+// LayerNorm performs layer normalization on x over the given axes. If no axes are given, the last axis is used.
+//		y = gain * (x - mean(x)) / sqrt(var(x) + eps) + bias
+// gain and bias have the shape of the normalized axes, and are broadcast across the other axes. Either may be nil.
+func LayerNorm(x, gain, bias *Node, eps float64, along ...int) (retVal *Node, err error) {
+	return norm("LayerNorm", false, x, gain, bias, eps, along)
+}
+
+// RMSNorm performs root mean square normalization on x over the given axes. If no axes are given, the last axis is used.
+//		y = gain * x / sqrt(mean(x²) + eps) + bias
+// gain and bias have the shape of the normalized axes, and are broadcast across the other axes. Either may be nil.
+func RMSNorm(x, gain, bias *Node, eps float64, along ...int) (retVal *Node, err error) {
+	return norm("RMSNorm", true, x, gain, bias, eps, along)
+}
+
+func norm(fn string, rms bool, x, gain, bias *Node, eps float64, along []int) (retVal *Node, err error) {
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(x.t); err != nil {
+		return nil, errors.Wrap(err, dtypeOfFail)
+	}
+	switch dt {
+	case Float64, Float32:
+	default:
+		return nil, errors.Errorf(nyiFail, fn, dt)
+	}
+
+	dims := x.Dims()
+	if dims == 0 {
+		return nil, errors.Errorf("%s cannot be applied to a scalar", fn)
+	}
+	if len(along) == 0 {
+		along = []int{dims - 1}
+	}
+	along = append([]int(nil), along...)
+	sort.Ints(along)
+	for i, a := range along {
+		if a < 0 || a >= dims || (i > 0 && along[i-1] == a) {
+			return nil, errors.Errorf("%s: invalid axes %v for an input with %d dimensions", fn, along, dims)
+		}
+	}
+
+	op := normOp{
+		rms:     rms,
+		along:   axes(along),
+		eps:     eps,
+		dims:    dims,
+		hasGain: gain != nil,
+		hasBias: bias != nil,
+	}
+	children := Nodes{x}
+	if gain != nil {
+		children = append(children, gain)
+	}
+	if bias != nil {
+		children = append(children, bias)
+	}
+	return applyOp(op, children...)
+}
+
+// LSTMCell performs one step of a LSTM, given the input x, and the previous hidden and cell states. It returns the new hidden and cell states.
+//
+// x, prevHidden and prevCell may either be vectors, or (batch, size) matrices. The weights are stacked in the order
+// of the input gate, the forget gate, the output gate and the cell write:
+//		wx is a (4×hiddenSize, inputSize) matrix
+//		wh is a (4×hiddenSize, hiddenSize) matrix
+//		bias is a vector of 4×hiddenSize
+// The cell is computed in one fused op, so the graph only grows by a handful of nodes per step.
+func LSTMCell(x, prevHidden, prevCell, wx, wh, bias *Node) (hidden, cell *Node, err error) {
+	if err = checkRNNCellDtype("LSTMCell", x); err != nil {
+		return
+	}
+
+	var state *Node
+	if state, err = applyOp(newLSTMOp(x.Dims()), x, prevHidden, prevCell, wx, wh, bias); err != nil {
+		return nil, nil, errors.Wrap(err, applyOpFail)
+	}
+	if hidden, err = Slice(state, S(0)); err != nil {
+		return nil, nil, errors.Wrapf(err, sliceFail, state)
+	}
+	if cell, err = Slice(state, S(1)); err != nil {
+		return nil, nil, errors.Wrapf(err, sliceFail, state)
+	}
+	return
+}
+
+// GRUCell performs one step of a GRU, given the input x and the previous hidden state. It returns the new hidden state.
+//
+// x and prevHidden may either be vectors, or (batch, size) matrices. The weights are stacked in the order
+// of the reset gate, the update gate and the candidate:
+//		wx is a (3×hiddenSize, inputSize) matrix
+//		wh is a (3×hiddenSize, hiddenSize) matrix
+//		bias is a vector of 3×hiddenSize
+// The cell is computed in one fused op.
+func GRUCell(x, prevHidden, wx, wh, bias *Node) (hidden *Node, err error) {
+	if err = checkRNNCellDtype("GRUCell", x); err != nil {
+		return
+	}
+	return applyOp(newGRUOp(x.Dims()), x, prevHidden, wx, wh, bias)
+}
+
+func checkRNNCellDtype(fn string, x *Node) error {
+	dt, err := dtypeOf(x.t)
+	if err != nil {
+		return errors.Wrap(err, dtypeOfFail)
+	}
+	switch dt {
+	case Float64, Float32:
+		return nil
+	default:
+		return errors.Errorf(nyiFail, fn, dt)
+	}
+}
+
+// Embedding looks up the rows of table, a (rows, dims) matrix, given indices, which is a scalar or tensor of Int.
+// The result has the shape of indices with dims appended to it.
+//
+// The gradient wrt table is row sparse: only the rows that were looked up are incremented.
+// The solvers recognize tables that are only used by Embedding, and only update the rows that were looked up.
+func Embedding(table, indices *Node) (retVal *Node, err error) {
+	if table.Dims() != 2 {
+		return nil, errors.Errorf("Embedding expects the table to be a matrix. Got %v instead", table.Shape())
+	}
+	if err = checkRNNCellDtype("Embedding", table); err != nil {
+		return
+	}
+
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(indices.t); err != nil {
+		return nil, errors.Wrap(err, dtypeOfFail)
+	}
+	if dt != Int {
+		return nil, errors.Errorf("Embedding expects the indices to be of Int. Got %v instead", dt)
+	}
+	return applyOp(embeddingOp{indices.Dims()}, table, indices)
+}
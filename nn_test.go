@@ -2,10 +2,12 @@ package gorgonia
 
 import (
 	"io/ioutil"
+	"math"
 	"runtime"
 	"testing"
 
 	"github.com/chewxy/gorgonia/tensor"
+	"github.com/stretchr/testify/assert"
 )
 
 func dropoutTest(t *testing.T, dt tensor.Dtype) error {
@@ -56,3 +58,785 @@ func TestDropout(t *testing.T) {
 	// visual inspection
 	// ioutil.WriteFile("fullGraph.dot", []byte(g.ToDot()), 0644)
 }
+
+func TestDropoutModes(t *testing.T) {
+	assert := assert.New(t)
+	const size, prob = 1000, 0.25
+	scale := 1 / (1 - prob)
+
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewVector(g, Float64, WithShape(size), WithName("x"), WithValue(tensor.Ones(Float64, size)))
+		y := Must(Dropout(x, prob))
+		cost := Must(Sum(y))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+
+		// training: as x is all ones, the gradient is the scaled mask
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		dx, _ := x.Grad()
+		var dropped int
+		for _, v := range extractF64s(dx) {
+			switch v {
+			case 0:
+				dropped++
+			case scale:
+			default:
+				t.Fatalf("Expected the gradient to be 0 or %v. Got %v", scale, v)
+			}
+		}
+		assert.InDelta(prob, float64(dropped)/size, 0.1)
+		assert.InDelta(float64(size-dropped)*scale, extractF64(cost.Value()), 1e-6)
+
+		// inference: the same graph is the identity
+		if err := EvalMode(m); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		m.Reset()
+		// gradients accumulate until a solver steps
+		x.boundTo.(*dualValue).d.(tensor.Tensor).Zero()
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(float64(size), extractF64(cost.Value()))
+		dx, _ = x.Grad()
+		assert.Equal(extractF64s(tensor.Ones(Float64, size)), extractF64s(dx))
+	}
+
+	g := NewGraph()
+	x := NewVector(g, Float64, WithShape(2), WithName("x"))
+	if _, err := Dropout(x, 1); err == nil {
+		t.Error("Expected an error for a probability of 1")
+	}
+}
+
+func TestIfTraining(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewVector(g, Float64, WithShape(3), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float64{1, 2, 3}))))
+		noise := NewVector(g, Float64, WithShape(3), WithName("noise"), WithValue(tensor.New(tensor.WithBacking([]float64{0.5, -0.5, 1}))))
+		noisy := Must(Add(x, noise))
+		y := Must(IfTraining(noisy, Must(HadamardProd(x, twof64))))
+		cost := Must(Sum(y))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x, noise); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(7.0, extractF64(cost.Value()))
+		dx, _ := x.Grad()
+		dnoise, _ := noise.Grad()
+		assert.Equal([]float64{1, 1, 1}, extractF64s(dx))
+		assert.Equal([]float64{1, 1, 1}, extractF64s(dnoise))
+
+		if err := EvalMode(m); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		m.Reset()
+		// gradients accumulate until a solver steps
+		x.boundTo.(*dualValue).d.(tensor.Tensor).Zero()
+		noise.boundTo.(*dualValue).d.(tensor.Tensor).Zero()
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(12.0, extractF64(cost.Value()))
+		dx, _ = x.Grad()
+		dnoise, _ = noise.Grad()
+		assert.Equal([]float64{2, 2, 2}, extractF64s(dx))
+		assert.Equal([]float64{0, 0, 0}, extractF64s(dnoise))
+	}
+
+	g := NewGraph()
+	a := NewVector(g, Float64, WithShape(2), WithName("a"))
+	b := NewVector(g, Float64, WithShape(3), WithName("b"))
+	if _, err := IfTraining(a, b); err == nil {
+		t.Error("Expected an error for nodes of different shapes")
+	}
+}
+
+func TestRandomNodeModes(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		u := Must(MeanInEvalMode(UniformRandomNode(g, Float64, -1, 3, 100)))
+		n := Must(MeanInEvalMode(GaussianRandomNode(g, Float32, 2, 1, 100)))
+		b := Must(MeanInEvalMode(BinomialRandomNode(g, Float64, 10, 0.5)))
+		s := GaussianRandomNode(g, Float64, 0, 1, 50) // not marked: sampled in both modes
+		cost := Must(Add(Must(Sum(u)), b))
+		sSum := Must(Sum(s))
+
+		var m VM
+		if i == 0 {
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap)
+		} else {
+			m = NewLispMachine(g, ExecuteFwdOnly())
+		}
+
+		// training: the values are drawn from the distributions
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		us := extractF64s(u.Value())
+		assert.NotEqual(us[0], us[1])
+
+		// inference: the values of the marked nodes are the means of the distributions
+		if err := EvalMode(m); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		m.Reset()
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		for _, v := range extractF64s(u.Value()) {
+			assert.Equal(1.0, v)
+		}
+		for _, v := range n.Value().Data().([]float32) {
+			assert.Equal(float32(2), v)
+		}
+		assert.Equal(5.0, extractF64(b.Value()))
+		assert.Equal(105.0, extractF64(cost.Value()))
+		ss := extractF64s(s.Value())
+		assert.NotEqual(ss[0], ss[1])
+		assert.NotEqual(0.0, extractF64(sSum.Value()))
+	}
+
+	g := NewGraph()
+	x := NewVector(g, Float64, WithShape(2), WithName("x"))
+	if _, err := MeanInEvalMode(x); err == nil {
+		t.Error("Expected an error marking a node that is not random")
+	}
+}
+
+type modelessVM struct{}
+
+func (modelessVM) RunAll() error { return nil }
+func (modelessVM) Reset()        {}
+
+func TestModes_UnsupportedVM(t *testing.T) {
+	if err := EvalMode(modelessVM{}); err == nil {
+		t.Error("Expected an error setting the evaluation mode of a VM that does not support modes")
+	}
+	if err := TrainMode(modelessVM{}); err == nil {
+		t.Error("Expected an error setting the training mode of a VM that does not support modes")
+	}
+}
+
+func conv2dTest(t *testing.T, dt tensor.Dtype) {
+	assert := assert.New(t)
+
+	correctY := []float64{8, 12, 20, 24}
+	correctDX := []float64{1, 2, 1, 2, 4, 2, 1, 2, 1}
+	correctDF := []float64{8, 12, 20, 24}
+
+	// symbolic differentiation, run on the tape machine
+	g := NewGraph()
+	x := NewTensor(g, dt, 4, WithShape(1, 1, 3, 3), WithName("x"), WithInit(RangedFrom(0)))
+	f := NewTensor(g, dt, 4, WithShape(1, 1, 2, 2), WithName("f"), WithValue(tensor.Ones(dt, 1, 1, 2, 2)))
+	y := Must(Conv2d(x, f, []int{2, 2}, nil, nil, nil))
+	cost := Must(Sum(y))
+	assert.Equal(tensor.Shape{1, 1, 2, 2}, y.Shape())
+
+	if _, err := Grad(cost, x, f); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	prog, locMap, err := Compile(g)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	m := NewTapeMachine(prog, locMap, BindDualValues())
+	if err = m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	dx, _ := x.Grad()
+	df, _ := f.Grad()
+	assert.Equal(correctY, dataAsF64s(y.Value()))
+	assert.Equal(correctDX, dataAsF64s(dx))
+	assert.Equal(correctDF, dataAsF64s(df))
+
+	// automatic differentiation, run on the lisp machine
+	g2 := NewGraph()
+	x2 := NewTensor(g2, dt, 4, WithShape(1, 1, 3, 3), WithName("x"), WithInit(RangedFrom(0)))
+	f2 := NewTensor(g2, dt, 4, WithShape(1, 1, 2, 2), WithName("f"), WithValue(tensor.Ones(dt, 1, 1, 2, 2)))
+	y2 := Must(Conv2d(x2, f2, []int{2, 2}, nil, nil, nil))
+	Must(Sum(y2))
+
+	m2 := NewLispMachine(g2)
+	if err = m2.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	dx2, _ := x2.Grad()
+	df2, _ := f2.Grad()
+	assert.Equal(correctY, dataAsF64s(y2.Value()))
+	assert.Equal(correctDX, dataAsF64s(dx2))
+	assert.Equal(correctDF, dataAsF64s(df2))
+}
+
+func TestRectify(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewVector(g, Float64, WithShape(4), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float64{-2, -0.5, 0, 3}))))
+		y := Must(Rectify(x))
+		cost := Must(Sum(y))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal([]float64{0, 0, 0, 3}, extractF64s(y.Value()))
+		dx, _ := x.Grad()
+		assert.Equal([]float64{0, 0, 1, 1}, extractF64s(dx))
+	}
+
+	g := NewGraph()
+	x := NewVector(g, Float32, WithShape(2), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float32{-1, 2}))))
+	y := Must(Rectify(x))
+	if err := NewLispMachine(g, ExecuteFwdOnly()).RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]float32{0, 2}, y.Value().Data())
+}
+
+func TestConv2d(t *testing.T) {
+	conv2dTest(t, Float64)
+	conv2dTest(t, Float32)
+
+	// padding, strides and dilation
+	g := NewGraph()
+	x := NewTensor(g, Float64, 4, WithShape(2, 3, 7, 7), WithName("x"))
+	f := NewTensor(g, Float64, 4, WithShape(4, 3, 3, 3), WithName("f"))
+	y := Must(Conv2d(x, f, []int{3, 3}, []int{1, 1}, []int{2, 2}, []int{1, 1}))
+	assert.Equal(t, tensor.Shape{2, 4, 4, 4}, y.Shape())
+
+	y = Must(Conv2d(x, f, []int{3, 3}, nil, nil, []int{2, 2}))
+	assert.Equal(t, tensor.Shape{2, 4, 3, 3}, y.Shape())
+
+	// mismatched kernel shape
+	if _, err := Conv2d(x, f, []int{2, 2}, nil, nil, nil); err == nil {
+		t.Error("Expected an error when the kernel shape does not match the filter")
+	}
+}
+
+func poolTest(t *testing.T, dt tensor.Dtype) {
+	assert := assert.New(t)
+
+	xBack := []float64{
+		1, 5, 2, 0,
+		3, 4, 8, 1,
+		0, 2, 6, 7,
+		9, 1, 3, 5,
+	}
+	correctMax := []float64{5, 8, 9, 7}
+	correctMaxDX := []float64{
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+		1, 0, 0, 0,
+	}
+	correctAvg := []float64{13.0 / 4, 11.0 / 4, 12.0 / 4, 21.0 / 4}
+	correctAvgDX := []float64{
+		0.25, 0.25, 0.25, 0.25,
+		0.25, 0.25, 0.25, 0.25,
+		0.25, 0.25, 0.25, 0.25,
+		0.25, 0.25, 0.25, 0.25,
+	}
+
+	var xv tensor.Tensor
+	if dt == Float64 {
+		xv = tensor.New(tensor.WithBacking(xBack), tensor.WithShape(1, 1, 4, 4))
+	} else {
+		xv = tensor.New(tensor.WithBacking(f64sTof32s(xBack)), tensor.WithShape(1, 1, 4, 4))
+	}
+
+	pools := []struct {
+		name            string
+		fn              func(x *Node, kernel, pad, stride []int) (*Node, error)
+		correct, corrDX []float64
+	}{
+		{"MaxPool2D", MaxPool2D, correctMax, correctMaxDX},
+		{"AvgPool2D", AvgPool2D, correctAvg, correctAvgDX},
+	}
+
+	for _, p := range pools {
+		// symbolic differentiation, run on the tape machine
+		g := NewGraph()
+		x := NewTensor(g, dt, 4, WithShape(1, 1, 4, 4), WithName("x"), WithValue(xv))
+		y := Must(p.fn(x, []int{2, 2}, nil, nil))
+		cost := Must(Sum(y))
+		assert.Equal(tensor.Shape{1, 1, 2, 2}, y.Shape(), p.name)
+
+		if _, err := Grad(cost, x); err != nil {
+			t.Fatalf("%v: %+v", p.name, err)
+		}
+		prog, locMap, err := Compile(g)
+		if err != nil {
+			t.Fatalf("%v: %+v", p.name, err)
+		}
+		m := NewTapeMachine(prog, locMap, BindDualValues())
+		if err = m.RunAll(); err != nil {
+			t.Fatalf("%v: %+v", p.name, err)
+		}
+		dx, _ := x.Grad()
+		assert.Equal(p.correct, dataAsF64s(y.Value()), p.name)
+		assert.Equal(p.corrDX, dataAsF64s(dx), p.name)
+
+		// automatic differentiation, run on the lisp machine
+		g2 := NewGraph()
+		x2 := NewTensor(g2, dt, 4, WithShape(1, 1, 4, 4), WithName("x"), WithValue(xv))
+		y2 := Must(p.fn(x2, []int{2, 2}, nil, nil))
+		Must(Sum(y2))
+
+		m2 := NewLispMachine(g2)
+		if err = m2.RunAll(); err != nil {
+			t.Fatalf("%v: %+v", p.name, err)
+		}
+		dx2, _ := x2.Grad()
+		assert.Equal(p.correct, dataAsF64s(y2.Value()), p.name)
+		assert.Equal(p.corrDX, dataAsF64s(dx2), p.name)
+	}
+}
+
+func TestPool2D(t *testing.T) {
+	poolTest(t, Float64)
+	poolTest(t, Float32)
+
+	g := NewGraph()
+	x := NewTensor(g, Float64, 4, WithShape(2, 3, 7, 7), WithName("x"))
+	y := Must(MaxPool2D(x, []int{3, 3}, []int{1, 1}, []int{2, 2}))
+	assert.Equal(t, tensor.Shape{2, 3, 4, 4}, y.Shape())
+
+	y = Must(AvgPool2D(x, []int{3, 3}, nil, []int{1, 1}))
+	assert.Equal(t, tensor.Shape{2, 3, 5, 5}, y.Shape())
+
+	if _, err := MaxPool2D(x, []int{2, 2}, []int{2, 2}, nil); err == nil {
+		t.Error("Expected an error when the padding is as large as the kernel")
+	}
+}
+
+func TestBatchNorm(t *testing.T) {
+	assert := assert.New(t)
+
+	xBack := []float64{
+		1, 10,
+		2, 20,
+		3, 30,
+		6, 60,
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(4, 2), WithName("x"), WithValue(tensor.New(tensor.WithBacking(xBack), tensor.WithShape(4, 2))))
+	scale := NewVector(g, Float64, WithShape(2), WithName("scale"), WithValue(tensor.New(tensor.WithBacking([]float64{1, 2}), tensor.WithShape(2))))
+	bias := NewVector(g, Float64, WithShape(2), WithName("bias"), WithValue(tensor.New(tensor.WithBacking([]float64{0, 1}), tensor.WithShape(2))))
+	runMean := NewVector(g, Float64, WithShape(2), WithName("runningMean"), WithInit(Zeroes()))
+	runVar := NewVector(g, Float64, WithShape(2), WithName("runningVar"), WithValue(tensor.Ones(Float64, 2)))
+	w := NewConstant(tensor.New(tensor.WithBacking([]float64{1, 2, 3, -1, 0, 4, 2, 1}), tensor.WithShape(4, 2)))
+	y := Must(BatchNorm(x, scale, bias, runMean, runVar, 0.5, 0))
+	cost := Must(Sum(Must(HadamardProd(y, w))))
+
+	if _, err := Grad(cost, x, scale, bias); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	// the gradients wrt all the inputs are computed by one node
+	var diffs int
+	for _, n := range g.AllNodes() {
+		if _, ok := n.op.(batchNormDiffOp); ok {
+			diffs++
+		}
+	}
+	assert.Equal(1, diffs)
+
+	prog, locMap, err := Compile(g)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// training: normalized with the batch statistics. mean is (3, 30), variance is (3.5, 350)
+	m := NewTapeMachine(prog, locMap, BindDualValues())
+	if err = m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	correct := []float64{-2, -2, -1, -1, 0, 0, 3, 3}
+	for i := range correct {
+		correct[i] /= math.Sqrt(3.5)
+		if i%2 == 1 {
+			correct[i] = correct[i]*2 + 1
+		}
+	}
+	assert.True(floatsEqual64(correct, extractF64s(y.Value())), "Got %v", y.Value())
+	assert.True(floatsEqual64([]float64{1.5, 15}, extractF64s(runMean.Value())))
+	assert.True(floatsEqual64([]float64{0.5 + 0.5*14.0/3, 0.5 + 0.5*1400.0/3}, extractF64s(runVar.Value())))
+
+	correctDX := []float64{
+		-0.19090088708030306, 0.05345224838248487,
+		0.8399639031533338, -0.26726124191242434,
+		-0.8017837257372732, 0.26726124191242434,
+		0.15272070966424245, -0.05345224838248487,
+	}
+	correctDScale := []float64{1 / math.Sqrt(3.5), 0}
+	dx, _ := x.Grad()
+	dscale, _ := scale.Grad()
+	dbias, _ := bias.Grad()
+	assert.True(floatsEqual64(correctDX, extractF64s(dx)), "Got %v", dx)
+	assert.True(floatsEqual64(correctDScale, extractF64s(dscale)), "Got %v", dscale)
+	assert.Equal([]float64{6, 6}, extractF64s(dbias))
+
+	// inference: normalized with the running statistics, which are not updated
+	if err = EvalMode(m); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	m.Reset()
+	if err = m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	v0 := extractF64s(runVar.Value())[0]
+	assert.True(floatEquals64((1-1.5)/math.Sqrt(v0), extractF64s(y.Value())[0]))
+	assert.True(floatsEqual64([]float64{1.5, 15}, extractF64s(runMean.Value())))
+
+	// the running statistics may be restored, such as from a saved model
+	Let(runMean, tensor.New(tensor.WithBacking([]float64{1, 10}), tensor.WithShape(2)))
+	Let(runVar, tensor.New(tensor.WithBacking([]float64{4, 100}), tensor.WithShape(2)))
+	m.Reset()
+	if err = m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	correctRestored := []float64{0, 1, 0.5, 3, 1, 5, 2.5, 11}
+	assert.True(floatsEqual64(correctRestored, extractF64s(y.Value())), "Got %v", y.Value())
+
+	// lisp machine
+	g2 := NewGraph()
+	x2 := NewMatrix(g2, Float32, WithShape(4, 2), WithName("x"), WithValue(tensor.New(tensor.WithBacking(f64sTof32s(xBack)), tensor.WithShape(4, 2))))
+	scale2 := NewVector(g2, Float32, WithShape(2), WithName("scale"), WithValue(tensor.New(tensor.WithBacking([]float32{1, 2}), tensor.WithShape(2))))
+	bias2 := NewVector(g2, Float32, WithShape(2), WithName("bias"), WithValue(tensor.New(tensor.WithBacking([]float32{0, 1}), tensor.WithShape(2))))
+	runMean2 := NewVector(g2, Float32, WithShape(2), WithName("runningMean"), WithInit(Zeroes()))
+	runVar2 := NewVector(g2, Float32, WithShape(2), WithName("runningVar"), WithValue(tensor.Ones(Float32, 2)))
+	w2 := NewConstant(tensor.New(tensor.WithBacking([]float32{1, 2, 3, -1, 0, 4, 2, 1}), tensor.WithShape(4, 2)))
+	y2 := Must(BatchNorm(x2, scale2, bias2, runMean2, runVar2, 0.5, 0))
+	Must(Sum(Must(HadamardProd(y2, w2))))
+
+	m2 := NewLispMachine(g2)
+	if err = m2.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.True(floatsEqual32(f64sTof32s(correct), extractF32s(y2.Value())), "Got %v", y2.Value())
+	assert.True(floatsEqual32([]float32{1.5, 15}, extractF32s(runMean2.Value())))
+	dx2, _ := x2.Grad()
+	dscale2, _ := scale2.Grad()
+	dbias2, _ := bias2.Grad()
+	assert.True(floatsEqual32(f64sTof32s(correctDX), extractF32s(dx2)), "Got %v", dx2)
+	assert.True(floatsEqual32(f64sTof32s(correctDScale), extractF32s(dscale2)), "Got %v", dscale2)
+	assert.Equal([]float32{6, 6}, extractF32s(dbias2))
+
+	// a single channel: the first channel of x, with a scale of 2
+	g3 := NewGraph()
+	x3 := NewTensor(g3, Float32, 3, WithShape(2, 1, 2), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float32{1, 2, 3, 6}), tensor.WithShape(2, 1, 2))))
+	scale3 := NewVector(g3, Float32, WithShape(1), WithName("scale"), WithValue(tensor.New(tensor.WithBacking([]float32{2}), tensor.WithShape(1))))
+	bias3 := NewVector(g3, Float32, WithShape(1), WithName("bias"), WithValue(tensor.New(tensor.WithBacking([]float32{0}), tensor.WithShape(1))))
+	runMean3 := NewVector(g3, Float32, WithShape(1), WithName("runningMean"), WithInit(Zeroes()))
+	runVar3 := NewVector(g3, Float32, WithShape(1), WithName("runningVar"), WithValue(tensor.Ones(Float32, 1)))
+	w3 := NewConstant(tensor.New(tensor.WithBacking([]float32{1, 3, 0, 2}), tensor.WithShape(2, 1, 2)))
+	y3 := Must(BatchNorm(x3, scale3, bias3, runMean3, runVar3, 0.5, 0))
+	Must(Sum(Must(HadamardProd(y3, w3))))
+
+	m3 := NewLispMachine(g3)
+	if err = m3.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	correct3, correctDX3 := make([]float32, 4), make([]float32, 4)
+	for i := range correct3 {
+		correct3[i] = float32(correct[2*i] * 2)
+		correctDX3[i] = float32(correctDX[2*i] * 2)
+	}
+	assert.True(floatsEqual32(correct3, extractF32s(y3.Value())), "Got %v", y3.Value())
+	dx3, _ := x3.Grad()
+	dscale3, _ := scale3.Grad()
+	dbias3, _ := bias3.Grad()
+	assert.True(floatsEqual32(correctDX3, extractF32s(dx3)), "Got %v", dx3)
+	assert.True(floatEquals32(float32(correctDScale[0]), extractF32(dscale3)), "Got %v", dscale3)
+	assert.Equal(float32(6), extractF32(dbias3))
+	assert.True(floatEquals32(1.5, extractF32(runMean3.Value())))
+
+	if _, err = BatchNorm(x3, scale3, bias3, Must(Neg(runMean3)), runVar3, 0.5, 0); err == nil {
+		t.Error("Expected an error when the running statistics are not input nodes")
+	}
+}
+
+func TestLayerNormRMSNorm(t *testing.T) {
+	assert := assert.New(t)
+	xBack := []float64{1, 2, 3, 2, 4, 6}
+	wBack := []float64{0.5, -1, 2, 1, 3, -2}
+
+	s := math.Sqrt(1.5)
+	r1, r2 := math.Sqrt(14.0/3), math.Sqrt(56.0/3)
+	norms := []struct {
+		name    string
+		fn      func(x, gain, bias *Node, eps float64, along ...int) (*Node, error)
+		correct []float64
+	}{
+		{"LayerNorm", LayerNorm, []float64{-s*2 + 1, 0*3 - 1, s*4 + 1, -s*2 + 1, 0*3 - 1, s*4 + 1}},
+		{"RMSNorm", RMSNorm, []float64{2/r1 + 1, 6/r1 - 1, 12/r1 + 1, 4/r2 + 1, 12/r2 - 1, 24/r2 + 1}},
+	}
+
+	for _, n := range norms {
+		var grads [2][]Value
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithBacking(xBack), tensor.WithShape(2, 3))))
+			gain := NewVector(g, Float64, WithShape(3), WithName("gain"), WithValue(tensor.New(tensor.WithBacking([]float64{2, 3, 4}), tensor.WithShape(3))))
+			bias := NewVector(g, Float64, WithShape(3), WithName("bias"), WithValue(tensor.New(tensor.WithBacking([]float64{1, -1, 1}), tensor.WithShape(3))))
+			w := NewConstant(tensor.New(tensor.WithBacking(wBack), tensor.WithShape(2, 3)))
+			y := Must(n.fn(x, gain, bias, 0))
+			Must(Sum(Must(HadamardProd(y, w))))
+
+			var m VM
+			if i == 0 {
+				if _, err := Grad(g.Roots()[0], x, gain, bias); err != nil {
+					t.Fatalf("%v: %+v", n.name, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", n.name, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err := m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", n.name, err)
+			}
+			assert.True(floatsEqual64(n.correct, extractF64s(y.Value())), "%v: Got %v", n.name, y.Value())
+
+			for _, node := range (Nodes{x, gain, bias}) {
+				grad, err := node.Grad()
+				if err != nil {
+					t.Fatalf("%v: %+v", n.name, err)
+				}
+				grads[i] = append(grads[i], grad)
+			}
+		}
+
+		// symbolic and automatic differentiation should agree
+		for j := range grads[0] {
+			assert.True(floatsEqual64(extractF64s(grads[0][j]), extractF64s(grads[1][j])), "%v: %v vs %v", n.name, grads[0][j], grads[1][j])
+		}
+		// the gradient of the bias is the sum of the gradients of the output along the non-normalized axes
+		assert.Equal([]float64{1.5, 2, 0}, extractF64s(grads[0][2]), n.name)
+	}
+
+	g := NewGraph()
+	x := NewTensor(g, Float32, 3, WithShape(2, 3, 4), WithName("x"))
+	gain := NewMatrix(g, Float32, WithShape(3, 4), WithName("gain"))
+	y := Must(LayerNorm(x, gain, nil, 1e-5, 1, 2))
+	assert.Equal(tensor.Shape{2, 3, 4}, y.Shape())
+	if _, err := RMSNorm(x, gain, nil, 1e-5); err == nil {
+		t.Error("Expected an error when the gain does not match the normalized axes")
+	}
+}
+
+func TestEmbedding(t *testing.T) {
+	assert := assert.New(t)
+	tableBack := []float64{
+		0, 1, 2,
+		3, 4, 5,
+		6, 7, 8,
+		9, 10, 11,
+	}
+
+	var grads [2]Value
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		table := NewMatrix(g, Float64, WithShape(4, 3), WithName("table"), WithValue(tensor.New(tensor.WithBacking(tableBack), tensor.WithShape(4, 3))))
+		indices := NewMatrix(g, Int, WithShape(2, 2), WithName("indices"), WithValue(tensor.New(tensor.WithBacking([]int{1, 3, 1, 0}), tensor.WithShape(2, 2))))
+		y := Must(Embedding(table, indices))
+		assert.Equal(tensor.Shape{2, 2, 3}, y.Shape())
+		w := NewConstant(tensor.New(tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}), tensor.WithShape(2, 2, 3)))
+		Must(Sum(Must(HadamardProd(y, w))))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(g.Roots()[0], table); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal([]float64{3, 4, 5, 9, 10, 11, 3, 4, 5, 0, 1, 2}, extractF64s(y.Value()))
+
+		var err error
+		if grads[i], err = table.Grad(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	// row 1 was looked up twice, row 2 was never looked up
+	correct := []float64{
+		10, 11, 12,
+		8, 10, 12,
+		0, 0, 0,
+		4, 5, 6,
+	}
+	assert.Equal(correct, extractF64s(grads[0]))
+	assert.Equal(correct, extractF64s(grads[1]))
+
+	g := NewGraph()
+	table := NewMatrix(g, Float32, WithShape(4, 3), WithName("table"))
+	idx := NewScalar(g, Int, WithName("idx"))
+	y := Must(Embedding(table, idx))
+	assert.Equal(tensor.Shape{3}, y.Shape())
+	if _, err := Embedding(table, table); err == nil {
+		t.Error("Expected an error when the indices are not of Int")
+	}
+}
+
+func TestSoftmaxCrossEntropy(t *testing.T) {
+	assert := assert.New(t)
+	logitsBack := []float64{1, 2, 3, 1000, 0, -1000}
+	lse := math.Log(math.Exp(-2)+math.Exp(-1)+1) + 3
+	softmax := []float64{math.Exp(1 - lse), math.Exp(2 - lse), math.Exp(3 - lse), 1, 0, 0}
+
+	targets := []struct {
+		name      string
+		targets   func(g *ExprGraph) *Node
+		smoothing float64
+	}{
+		{"int", func(g *ExprGraph) *Node {
+			return NewVector(g, Int, WithShape(2), WithName("t"), WithValue(tensor.New(tensor.WithBacking([]int{2, 0}), tensor.WithShape(2))))
+		}, 0},
+		{"one-hot", func(g *ExprGraph) *Node {
+			return NewMatrix(g, Float64, WithShape(2, 3), WithName("t"), WithValue(tensor.New(tensor.WithBacking([]float64{0, 0, 1, 1, 0, 0}), tensor.WithShape(2, 3))))
+		}, 0},
+		{"smoothed", func(g *ExprGraph) *Node {
+			return NewVector(g, Int, WithShape(2), WithName("t"), WithValue(tensor.New(tensor.WithBacking([]int{2, 0}), tensor.WithShape(2))))
+		}, 0.3},
+	}
+
+	for _, tt := range targets {
+		// the smoothed targets
+		oneHot := []float64{0, 0, 1, 1, 0, 0}
+		for i := range oneHot {
+			oneHot[i] = oneHot[i]*(1-tt.smoothing) + tt.smoothing/3
+		}
+		correctLoss := []float64{
+			lse - oneHot[0]*1 - oneHot[1]*2 - oneHot[2]*3,
+			1000 - oneHot[3]*1000 + oneHot[5]*1000,
+		}
+		correctGrad := make([]float64, 6)
+		for i := range correctGrad {
+			correctGrad[i] = softmax[i] - oneHot[i]
+		}
+
+		var grads [2]Value
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			logits := NewMatrix(g, Float64, WithShape(2, 3), WithName("logits"), WithValue(tensor.New(tensor.WithBacking(logitsBack), tensor.WithShape(2, 3))))
+			var loss *Node
+			if tt.smoothing > 0 {
+				loss = Must(SmoothedSoftmaxCrossEntropy(logits, tt.targets(g), 1, tt.smoothing))
+			} else {
+				loss = Must(SoftmaxCrossEntropy(logits, tt.targets(g), 1))
+			}
+			assert.Equal(tensor.Shape{2}, loss.Shape())
+			cost := Must(Sum(loss))
+
+			var m VM
+			if i == 0 {
+				if _, err := Grad(cost, logits); err != nil {
+					t.Fatalf("%v: %+v", tt.name, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", tt.name, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err := m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", tt.name, err)
+			}
+			assert.True(floatsEqual64(correctLoss, extractF64s(loss.Value())), "%v: Got %v", tt.name, loss.Value())
+
+			var err error
+			if grads[i], err = logits.Grad(); err != nil {
+				t.Fatalf("%v: %+v", tt.name, err)
+			}
+			assert.True(floatsEqual64(correctGrad, extractF64s(grads[i])), "%v: Got %v", tt.name, grads[i])
+		}
+	}
+
+	// the classes may be along any axis
+	g := NewGraph()
+	logitsT := NewMatrix(g, Float64, WithShape(3, 2), WithName("logits"), WithValue(tensor.New(tensor.WithBacking([]float64{1, 1000, 2, 0, 3, -1000}), tensor.WithShape(3, 2))))
+	classes := NewVector(g, Int, WithShape(2), WithName("t"), WithValue(tensor.New(tensor.WithBacking([]int{2, 0}), tensor.WithShape(2))))
+	lossT := Must(SoftmaxCrossEntropy(logitsT, classes, 0))
+	if err := NewLispMachine(g, ExecuteFwdOnly()).RunAll(); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(floatsEqual64([]float64{lse - 3, 0}, extractF64s(lossT.Value())), "Got %v", lossT.Value())
+
+	// along the first axis of a vector, the loss is a scalar
+	g = NewGraph()
+	logits := NewVector(g, Float32, WithShape(3), WithName("logits"), WithValue(tensor.New(tensor.WithBacking([]float32{1, 2, 3}), tensor.WithShape(3))))
+	target := NewScalar(g, Int, WithName("target"), WithValue(2))
+	loss := Must(SoftmaxCrossEntropy(logits, target, 0))
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(floatEquals32(float32(lse-3), loss.Value().(*F32).any()), "Got %v", loss.Value())
+
+	if _, err := SoftmaxCrossEntropy(logits, target, 1); err == nil {
+		t.Error("Expected an error for an axis out of bounds")
+	}
+}
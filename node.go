@@ -144,6 +144,8 @@ func WithValue(any interface{}) NodeConsOpt {
 }
 
 // WithInit is a node construction option to initialize a *Node with the InitWFn provided.
+// The package level InitWFns (such as GlorotU) draw from DefaultRNG. To draw from the RNG of the graph, use its methods:
+//		WithInit(g.RNG().GlorotU(1))
 func WithInit(fn InitWFn) NodeConsOpt {
 	f := func(n *Node) {
 		dt, err := dtypeOf(n.t)
@@ -221,7 +223,7 @@ func (n *Node) isArg() bool      { return n.op == nil }
 func (n *Node) isInput() bool    { return (n.isArg() || n.isRandom()) && !n.isStmt }
 func (n *Node) isMutable() bool  { return !n.isInput() && n.op.ReturnsPtr() }
 func (n *Node) isConstant() bool { _, ok := n.op.(constant); return ok }
-func (n *Node) isRandom() bool   { _, ok := n.op.(randomOp); return ok }
+func (n *Node) isRandom() bool   { _, ok := n.op.(*randomOp); return ok }
 
 func (n *Node) isRoot() bool {
 	if n.g == nil {
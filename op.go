@@ -127,6 +127,19 @@ type SDOp interface {
 	SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error)
 }
 
+// A TrainModeOp is an Op that behaves differently when training and when doing inference (i.e. evaluation).
+// Examples are batch normalization, dropout and random ops marked with MeanInEvalMode. The VMs inform these ops of the mode they are running in
+// before executing them.
+//
+// The mode is state of the op, as are things like the mask of a dropout or the running averages of a batch normalization.
+// So a graph with TrainModeOps can't be run by two VMs at the same time.
+type TrainModeOp interface {
+	Op
+
+	// SetTraining sets the mode of the op. It's true when training, and false when doing inference.
+	SetTraining(training bool)
+}
+
 // ReductionOp changes the shape of the node
 type ReductionOp interface {
 	Op
@@ -160,6 +173,13 @@ type CLDoer interface {
 	CLDo(inputs ...Value) (Value, error)
 }
 
+// a viewOp is an Op whose result shares the backing data of its input. The register allocator keeps the input and the result
+// live for as long as either of them is used, so that neither is overwritten while the other is still needed.
+type viewOp interface {
+	Op
+	isView() bool
+}
+
 // a constant is an unchanging value. I think everyone would know what a constant is
 // a constant op is an op that creates a constant. It is also a Value of a constant value
 type constant interface {
@@ -5,6 +5,8 @@ This file holds all the Ops that are related to doing math-related work. Due to
 mathematical operations, they're classified into 3 main types:
 	elemBinOp - a representation of a binary mathematical operation that is performed elementwise (example: +, *, -, or >, <)
 	elemUnaryOp - a representation of a mathematical operation that is performed elmentwise
+	logicalOp - a representation of a logical operation (and, or, not) that is performed elementwise on Bools
+	whereOp - a representation of an elementwise selection between two values, driven by a condition
 	linAlgBinOp - a representation of a binary mathematical operation that is performed on matrices
 
 The individual operators are further exanded on operator*.go files. Their datatypes are often embedded in the datatypes here.
@@ -22,6 +24,7 @@ import (
 
 	"github.com/chewxy/gorgonia/tensor"
 	"github.com/chewxy/hm"
+	"github.com/gonum/blas"
 	"github.com/pkg/errors"
 )
 
@@ -361,7 +364,8 @@ type elemUnaryOp struct {
 	ʘUnaryOperator
 
 	argTensor     bool
-	numericResult bool // indicate if boolean results should be converted to 1 and 0 in the respective Dtype
+	numericResult bool    // indicate if boolean results should be converted to 1 and 0 in the respective Dtype
+	alpha         float64 // the slope of the negative part of a leaky ReLU. The other operators ignore it
 }
 
 func newElemUnaryOp(op ʘUnaryOperatorType, a *Node) elemUnaryOp {
@@ -383,6 +387,7 @@ func newElemUnaryOp(op ʘUnaryOperatorType, a *Node) elemUnaryOp {
 	return elemUnaryOp{
 		ʘUnaryOperator: operator,
 		argTensor:      isTensor,
+		alpha:          defaultLeakyReluSlope,
 	}
 }
 
@@ -469,6 +474,10 @@ func (op elemUnaryOp) WriteHash(h hash.Hash) {
 	} else {
 		h.Write([]byte{0})
 	}
+
+	if err := binary.Write(h, binary.LittleEndian, op.alpha); err != nil {
+		panic(err)
+	}
 }
 
 func (op elemUnaryOp) Hashcode() uint32 {
@@ -494,17 +503,14 @@ func (op elemUnaryOp) do(inputs []Value, opts ...tensor.FuncOpt) (retVal Value,
 	}
 
 	a := inputs[0]
+	if kernel := activationKernel(op.unaryOpType(), false, op.alpha, a.Dtype()); kernel != nil {
+		return doUnaryKernel(kernel, a, opts...)
+	}
+
+	fn := op.fn()
 	switch v := a.(type) {
 	case tensor.Tensor:
 		var t tensor.Tensor
-		var fn interface{}
-		switch opFn := op.ʘUnaryOperator.(type) {
-		case *sf64UnaryOperator:
-			fn = (func(float64) float64)(*opFn)
-		case *sf32UnaryOperator:
-			fn = (func(float32) float32)(*opFn)
-		}
-
 		if t, err = v.Apply(fn, opts...); err != nil {
 			return nil, errors.Wrap(err, applyFail)
 		}
@@ -515,13 +521,13 @@ func (op elemUnaryOp) do(inputs []Value, opts ...tensor.FuncOpt) (retVal Value,
 		case tensor.Float32:
 			vs := v.(*F32)
 			f := float32(*vs)
-			opFn := op.ʘUnaryOperator.(*sf32UnaryOperator)
-			retVal, _ = anyToScalar((*opFn)(f))
+			opFn := fn.(func(float32) float32)
+			retVal, _ = anyToScalar(opFn(f))
 		case tensor.Float64:
 			vs := v.(*F64)
 			f := float64(*vs)
-			opFn := op.ʘUnaryOperator.(*sf64UnaryOperator)
-			retVal, _ = anyToScalar((*opFn)(f))
+			opFn := fn.(func(float64) float64)
+			retVal, _ = anyToScalar(opFn(f))
 		default:
 			return nil, errors.Errorf(nyiFail, "elemUnaryOp.do", vt)
 		}
@@ -529,6 +535,719 @@ func (op elemUnaryOp) do(inputs []Value, opts ...tensor.FuncOpt) (retVal Value,
 	return
 }
 
+// fn returns the function that is applied elementwise: a func(float64) float64 or a func(float32) float32
+func (op elemUnaryOp) fn() interface{} {
+	switch opFn := op.ʘUnaryOperator.(type) {
+	case *sf64UnaryOperator:
+		return (func(float64) float64)(*opFn)
+	case *sf32UnaryOperator:
+		return (func(float32) float32)(*opFn)
+	}
+	return nil
+}
+
+// unaryKernel is a generated Dense kernel of the tensor package, which is applied elementwise
+type unaryKernel func(a tensor.Tensor, opts ...tensor.FuncOpt) (tensor.Tensor, error)
+
+// activationKernel returns the kernel of the activation function, or of its derivative if diff is true.
+// The other operators have no kernel, and are applied with Apply.
+func activationKernel(which ʘUnaryOperatorType, diff bool, alpha float64, dt tensor.Dtype) unaryKernel {
+	switch which {
+	case leakyReluOpType:
+		var alphaVal interface{} = alpha
+		if dt == Float32 {
+			alphaVal = float32(alpha)
+		}
+		f := tensor.LeakyRelu
+		if diff {
+			f = tensor.LeakyReluDiff
+		}
+		return func(a tensor.Tensor, opts ...tensor.FuncOpt) (tensor.Tensor, error) { return f(a, alphaVal, opts...) }
+	case eluOpType:
+		if diff {
+			return tensor.EluDiff
+		}
+		return tensor.Elu
+	case seluOpType:
+		if diff {
+			return tensor.SeluDiff
+		}
+		return tensor.Selu
+	case geluOpType:
+		if diff {
+			return tensor.GeluDiff
+		}
+		return tensor.Gelu
+	case geluTanhOpType:
+		if diff {
+			return tensor.GeluTanhDiff
+		}
+		return tensor.GeluTanh
+	case swishOpType:
+		if diff {
+			return tensor.SwishDiff
+		}
+		return tensor.Swish
+	case mishOpType:
+		if diff {
+			return tensor.MishDiff
+		}
+		return tensor.Mish
+	case hardSigmoidOpType:
+		if diff {
+			return tensor.HardSigmoidDiff
+		}
+		return tensor.HardSigmoid
+	}
+	return nil
+}
+
+// doUnaryKernel applies the kernel on a Value. Scalars are wrapped in a scalar *tensor.Dense.
+func doUnaryKernel(kernel unaryKernel, a Value, opts ...tensor.FuncOpt) (retVal Value, err error) {
+	var t tensor.Tensor
+	switch v := a.(type) {
+	case tensor.Tensor:
+		if t, err = kernel(v, opts...); err != nil {
+			return nil, errors.Wrap(err, applyFail)
+		}
+		return t, nil
+	case Scalar:
+		if t, err = kernel(tensor.New(tensor.FromScalar(v.Data())), tensor.UseUnsafe()); err != nil {
+			return nil, errors.Wrap(err, applyFail)
+		}
+		retVal, _ = anyToScalar(t.ScalarValue())
+		return
+	}
+	return nil, errors.Errorf(nyiTypeFail, "doUnaryKernel", a)
+}
+
+// activationDiffOp computes the derivative of an activation function elementwise, given the input of the activation function.
+// It is what the gradients of the activation functions are made of. The derivatives themselves are not differentiable.
+type activationDiffOp struct {
+	which ʘUnaryOperatorType
+	alpha float64 // slope of a leaky ReLU
+}
+
+// newActivationDiffOp creates the op that differentiates y, which is the output of the given activation function
+func newActivationDiffOp(which ʘUnaryOperatorType, y *Node) activationDiffOp {
+	var alpha float64
+	if euo, ok := y.op.(elemUnaryOp); ok {
+		alpha = euo.alpha
+	}
+	return activationDiffOp{which: which, alpha: alpha}
+}
+
+func (op activationDiffOp) Arity() int { return 1 }
+
+// activationDiffOp has this type:
+//		op :: (Arithable a) ⇒ a → a
+func (op activationDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(a, a)
+}
+
+func (op activationDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	return s.Clone(), nil
+}
+
+func (op activationDiffOp) DiffWRT(inputs int) []bool { return make([]bool, inputs) }
+
+func (op activationDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op activationDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+func (op activationDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	kernel := activationKernel(op.which, true, op.alpha, inputs[0].Dtype())
+	if kernel == nil {
+		return nil, errors.Errorf("%v is not an activation function", op.which)
+	}
+	return doUnaryKernel(kernel, inputs[0])
+}
+
+func (op activationDiffOp) ReturnsPtr() bool     { return false }
+func (op activationDiffOp) CallsExtern() bool    { return false }
+func (op activationDiffOp) OverwritesInput() int { return -1 }
+
+func (op activationDiffOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "%v %v", op, op.alpha) }
+
+func (op activationDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op activationDiffOp) String() string { return fmt.Sprintf("d%v/dx", op.which) }
+
+/* LOGICAL OPERATIONS */
+
+type logicalOpType byte
+
+const (
+	andOpType logicalOpType = iota
+	orOpType
+	notOpType
+)
+
+// logicalOp is a logical operation that is performed elementwise on Bool scalars or tensors. Both operands of a binary logical operation
+// have to be of the same shape. Logical operations are not differentiable.
+type logicalOp struct {
+	which logicalOpType
+	d     int // dims of the operands
+}
+
+func (op logicalOp) Arity() int {
+	if op.which == notOpType {
+		return 1
+	}
+	return 2
+}
+
+// logicalOp has either of these types:
+//		op :: Tensor-d Bool → Tensor-d Bool → Tensor-d Bool
+//		op :: Tensor-d Bool → Tensor-d Bool
+// where a Tensor-0 Bool is a Bool.
+func (op logicalOp) Type() hm.Type {
+	var t hm.Type = Bool
+	if op.d > 0 {
+		t = newTensorType(op.d, Bool)
+	}
+	if op.which == notOpType {
+		return hm.NewFnType(t, t)
+	}
+	return hm.NewFnType(t, t, t)
+}
+
+func (op logicalOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	for _, in := range inputs[1:] {
+		s2, ok := in.(tensor.Shape)
+		if !ok {
+			return nil, errors.Errorf("Expected a shape. Got %T instead", in)
+		}
+		if !s.Eq(s2) {
+			return nil, errors.Errorf("%v expects the operands to be of the same shape. Got %v and %v instead", op, s, s2)
+		}
+	}
+	return s.Clone(), nil
+}
+
+func (op logicalOp) DiffWRT(inputs int) []bool { return make([]bool, inputs) }
+
+func (op logicalOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+// DoDiff is a no-op, as there is nothing to backpropagate through Bools.
+func (op logicalOp) DoDiff(inputs Nodes, output *Node) error { return nil }
+
+func (op logicalOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	operands := make([][]bool, len(inputs))
+	for i, in := range inputs {
+		if operands[i], err = boolsOf(in); err != nil {
+			return nil, errors.Wrapf(err, doFail, op)
+		}
+	}
+	a := operands[0]
+	if len(operands) == 2 && len(operands[1]) != len(a) {
+		return nil, errors.Errorf("%v expects the operands to be of the same size. Got %d and %d instead", op, len(a), len(operands[1]))
+	}
+
+	res := make([]bool, len(a))
+	for i, v := range a {
+		switch op.which {
+		case andOpType:
+			res[i] = v && operands[1][i]
+		case orOpType:
+			res[i] = v || operands[1][i]
+		case notOpType:
+			res[i] = !v
+		}
+	}
+
+	if _, ok := inputs[0].(Scalar); ok {
+		return newB(res[0]), nil
+	}
+	return tensor.New(tensor.WithBacking(res), tensor.WithShape(inputs[0].Shape().Clone()...)), nil
+}
+
+func (op logicalOp) ReturnsPtr() bool     { return false }
+func (op logicalOp) CallsExtern() bool    { return false }
+func (op logicalOp) OverwritesInput() int { return -1 }
+
+func (op logicalOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "%v%d", op, op.d) }
+
+func (op logicalOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op logicalOp) String() string {
+	switch op.which {
+	case andOpType:
+		return "∧"
+	case orOpType:
+		return "∨"
+	default:
+		return "¬"
+	}
+}
+
+/* SELECTION */
+
+// whereOp selects elementwise from a where cond is true, and from b otherwise. cond is either a Bool or a 0/1 value of
+// the same type as a and b. Scalar inputs are used for every element of the result; all the other inputs have to be of the result's shape.
+type whereOp struct {
+	shape    tensor.Shape // shape of the result
+	scalars  [3]bool      // whether cond, a and b are scalars
+	condBool bool         // whether cond is a Bool
+}
+
+func (op whereOp) Arity() int { return 3 }
+
+// whereOp has this type:
+//		op :: Tensor-d c → Tensor-d a → Tensor-d a → Tensor-d a
+// where c is either Bool or a, and any of the inputs may be a scalar.
+func (op whereOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	var c hm.Type = a
+	if op.condBool {
+		c = Bool
+	}
+	return hm.NewFnType(op.inputType(0, c), op.inputType(1, a), op.inputType(2, a), op.inputType(-1, a))
+}
+
+// inputType returns the type of the ith input, or the type of the result if i is -1
+func (op whereOp) inputType(i int, of hm.Type) hm.Type {
+	if len(op.shape) == 0 || (i >= 0 && op.scalars[i]) {
+		return of
+	}
+	return newTensorType(len(op.shape), of)
+}
+
+func (op whereOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	for i, in := range inputs {
+		if op.scalars[i] {
+			continue
+		}
+		s, ok := in.(tensor.Shape)
+		if !ok {
+			return nil, errors.Errorf("Expected a shape. Got %T instead", in)
+		}
+		if !sameShape(s, op.shape) {
+			return nil, errors.Errorf("%v expects input %d to be of shape %v. Got %v instead", op, i, op.shape, s)
+		}
+	}
+	if len(op.shape) == 0 {
+		return scalarShape, nil
+	}
+	return op.shape.Clone(), nil
+}
+
+func (op whereOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var mask []bool
+	var xs [][]float64
+	if mask, err = op.mask(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if xs, err = float64sOfValues(inputs[1:]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	res := make([]float64, op.size())
+	for i := range res {
+		if boolAt(mask, i) {
+			res[i] = f64At(xs[0], i)
+		} else {
+			res[i] = f64At(xs[1], i)
+		}
+	}
+	return valueFromFloat64s(inputs[1].Dtype(), op.shape, res)
+}
+
+// mask returns the values of cond as a []bool. Non zero values are true.
+func (op whereOp) mask(cond Value) ([]bool, error) {
+	if op.condBool {
+		return boolsOf(cond)
+	}
+
+	c, err := float64sOf(cond)
+	if err != nil {
+		return nil, err
+	}
+	retVal := make([]bool, len(c))
+	for i, v := range c {
+		retVal[i] = v != 0
+	}
+	return retVal, nil
+}
+
+func (op whereOp) size() int {
+	if len(op.shape) == 0 {
+		return 1
+	}
+	return op.shape.TotalSize()
+}
+
+// boolAt returns xs[i], or the only element of xs if it came from a scalar
+func boolAt(xs []bool, i int) bool {
+	if len(xs) == 1 {
+		return xs[0]
+	}
+	return xs[i]
+}
+
+// f64At returns xs[i], or the only element of xs if it came from a scalar
+func f64At(xs []float64, i int) float64 {
+	if len(xs) == 1 {
+		return xs[0]
+	}
+	return xs[i]
+}
+
+func (op whereOp) ReturnsPtr() bool     { return false }
+func (op whereOp) CallsExtern() bool    { return false }
+func (op whereOp) OverwritesInput() int { return -1 }
+
+func (op whereOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "where%v%v%t", op.shape, op.scalars, op.condBool)
+}
+
+func (op whereOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op whereOp) String() string { return "Where" }
+
+func (op whereOp) DiffWRT(inputs int) []bool { return []bool{false, true, true} }
+
+func (op whereOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	retVal = make(Nodes, 3)
+	for i := 1; i < 3; i++ {
+		if retVal[i], err = applyOp(whereDiffOp{whereOp: op, left: i == 1}, inputs[0], grad); err != nil {
+			return nil, errors.Wrap(err, applyOpFail)
+		}
+		retVal[i].setGroup(gradClust)
+	}
+	return
+}
+
+func (op whereOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	ydv := output.boundTo.(*dualValue)
+	for i := 1; i < 3; i++ {
+		xdv := inputs[i].boundTo.(*dualValue)
+
+		var d Value
+		if d, err = (whereDiffOp{whereOp: op, left: i == 1}).Do(inputs[0].Value(), ydv.d); err != nil {
+			return errors.Wrapf(err, doFail, op)
+		}
+
+		add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+		if d, err = add.UnsafeDo(xdv.d, d); err != nil {
+			return errors.Wrapf(err, unsafeDoFail, add)
+		}
+		if err = xdv.SetDeriv(d); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// whereDiffOp routes the gradient of a whereOp to one of its branches: a if left is true, b otherwise.
+// The gradient is zero wherever the other branch was selected, and is summed if the branch is a scalar.
+type whereDiffOp struct {
+	whereOp
+	left bool
+}
+
+func (op whereDiffOp) Arity() int { return 2 }
+
+func (op whereDiffOp) branch() int {
+	if op.left {
+		return 1
+	}
+	return 2
+}
+
+// whereDiffOp has this type:
+//		op :: Tensor-d c → Tensor-d a → Tensor-d a
+// where the result is a scalar if the branch is a scalar.
+func (op whereDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	var c hm.Type = a
+	if op.condBool {
+		c = Bool
+	}
+	return hm.NewFnType(op.inputType(0, c), op.inputType(-1, a), op.inputType(op.branch(), a))
+}
+
+func (op whereDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	if len(op.shape) == 0 || op.scalars[op.branch()] {
+		return scalarShape, nil
+	}
+	return op.shape.Clone(), nil
+}
+
+func (op whereDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var mask []bool
+	var grad []float64
+	if mask, err = op.mask(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if grad, err = float64sOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	res := make([]float64, op.size())
+	var sum float64
+	for i := range res {
+		if boolAt(mask, i) == op.left {
+			res[i] = f64At(grad, i)
+			sum += res[i]
+		}
+	}
+
+	if len(op.shape) == 0 || op.scalars[op.branch()] {
+		return valueFromFloat64s(inputs[1].Dtype(), nil, []float64{sum})
+	}
+	return valueFromFloat64s(inputs[1].Dtype(), op.shape, res)
+}
+
+func (op whereDiffOp) WriteHash(h hash.Hash) {
+	op.whereOp.WriteHash(h)
+	fmt.Fprintf(h, "Diff%t", op.left)
+}
+
+func (op whereDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op whereDiffOp) String() string { return fmt.Sprintf("∂Where%d", op.branch()) }
+
+func (op whereDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false} }
+
+func (op whereDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op whereDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+/* CLAMP */
+
+// clampOp clamps the values of its input to [min, max]. The gradient is masked to where the input is within [min, max],
+// unless the op is straight-through, in which case the gradient passes through unchanged.
+type clampOp struct {
+	min, max        float64
+	straightThrough bool
+}
+
+func (op clampOp) Arity() int { return 1 }
+
+// clampOp has this type:
+//		op :: (Arithable a) ⇒ a → a
+func (op clampOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(a, a)
+}
+
+func (op clampOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	return s.Clone(), nil
+}
+
+func (op clampOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	// the bounds have to be of the Dtype of the input
+	var min, max interface{} = op.min, op.max
+	if inputs[0].Dtype() == Float32 {
+		min, max = float32(op.min), float32(op.max)
+	}
+	kernel := func(a tensor.Tensor, opts ...tensor.FuncOpt) (tensor.Tensor, error) {
+		return tensor.Clamp(a, min, max, opts...)
+	}
+	return doUnaryKernel(kernel, inputs[0])
+}
+
+func (op clampOp) ReturnsPtr() bool     { return false }
+func (op clampOp) CallsExtern() bool    { return false }
+func (op clampOp) OverwritesInput() int { return -1 }
+
+func (op clampOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "%v", op) }
+
+func (op clampOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op clampOp) String() string {
+	if op.straightThrough {
+		return fmt.Sprintf("ClampST[%v, %v]", op.min, op.max)
+	}
+	return fmt.Sprintf("Clamp[%v, %v]", op.min, op.max)
+}
+
+func (op clampOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op clampOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	if op.straightThrough {
+		return Nodes{grad}, nil
+	}
+
+	var d *Node
+	if d, err = applyOp(clampDiffOp{op}, inputs[0], grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	d.setGroup(gradClust)
+	return Nodes{d}, nil
+}
+
+func (op clampOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	d := ydv.d
+	if !op.straightThrough {
+		if d, err = (clampDiffOp{op}).Do(xdv.Value, ydv.d); err != nil {
+			return errors.Wrapf(err, doFail, op)
+		}
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if d, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return xdv.SetDeriv(d)
+}
+
+// clampDiffOp masks the gradient of a clampOp (its second input) to where x (its first input) is within [min, max].
+type clampDiffOp struct {
+	clampOp
+}
+
+func (op clampDiffOp) Arity() int { return 2 }
+
+// clampDiffOp has this type:
+//		op :: (Arithable a) ⇒ a → a → a
+func (op clampDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(a, a, a)
+}
+
+func (op clampDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.clampOp.InferShape(inputs[0])
+}
+
+func (op clampDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var xs [][]float64
+	if xs, err = float64sOfValues(inputs); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	x, grad := xs[0], xs[1]
+	if len(x) != len(grad) {
+		return nil, errors.Errorf("%v expects the input and the gradient to be of the same size. Got %d and %d instead", op, len(x), len(grad))
+	}
+
+	res := make([]float64, len(x))
+	for i, v := range x {
+		if v >= op.min && v <= op.max {
+			res[i] = grad[i]
+		}
+	}
+	return valueFromFloat64s(inputs[1].Dtype(), inputs[1].Shape(), res)
+}
+
+func (op clampDiffOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "%v", op) }
+
+func (op clampDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op clampDiffOp) String() string { return fmt.Sprintf("∂Clamp[%v, %v]", op.min, op.max) }
+
+func (op clampDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false} }
+
+func (op clampDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op clampDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
 /* LINEAR ALGEBRA RELATED OPERATIONS */
 
 type linAlgBinOp struct {
@@ -580,6 +1299,12 @@ func (op linAlgBinOp) InferShape(inputs ...DimSizer) (retVal tensor.Shape, err e
 	case outerProdOperator:
 		// outerprods only handles vec x vec for now
 		retVal = tensor.Shape{x.TotalSize(), y.TotalSize()}
+	case batchedMatMulOperator:
+		var batch, m, n int
+		if batch, m, n, _, err = op.batchedDims(x, y); err != nil {
+			return nil, err
+		}
+		retVal = tensor.Shape{batch, m, n}
 	}
 	return
 }
@@ -648,7 +1373,7 @@ func (op linAlgBinOp) String() string {
 	var buf bytes.Buffer
 
 	switch op.āBinaryOperator {
-	case matMulOperator, matVecMulOperator:
+	case matMulOperator, matVecMulOperator, batchedMatMulOperator:
 		buf.WriteString("A")
 	case vecDotOperator, outerProdOperator:
 		buf.WriteString("a")
@@ -659,7 +1384,7 @@ func (op linAlgBinOp) String() string {
 	}
 
 	switch op.āBinaryOperator {
-	case matMulOperator:
+	case matMulOperator, batchedMatMulOperator:
 		fmt.Fprintf(&buf, " %v B", op.āBinaryOperator)
 	case matVecMulOperator, vecDotOperator, outerProdOperator:
 		fmt.Fprintf(&buf, " %v b", op.āBinaryOperator)
@@ -677,6 +1402,10 @@ func (op linAlgBinOp) IncrDo(incr Value, inputs ...Value) (err error) {
 	t, ok := incr.(tensor.Tensor)
 
 	if ok {
+		if op.āBinaryOperator == batchedMatMulOperator {
+			_, err = op.batchedDo(inputs, t, true)
+			return
+		}
 		_, err = op.do(inputs, tensor.WithIncr(t))
 		return
 	}
@@ -702,6 +1431,9 @@ func (op linAlgBinOp) UsePreallocDo(prealloc Value, inputs ...Value) (retVal Val
 		return nil, errors.Errorf("Expected Tensor as preallocated value. Got %v of %T instead", prealloc, prealloc)
 	}
 
+	if op.āBinaryOperator == batchedMatMulOperator {
+		return op.batchedDo(inputs, t, false)
+	}
 	return op.do(inputs, tensor.WithReuse(t))
 }
 
@@ -715,6 +1447,10 @@ func (op linAlgBinOp) do(inputs []Value, opts ...tensor.FuncOpt) (retVal Value,
 		return
 	}
 
+	if op.āBinaryOperator == batchedMatMulOperator {
+		return op.batchedDo(inputs, nil, false)
+	}
+
 	a, b := inputs[0].(tensor.Tensor), inputs[1].(tensor.Tensor)
 
 	if op.transA {
@@ -750,3 +1486,97 @@ func (op linAlgBinOp) do(inputs []Value, opts ...tensor.FuncOpt) (retVal Value,
 	return
 
 }
+
+// batchedDims checks the shapes of the operands of a batched matrix multiplication, taking the transpositions into account.
+// It returns the size of the batch, and the m, n and k of the GEMM calls.
+func (op linAlgBinOp) batchedDims(x, y tensor.Shape) (batch, m, n, k int, err error) {
+	if len(x) != 3 || len(y) != 3 {
+		return 0, 0, 0, 0, errors.Errorf("Batched matrix multiplication requires both operands to be 3-tensors. Got %v and %v", x, y)
+	}
+
+	m, k = x[1], x[2]
+	if op.transA {
+		m, k = k, m
+	}
+	k2, n := y[1], y[2]
+	if op.transB {
+		k2, n = n, k2
+	}
+
+	if x[0] != y[0] || k != k2 {
+		return 0, 0, 0, 0, errors.Errorf("Incompatible shapes for %v: %v and %v", op, x, y)
+	}
+	return x[0], m, n, k, nil
+}
+
+// batchedDo carries out a batched matrix multiplication with one GEMM call through the BLAS set by Use for each matrix in the batch.
+// The result is written into into if it's not nil, and is added to it if incr is true.
+func (op linAlgBinOp) batchedDo(inputs []Value, into tensor.Tensor, incr bool) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var a, b *tensor.Dense
+	if a, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if b, err = contiguousDense(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	var batch, m, n, k int
+	if batch, m, n, k, err = op.batchedDims(a.Shape(), b.Shape()); err != nil {
+		return nil, err
+	}
+	if a.Dtype() != b.Dtype() {
+		return nil, errors.Errorf("Expected both operands of %v to be of the same Dtype. Got %v and %v", op, a.Dtype(), b.Dtype())
+	}
+
+	var c *tensor.Dense
+	switch {
+	case into == nil:
+		c = tensor.New(tensor.Of(a.Dtype()), tensor.WithShape(batch, m, n))
+	default:
+		var ok bool
+		if c, ok = into.(*tensor.Dense); !ok || c.IsMaterializable() {
+			return nil, errors.Errorf(nyiTypeFail, "batched matrix multiplication into", into)
+		}
+		if !sameShape(c.Shape(), tensor.Shape{batch, m, n}) || c.Dtype() != a.Dtype() {
+			return nil, errors.Errorf("Cannot write the result of %v into a %v of shape %v", op, c.Dtype(), c.Shape())
+		}
+	}
+
+	tA, tB := blas.NoTrans, blas.NoTrans
+	if op.transA {
+		tA = blas.Trans
+	}
+	if op.transB {
+		tB = blas.Trans
+	}
+	lda, ldb := a.Shape()[2], b.Shape()[2]
+	as, bs, cs := m*k, k*n, m*n
+
+	switch ad := a.Data().(type) {
+	case []float64:
+		bd, cd := b.Data().([]float64), c.Data().([]float64)
+		var beta float64
+		if incr {
+			beta = 1
+		}
+		for i := 0; i < batch; i++ {
+			whichblas.Dgemm(tA, tB, m, n, k, 1, ad[i*as:(i+1)*as], lda, bd[i*bs:(i+1)*bs], ldb, beta, cd[i*cs:(i+1)*cs], n)
+		}
+	case []float32:
+		bd, cd := b.Data().([]float32), c.Data().([]float32)
+		var beta float32
+		if incr {
+			beta = 1
+		}
+		for i := 0; i < batch; i++ {
+			whichblas.Sgemm(tA, tB, m, n, k, 1, ad[i*as:(i+1)*as], lda, bd[i*bs:(i+1)*bs], ldb, beta, cd[i*cs:(i+1)*cs], n)
+		}
+	default:
+		return nil, errors.Errorf(nyiTypeFail, "batched matrix multiplication", ad)
+	}
+	return c, nil
+}
@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
-	"time"
+	"math"
 
 	"github.com/chewxy/gorgonia/tensor"
 	"github.com/chewxy/hm"
-	"github.com/leesper/go_rng"
+	"github.com/chewxy/math32"
+	"github.com/gonum/blas"
 	"github.com/pkg/errors"
 )
 
@@ -29,29 +30,37 @@ const (
 	binomial
 )
 
+// randomOp draws random values from a distribution. If evalMean is set, it returns the mean of the distribution when doing inference,
+// so that the graph is deterministic.
 type randomOp struct {
 	which randomness
 	shape tensor.Shape
 	dt    tensor.Dtype
 
-	a, b float64 // when uniform, a,b = low, high; when gaussian, a,b = mean, stdev
+	a, b float64 // when uniform, a,b = low, high; when gaussian, a,b = mean, stdev; when binomial, a,b = trials, prob
+
+	training bool
+	evalMean bool // return the mean when not training (see MeanInEvalMode)
+	rng      *RNG
 }
 
-func makeRandomOp(which randomness, dt tensor.Dtype, a, b float64, shape ...int) randomOp {
-	return randomOp{
-		which: which,
-		shape: tensor.Shape(shape),
-		dt:    dt,
-		a:     a,
-		b:     b,
+func makeRandomOp(which randomness, dt tensor.Dtype, a, b float64, rng *RNG, shape ...int) *randomOp {
+	return &randomOp{
+		which:    which,
+		shape:    tensor.Shape(shape),
+		dt:       dt,
+		a:        a,
+		b:        b,
+		training: true,
+		rng:      rng,
 	}
 }
 
-func (op randomOp) Arity() int { return 0 }
+func (op *randomOp) Arity() int { return 0 }
 
 // randomOp :: a
 // randomOp :: Tensor a
-func (op randomOp) Type() hm.Type {
+func (op *randomOp) Type() hm.Type {
 	if op.shape.IsScalar() {
 		return op.dt
 	}
@@ -59,34 +68,38 @@ func (op randomOp) Type() hm.Type {
 	return tt
 }
 
-func (op randomOp) InferShape(...DimSizer) (tensor.Shape, error) { return op.shape, nil }
+func (op *randomOp) InferShape(...DimSizer) (tensor.Shape, error) { return op.shape, nil }
+
+func (op *randomOp) Do(...Value) (retVal Value, err error) {
+	if op.evalMean && !op.training {
+		return op.mean()
+	}
 
-func (op randomOp) Do(...Value) (retVal Value, err error) {
 	if op.shape.IsScalar() {
 		var v interface{}
 		switch op.dt {
 		case Float64:
 			switch op.which {
 			case uniform:
-				rand := rng.NewUniformGenerator(time.Now().UnixNano())
+				rand := op.rng.uniform()
 				v = rand.Float64Range(op.a, op.b)
 			case gaussian:
-				rand := rng.NewGaussianGenerator(time.Now().UnixNano())
+				rand := op.rng.gaussian()
 				v = rand.Gaussian(op.a, op.b)
 			case binomial:
-				rand := rng.NewBinomialGenerator(time.Now().UnixNano())
+				rand := op.rng.binomial()
 				v = float64(rand.Binomial(int64(op.a), op.b))
 			}
 		case Float32:
 			switch op.which {
 			case uniform:
-				rand := rng.NewUniformGenerator(time.Now().UnixNano())
+				rand := op.rng.uniform()
 				v = rand.Float32Range(float32(op.a), float32(op.b))
 			case gaussian:
-				rand := rng.NewGaussianGenerator(time.Now().UnixNano())
+				rand := op.rng.gaussian()
 				v = float32(rand.Gaussian(op.a, op.b))
 			case binomial:
-				rand := rng.NewBinomialGenerator(time.Now().UnixNano())
+				rand := op.rng.binomial()
 				v = float32(rand.Binomial(int64(op.a), op.b))
 			}
 		default:
@@ -101,26 +114,26 @@ func (op randomOp) Do(...Value) (retVal Value, err error) {
 	case Float64:
 		switch op.which {
 		case uniform:
-			backing := Uniform64(op.a, op.b, op.shape...)
+			backing := op.rng.Uniform64(op.a, op.b, op.shape...)
 			retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(op.shape...))
 		case gaussian:
-			backing := Gaussian64(op.a, op.b, op.shape...)
+			backing := op.rng.Gaussian64(op.a, op.b, op.shape...)
 			retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(op.shape...))
 		case binomial:
-			backing := Binomial64(op.a, op.b, op.shape...)
+			backing := op.rng.Binomial64(op.a, op.b, op.shape...)
 			retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(op.shape...))
 		}
 		return
 	case Float32:
 		switch op.which {
 		case uniform:
-			backing := Uniform32(op.a, op.b, op.shape...)
+			backing := op.rng.Uniform32(op.a, op.b, op.shape...)
 			retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(op.shape...))
 		case gaussian:
-			backing := Gaussian32(op.a, op.b, op.shape...)
+			backing := op.rng.Gaussian32(op.a, op.b, op.shape...)
 			retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(op.shape...))
 		case binomial:
-			backing := Binomial32(op.a, op.b, op.shape...)
+			backing := op.rng.Binomial32(op.a, op.b, op.shape...)
 			retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(op.shape...))
 		}
 		return
@@ -129,19 +142,3141 @@ func (op randomOp) Do(...Value) (retVal Value, err error) {
 	}
 }
 
-func (op randomOp) ReturnsPtr() bool     { return false }
-func (op randomOp) CallsExtern() bool    { return false }
-func (op randomOp) OverwritesInput() int { return -1 }
-func (op randomOp) WriteHash(h hash.Hash) {
+// mean returns the mean of the distribution, in the shape of the op
+func (op *randomOp) mean() (retVal Value, err error) {
+	var mean float64
+	switch op.which {
+	case uniform:
+		mean = (op.a + op.b) / 2
+	case gaussian:
+		mean = op.a
+	case binomial:
+		mean = float64(int64(op.a)) * op.b
+	}
+
+	if op.shape.IsScalar() {
+		switch op.dt {
+		case Float64:
+			return newF64(mean), nil
+		case Float32:
+			return newF32(float32(mean)), nil
+		}
+		return nil, errors.Errorf(nyiFail, "randomOp.mean()", op.dt)
+	}
+	backing := make([]float64, op.shape.TotalSize())
+	for i := range backing {
+		backing[i] = mean
+	}
+	return valueFromFloat64s(op.dt, op.shape, backing)
+}
+
+func (op *randomOp) SetTraining(training bool) { op.training = training }
+
+func (op *randomOp) ReturnsPtr() bool     { return false }
+func (op *randomOp) CallsExtern() bool    { return false }
+func (op *randomOp) OverwritesInput() int { return -1 }
+func (op *randomOp) WriteHash(h hash.Hash) {
 	fmt.Fprintf(h, "%d%v%f%f", op.which, op.shape, op.a, op.b)
 }
 
-func (op randomOp) Hashcode() uint32 {
+func (op *randomOp) Hashcode() uint32 {
 	h := fnv.New32a()
 	op.WriteHash(h)
 	return h.Sum32()
 }
 
-func (op randomOp) String() string {
+func (op *randomOp) String() string {
 	return fmt.Sprintf("%v(%v, %v) - %v", op.which, op.a, op.b, op.shape)
 }
+
+// conv2dOp is a 2D convolution over NCHW tensors. The input is a Tensor of (batch, channels, height, width),
+// and the filter is a Tensor of (outChannels, channels, kernelHeight, kernelWidth).
+//
+// The convolution is performed by means of im2col followed by a GEMM, using whatever BLAS is set by Use()
+type conv2dOp struct {
+	kernel, pad, stride, dilation []int
+}
+
+func makeConv2dOp(kernel, pad, stride, dilation []int) conv2dOp {
+	return conv2dOp{
+		kernel:   append([]int(nil), kernel...),
+		pad:      append([]int(nil), pad...),
+		stride:   append([]int(nil), stride...),
+		dilation: append([]int(nil), dilation...),
+	}
+}
+
+func (op conv2dOp) Arity() int { return 2 }
+
+// conv2dOp has this type:
+//		op :: Tensor-4 a → Tensor-4 a → Tensor-4 a
+func (op conv2dOp) Type() hm.Type {
+	t := newTensorType(4, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t, t)
+}
+
+func (op conv2dOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	f, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the filter. Got %T instead", inputs[1])
+	}
+
+	g, err := op.geometry(x, f)
+	if err != nil {
+		return nil, err
+	}
+	return tensor.Shape{g.n, g.k, g.oh, g.ow}, nil
+}
+
+func (op conv2dOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, f *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if f, err = contiguousDense(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	var g convGeometry
+	if g, err = op.geometry(x.Shape(), f.Shape()); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	y := tensor.New(tensor.Of(x.Dtype()), tensor.WithShape(g.n, g.k, g.oh, g.ow))
+	switch x.Dtype() {
+	case Float64:
+		g.fwdF64(x.Data().([]float64), f.Data().([]float64), y.Data().([]float64))
+	case Float32:
+		g.fwdF32(x.Data().([]float32), f.Data().([]float32), y.Data().([]float32))
+	default:
+		return nil, errors.Errorf(nyiFail, "conv2dOp.Do()", x.Dtype())
+	}
+	return y, nil
+}
+
+func (op conv2dOp) ReturnsPtr() bool     { return false }
+func (op conv2dOp) CallsExtern() bool    { return false }
+func (op conv2dOp) OverwritesInput() int { return -1 }
+
+func (op conv2dOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "conv2d%v%v%v%v", op.kernel, op.pad, op.stride, op.dilation)
+}
+
+func (op conv2dOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op conv2dOp) String() string {
+	return fmt.Sprintf("Conv2d k:%v p:%v s:%v d:%v", op.kernel, op.pad, op.stride, op.dilation)
+}
+
+func (op conv2dOp) DiffWRT(inputs int) []bool { return []bool{true, true} }
+
+func (op conv2dOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	x, f := inputs[0], inputs[1]
+	var dx, df *Node
+	if dx, err = applyOp(conv2dDiffOp{op, false, x.shape.Clone()}, x, f, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	if df, err = applyOp(conv2dDiffOp{op, true, f.shape.Clone()}, x, f, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	df.setGroup(gradClust)
+	return Nodes{dx, df}, nil
+}
+
+func (op conv2dOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	fdv := inputs[1].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var x, f, dy, dx, df *tensor.Dense
+	if x, err = contiguousDense(xdv.Value); err != nil {
+		return
+	}
+	if f, err = contiguousDense(fdv.Value); err != nil {
+		return
+	}
+	if dy, err = contiguousDense(ydv.d); err != nil {
+		return
+	}
+	if dx, err = contiguousDense(xdv.d); err != nil {
+		return
+	}
+	if df, err = contiguousDense(fdv.d); err != nil {
+		return
+	}
+
+	var g convGeometry
+	if g, err = op.geometry(x.Shape(), f.Shape()); err != nil {
+		return
+	}
+
+	switch x.Dtype() {
+	case Float64:
+		g.bwdF64(x.Data().([]float64), f.Data().([]float64), dy.Data().([]float64), dx.Data().([]float64), df.Data().([]float64))
+	case Float32:
+		g.bwdF32(x.Data().([]float32), f.Data().([]float32), dy.Data().([]float32), dx.Data().([]float32), df.Data().([]float32))
+	default:
+		return errors.Errorf(nyiFail, "conv2dOp.DoDiff()", x.Dtype())
+	}
+
+	// the derivatives may have been materialized from a view, in which case they need to be written back
+	if dx != xdv.d {
+		if _, err = Copy(xdv.d, dx); err != nil {
+			return
+		}
+	}
+	if df != fdv.d {
+		if _, err = Copy(fdv.d, df); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// geometry checks the input and filter shapes, and computes the shapes involved in the convolution
+func (op conv2dOp) geometry(x, f tensor.Shape) (g convGeometry, err error) {
+	if x.Dims() != 4 || f.Dims() != 4 {
+		return g, errors.Errorf("Conv2d expects 4D (NCHW) inputs and filters. Got %v and %v instead", x, f)
+	}
+	if x[1] != f[1] {
+		return g, errors.Errorf("Conv2d: input has %d channels, but the filter expects %d channels", x[1], f[1])
+	}
+	if f[2] != op.kernel[0] || f[3] != op.kernel[1] {
+		return g, errors.Errorf("Conv2d: kernel shape is %v but the filter has shape %v", op.kernel, f)
+	}
+
+	g = convGeometry{
+		n: x[0], c: x[1], h: x[2], w: x[3],
+		k: f[0], kh: f[2], kw: f[3],
+		ph: op.pad[0], pw: op.pad[1],
+		sh: op.stride[0], sw: op.stride[1],
+		dh: op.dilation[0], dw: op.dilation[1],
+	}
+	g.oh = (g.h+2*g.ph-(g.dh*(g.kh-1)+1))/g.sh + 1
+	g.ow = (g.w+2*g.pw-(g.dw*(g.kw-1)+1))/g.sw + 1
+	if g.oh <= 0 || g.ow <= 0 {
+		return g, errors.Errorf("Conv2d: input of shape %v is too small for the kernel %v with padding %v and dilation %v", x, op.kernel, op.pad, op.dilation)
+	}
+	return g, nil
+}
+
+// conv2dDiffOp is the op that computes the gradient of a conv2dOp with regards to either its input or its filter.
+// It takes the input, the filter, and the gradient of the output as its inputs.
+type conv2dDiffOp struct {
+	conv2dOp
+
+	wrtFilter bool
+	shape     tensor.Shape // shape of the thing the gradient is taken with regards to
+}
+
+func (op conv2dDiffOp) Arity() int { return 3 }
+
+// conv2dDiffOp has this type:
+//		op :: Tensor-4 a → Tensor-4 a → Tensor-4 a → Tensor-4 a
+func (op conv2dDiffOp) Type() hm.Type {
+	t := newTensorType(4, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t, t, t)
+}
+
+func (op conv2dDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	return op.shape.Clone(), nil
+}
+
+func (op conv2dDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, f, dy *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if f, err = contiguousDense(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if dy, err = contiguousDense(inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	var g convGeometry
+	if g, err = op.geometry(x.Shape(), f.Shape()); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	dt := x.Dtype()
+	var dx, df *tensor.Dense
+	if op.wrtFilter {
+		df = tensor.New(tensor.Of(dt), tensor.WithShape(f.Shape().Clone()...))
+		retVal = df
+	} else {
+		dx = tensor.New(tensor.Of(dt), tensor.WithShape(x.Shape().Clone()...))
+		retVal = dx
+	}
+
+	switch dt {
+	case Float64:
+		var dxs, dfs []float64
+		if dx != nil {
+			dxs = dx.Data().([]float64)
+		}
+		if df != nil {
+			dfs = df.Data().([]float64)
+		}
+		g.bwdF64(x.Data().([]float64), f.Data().([]float64), dy.Data().([]float64), dxs, dfs)
+	case Float32:
+		var dxs, dfs []float32
+		if dx != nil {
+			dxs = dx.Data().([]float32)
+		}
+		if df != nil {
+			dfs = df.Data().([]float32)
+		}
+		g.bwdF32(x.Data().([]float32), f.Data().([]float32), dy.Data().([]float32), dxs, dfs)
+	default:
+		return nil, errors.Errorf(nyiFail, "conv2dDiffOp.Do()", dt)
+	}
+	return
+}
+
+func (op conv2dDiffOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "conv2dDiff%v%v%v%v%t%v", op.kernel, op.pad, op.stride, op.dilation, op.wrtFilter, op.shape)
+}
+
+func (op conv2dDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op conv2dDiffOp) String() string {
+	if op.wrtFilter {
+		return fmt.Sprintf("∂%v/∂filter", op.conv2dOp)
+	}
+	return fmt.Sprintf("∂%v/∂x", op.conv2dOp)
+}
+
+// conv2dDiffOp is not differentiable. Overriding the embedded methods prevents it from being an SDOp/ADOp
+func (op conv2dDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false, false} }
+
+func (op conv2dDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op conv2dDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+// convGeometry holds the sizes involved in a 2D convolution
+type convGeometry struct {
+	n, c, h, w int // input: batch, channels, height, width
+	k, kh, kw  int // filter: output channels, kernel height, kernel width
+	oh, ow     int // output height and width
+	ph, pw     int // padding
+	sh, sw     int // stride
+	dh, dw     int // dilation
+}
+
+// colRows is the number of rows of the im2col matrix. It is also the number of columns of the filter when viewed as a matrix.
+func (g convGeometry) colRows() int { return g.c * g.kh * g.kw }
+
+// colCols is the number of columns of the im2col matrix - one per output pixel
+func (g convGeometry) colCols() int { return g.oh * g.ow }
+
+func (g convGeometry) fwdF64(x, f, y []float64) {
+	rows, cols := g.colRows(), g.colCols()
+	col := make([]float64, rows*cols)
+	imgSize := g.c * g.h * g.w
+	outSize := g.k * cols
+	for b := 0; b < g.n; b++ {
+		g.im2colF64(x[b*imgSize:(b+1)*imgSize], col)
+		whichblas.Dgemm(blas.NoTrans, blas.NoTrans, g.k, cols, rows, 1, f, rows, col, cols, 0, y[b*outSize:(b+1)*outSize], cols)
+	}
+}
+
+// bwdF64 accumulates the gradients of x and f into dx and df respectively. Either dx or df may be nil, in which case they will be skipped.
+func (g convGeometry) bwdF64(x, f, dy, dx, df []float64) {
+	rows, cols := g.colRows(), g.colCols()
+	col := make([]float64, rows*cols)
+	imgSize := g.c * g.h * g.w
+	outSize := g.k * cols
+	for b := 0; b < g.n; b++ {
+		dyb := dy[b*outSize : (b+1)*outSize]
+		if df != nil {
+			g.im2colF64(x[b*imgSize:(b+1)*imgSize], col)
+			whichblas.Dgemm(blas.NoTrans, blas.Trans, g.k, rows, cols, 1, dyb, cols, col, cols, 1, df, rows)
+		}
+		if dx != nil {
+			whichblas.Dgemm(blas.Trans, blas.NoTrans, rows, cols, g.k, 1, f, rows, dyb, cols, 0, col, cols)
+			g.col2imF64(col, dx[b*imgSize:(b+1)*imgSize])
+		}
+	}
+}
+
+func (g convGeometry) im2colF64(img, col []float64) {
+	cols := g.colCols()
+	for c := 0; c < g.c; c++ {
+		for i := 0; i < g.kh; i++ {
+			for j := 0; j < g.kw; j++ {
+				row := col[((c*g.kh+i)*g.kw+j)*cols:]
+				for oy := 0; oy < g.oh; oy++ {
+					iy := oy*g.sh - g.ph + i*g.dh
+					for ox := 0; ox < g.ow; ox++ {
+						ix := ox*g.sw - g.pw + j*g.dw
+						if iy < 0 || iy >= g.h || ix < 0 || ix >= g.w {
+							row[oy*g.ow+ox] = 0
+							continue
+						}
+						row[oy*g.ow+ox] = img[(c*g.h+iy)*g.w+ix]
+					}
+				}
+			}
+		}
+	}
+}
+
+// col2imF64 is the adjoint of im2colF64. The results are accumulated into img.
+func (g convGeometry) col2imF64(col, img []float64) {
+	cols := g.colCols()
+	for c := 0; c < g.c; c++ {
+		for i := 0; i < g.kh; i++ {
+			for j := 0; j < g.kw; j++ {
+				row := col[((c*g.kh+i)*g.kw+j)*cols:]
+				for oy := 0; oy < g.oh; oy++ {
+					iy := oy*g.sh - g.ph + i*g.dh
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for ox := 0; ox < g.ow; ox++ {
+						ix := ox*g.sw - g.pw + j*g.dw
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						img[(c*g.h+iy)*g.w+ix] += row[oy*g.ow+ox]
+					}
+				}
+			}
+		}
+	}
+}
+
+func (g convGeometry) fwdF32(x, f, y []float32) {
+	rows, cols := g.colRows(), g.colCols()
+	col := make([]float32, rows*cols)
+	imgSize := g.c * g.h * g.w
+	outSize := g.k * cols
+	for b := 0; b < g.n; b++ {
+		g.im2colF32(x[b*imgSize:(b+1)*imgSize], col)
+		whichblas.Sgemm(blas.NoTrans, blas.NoTrans, g.k, cols, rows, 1, f, rows, col, cols, 0, y[b*outSize:(b+1)*outSize], cols)
+	}
+}
+
+// bwdF32 accumulates the gradients of x and f into dx and df respectively. Either dx or df may be nil, in which case they will be skipped.
+func (g convGeometry) bwdF32(x, f, dy, dx, df []float32) {
+	rows, cols := g.colRows(), g.colCols()
+	col := make([]float32, rows*cols)
+	imgSize := g.c * g.h * g.w
+	outSize := g.k * cols
+	for b := 0; b < g.n; b++ {
+		dyb := dy[b*outSize : (b+1)*outSize]
+		if df != nil {
+			g.im2colF32(x[b*imgSize:(b+1)*imgSize], col)
+			whichblas.Sgemm(blas.NoTrans, blas.Trans, g.k, rows, cols, 1, dyb, cols, col, cols, 1, df, rows)
+		}
+		if dx != nil {
+			whichblas.Sgemm(blas.Trans, blas.NoTrans, rows, cols, g.k, 1, f, rows, dyb, cols, 0, col, cols)
+			g.col2imF32(col, dx[b*imgSize:(b+1)*imgSize])
+		}
+	}
+}
+
+func (g convGeometry) im2colF32(img, col []float32) {
+	cols := g.colCols()
+	for c := 0; c < g.c; c++ {
+		for i := 0; i < g.kh; i++ {
+			for j := 0; j < g.kw; j++ {
+				row := col[((c*g.kh+i)*g.kw+j)*cols:]
+				for oy := 0; oy < g.oh; oy++ {
+					iy := oy*g.sh - g.ph + i*g.dh
+					for ox := 0; ox < g.ow; ox++ {
+						ix := ox*g.sw - g.pw + j*g.dw
+						if iy < 0 || iy >= g.h || ix < 0 || ix >= g.w {
+							row[oy*g.ow+ox] = 0
+							continue
+						}
+						row[oy*g.ow+ox] = img[(c*g.h+iy)*g.w+ix]
+					}
+				}
+			}
+		}
+	}
+}
+
+// col2imF32 is the adjoint of im2colF32. The results are accumulated into img.
+func (g convGeometry) col2imF32(col, img []float32) {
+	cols := g.colCols()
+	for c := 0; c < g.c; c++ {
+		for i := 0; i < g.kh; i++ {
+			for j := 0; j < g.kw; j++ {
+				row := col[((c*g.kh+i)*g.kw+j)*cols:]
+				for oy := 0; oy < g.oh; oy++ {
+					iy := oy*g.sh - g.ph + i*g.dh
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for ox := 0; ox < g.ow; ox++ {
+						ix := ox*g.sw - g.pw + j*g.dw
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						img[(c*g.h+iy)*g.w+ix] += row[oy*g.ow+ox]
+					}
+				}
+			}
+		}
+	}
+}
+
+// poolGeometry checks the input shape and computes the shapes involved in a 2D pooling operation.
+// Pooling is done per channel, so the number of output channels is the same as the number of input channels.
+func poolGeometry(x tensor.Shape, kernel, pad, stride []int) (g convGeometry, err error) {
+	if x.Dims() != 4 {
+		return g, errors.Errorf("Pooling expects a 4D (NCHW) input. Got %v instead", x)
+	}
+
+	g = convGeometry{
+		n: x[0], c: x[1], h: x[2], w: x[3],
+		k: x[1], kh: kernel[0], kw: kernel[1],
+		ph: pad[0], pw: pad[1],
+		sh: stride[0], sw: stride[1],
+		dh: 1, dw: 1,
+	}
+	g.oh = (g.h+2*g.ph-g.kh)/g.sh + 1
+	g.ow = (g.w+2*g.pw-g.kw)/g.sw + 1
+	if g.oh <= 0 || g.ow <= 0 {
+		return g, errors.Errorf("Pooling: input of shape %v is too small for the kernel %v with padding %v", x, kernel, pad)
+	}
+	return g, nil
+}
+
+// maxPool2dOp is a 2D max pooling over a NCHW tensor.
+//
+// The indices of the maximum values found in the last call to Do() are kept, so that the gradient
+// can be scattered back without having to find the maxima again.
+type maxPool2dOp struct {
+	kernel, pad, stride []int
+
+	argmax []int // flat indices into the input of the max values of the last forward pass
+}
+
+func newMaxPool2dOp(kernel, pad, stride []int) *maxPool2dOp {
+	return &maxPool2dOp{
+		kernel: append([]int(nil), kernel...),
+		pad:    append([]int(nil), pad...),
+		stride: append([]int(nil), stride...),
+	}
+}
+
+func (op *maxPool2dOp) Arity() int { return 1 }
+
+// maxPool2dOp has this type:
+//		op :: Tensor-4 a → Tensor-4 a
+func (op *maxPool2dOp) Type() hm.Type {
+	t := newTensorType(4, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t)
+}
+
+func (op *maxPool2dOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	g, err := poolGeometry(x, op.kernel, op.pad, op.stride)
+	if err != nil {
+		return nil, err
+	}
+	return tensor.Shape{g.n, g.c, g.oh, g.ow}, nil
+}
+
+func (op *maxPool2dOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	var g convGeometry
+	if g, err = poolGeometry(x.Shape(), op.kernel, op.pad, op.stride); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	y := tensor.New(tensor.Of(x.Dtype()), tensor.WithShape(g.n, g.c, g.oh, g.ow))
+	if len(op.argmax) != y.Shape().TotalSize() {
+		op.argmax = make([]int, y.Shape().TotalSize())
+	}
+	switch x.Dtype() {
+	case Float64:
+		g.maxPoolF64(x.Data().([]float64), y.Data().([]float64), op.argmax)
+	case Float32:
+		g.maxPoolF32(x.Data().([]float32), y.Data().([]float32), op.argmax)
+	default:
+		return nil, errors.Errorf(nyiFail, "maxPool2dOp.Do()", x.Dtype())
+	}
+	return y, nil
+}
+
+func (op *maxPool2dOp) ReturnsPtr() bool     { return false }
+func (op *maxPool2dOp) CallsExtern() bool    { return false }
+func (op *maxPool2dOp) OverwritesInput() int { return -1 }
+
+func (op *maxPool2dOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "MaxPool2D%v%v%v", op.kernel, op.pad, op.stride)
+}
+
+func (op *maxPool2dOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op *maxPool2dOp) String() string {
+	return fmt.Sprintf("MaxPool2D k:%v p:%v s:%v", op.kernel, op.pad, op.stride)
+}
+
+func (op *maxPool2dOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op *maxPool2dOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	// the output is passed in to ensure that the forward pass has happened by the time the gradient is computed
+	var dx *Node
+	if dx, err = applyOp(maxPool2dDiffOp{op}, inputs[0], output, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op *maxPool2dOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = (maxPool2dDiffOp{op}).Do(xdv.Value, ydv.Value, ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	_, err = add.UnsafeDo(xdv.d, d)
+	return
+}
+
+// maxPool2dDiffOp is the gradient of a max pooling. It takes the input, the output and the gradient of the output as its inputs.
+type maxPool2dDiffOp struct {
+	*maxPool2dOp
+}
+
+func (op maxPool2dDiffOp) Arity() int { return 3 }
+
+// maxPool2dDiffOp has this type:
+//		op :: Tensor-4 a → Tensor-4 a → Tensor-4 a → Tensor-4 a
+func (op maxPool2dDiffOp) Type() hm.Type {
+	t := newTensorType(4, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t, t, t)
+}
+
+func (op maxPool2dDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	return x.Clone(), nil
+}
+
+func (op maxPool2dDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, dy *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if dy, err = contiguousDense(inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	// the indices are kept from the forward pass. If for some reason there are none, recompute them
+	if len(op.argmax) != dy.Shape().TotalSize() {
+		if _, err = op.maxPool2dOp.Do(x); err != nil {
+			return nil, err
+		}
+	}
+
+	dx := tensor.New(tensor.Of(x.Dtype()), tensor.WithShape(x.Shape().Clone()...))
+	switch x.Dtype() {
+	case Float64:
+		dxs := dx.Data().([]float64)
+		for i, d := range dy.Data().([]float64) {
+			dxs[op.argmax[i]] += d
+		}
+	case Float32:
+		dxs := dx.Data().([]float32)
+		for i, d := range dy.Data().([]float32) {
+			dxs[op.argmax[i]] += d
+		}
+	default:
+		return nil, errors.Errorf(nyiFail, "maxPool2dDiffOp.Do()", x.Dtype())
+	}
+	return dx, nil
+}
+
+func (op maxPool2dDiffOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "MaxPool2DDiff%v%v%v", op.kernel, op.pad, op.stride)
+}
+
+func (op maxPool2dDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op maxPool2dDiffOp) String() string { return fmt.Sprintf("∂%v", op.maxPool2dOp) }
+
+// maxPool2dDiffOp is not differentiable. Overriding the embedded methods prevents it from being an SDOp/ADOp
+func (op maxPool2dDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false, false} }
+
+func (op maxPool2dDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op maxPool2dDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+// avgPool2dOp is a 2D average pooling over a NCHW tensor. Padded values count as zeroes towards the average.
+type avgPool2dOp struct {
+	kernel, pad, stride []int
+}
+
+func makeAvgPool2dOp(kernel, pad, stride []int) avgPool2dOp {
+	return avgPool2dOp{
+		kernel: append([]int(nil), kernel...),
+		pad:    append([]int(nil), pad...),
+		stride: append([]int(nil), stride...),
+	}
+}
+
+func (op avgPool2dOp) Arity() int { return 1 }
+
+// avgPool2dOp has this type:
+//		op :: Tensor-4 a → Tensor-4 a
+func (op avgPool2dOp) Type() hm.Type {
+	t := newTensorType(4, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t)
+}
+
+func (op avgPool2dOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	g, err := poolGeometry(x, op.kernel, op.pad, op.stride)
+	if err != nil {
+		return nil, err
+	}
+	return tensor.Shape{g.n, g.c, g.oh, g.ow}, nil
+}
+
+func (op avgPool2dOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	var g convGeometry
+	if g, err = poolGeometry(x.Shape(), op.kernel, op.pad, op.stride); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	y := tensor.New(tensor.Of(x.Dtype()), tensor.WithShape(g.n, g.c, g.oh, g.ow))
+	switch x.Dtype() {
+	case Float64:
+		g.avgPoolF64(x.Data().([]float64), y.Data().([]float64))
+	case Float32:
+		g.avgPoolF32(x.Data().([]float32), y.Data().([]float32))
+	default:
+		return nil, errors.Errorf(nyiFail, "avgPool2dOp.Do()", x.Dtype())
+	}
+	return y, nil
+}
+
+func (op avgPool2dOp) ReturnsPtr() bool     { return false }
+func (op avgPool2dOp) CallsExtern() bool    { return false }
+func (op avgPool2dOp) OverwritesInput() int { return -1 }
+
+func (op avgPool2dOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "AvgPool2D%v%v%v", op.kernel, op.pad, op.stride)
+}
+
+func (op avgPool2dOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op avgPool2dOp) String() string {
+	return fmt.Sprintf("AvgPool2D k:%v p:%v s:%v", op.kernel, op.pad, op.stride)
+}
+
+func (op avgPool2dOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op avgPool2dOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(avgPool2dDiffOp{op}, inputs[0], grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op avgPool2dOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = (avgPool2dDiffOp{op}).Do(xdv.Value, ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	_, err = add.UnsafeDo(xdv.d, d)
+	return
+}
+
+// avgPool2dDiffOp is the gradient of an average pooling. It takes the input and the gradient of the output as its inputs.
+type avgPool2dDiffOp struct {
+	avgPool2dOp
+}
+
+func (op avgPool2dDiffOp) Arity() int { return 2 }
+
+// avgPool2dDiffOp has this type:
+//		op :: Tensor-4 a → Tensor-4 a → Tensor-4 a
+func (op avgPool2dDiffOp) Type() hm.Type {
+	t := newTensorType(4, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t, t)
+}
+
+func (op avgPool2dDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	return x.Clone(), nil
+}
+
+func (op avgPool2dDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dy *tensor.Dense
+	if dy, err = contiguousDense(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	xShape := inputs[0].Shape()
+	var g convGeometry
+	if g, err = poolGeometry(xShape, op.kernel, op.pad, op.stride); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	dx := tensor.New(tensor.Of(dy.Dtype()), tensor.WithShape(xShape.Clone()...))
+	switch dy.Dtype() {
+	case Float64:
+		g.avgPoolDiffF64(dy.Data().([]float64), dx.Data().([]float64))
+	case Float32:
+		g.avgPoolDiffF32(dy.Data().([]float32), dx.Data().([]float32))
+	default:
+		return nil, errors.Errorf(nyiFail, "avgPool2dDiffOp.Do()", dy.Dtype())
+	}
+	return dx, nil
+}
+
+func (op avgPool2dDiffOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "AvgPool2DDiff%v%v%v", op.kernel, op.pad, op.stride)
+}
+
+func (op avgPool2dDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op avgPool2dDiffOp) String() string { return fmt.Sprintf("∂%v", op.avgPool2dOp) }
+
+// avgPool2dDiffOp is not differentiable. Overriding the embedded methods prevents it from being an SDOp/ADOp
+func (op avgPool2dDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false} }
+
+func (op avgPool2dDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op avgPool2dDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+func (g convGeometry) maxPoolF64(x, y []float64, argmax []int) {
+	var i int
+	for nc := 0; nc < g.n*g.c; nc++ {
+		plane := nc * g.h * g.w
+		for oy := 0; oy < g.oh; oy++ {
+			for ox := 0; ox < g.ow; ox++ {
+				max, maxAt := math.Inf(-1), -1
+				for ky := 0; ky < g.kh; ky++ {
+					iy := oy*g.sh - g.ph + ky
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for kx := 0; kx < g.kw; kx++ {
+						ix := ox*g.sw - g.pw + kx
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						at := plane + iy*g.w + ix
+						if maxAt < 0 || x[at] > max {
+							max, maxAt = x[at], at
+						}
+					}
+				}
+				y[i] = max
+				argmax[i] = maxAt
+				i++
+			}
+		}
+	}
+}
+
+func (g convGeometry) maxPoolF32(x, y []float32, argmax []int) {
+	var i int
+	for nc := 0; nc < g.n*g.c; nc++ {
+		plane := nc * g.h * g.w
+		for oy := 0; oy < g.oh; oy++ {
+			for ox := 0; ox < g.ow; ox++ {
+				max, maxAt := math32.Inf(-1), -1
+				for ky := 0; ky < g.kh; ky++ {
+					iy := oy*g.sh - g.ph + ky
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for kx := 0; kx < g.kw; kx++ {
+						ix := ox*g.sw - g.pw + kx
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						at := plane + iy*g.w + ix
+						if maxAt < 0 || x[at] > max {
+							max, maxAt = x[at], at
+						}
+					}
+				}
+				y[i] = max
+				argmax[i] = maxAt
+				i++
+			}
+		}
+	}
+}
+
+func (g convGeometry) avgPoolF64(x, y []float64) {
+	size := float64(g.kh * g.kw)
+	var i int
+	for nc := 0; nc < g.n*g.c; nc++ {
+		plane := nc * g.h * g.w
+		for oy := 0; oy < g.oh; oy++ {
+			for ox := 0; ox < g.ow; ox++ {
+				var sum float64
+				for ky := 0; ky < g.kh; ky++ {
+					iy := oy*g.sh - g.ph + ky
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for kx := 0; kx < g.kw; kx++ {
+						ix := ox*g.sw - g.pw + kx
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						sum += x[plane+iy*g.w+ix]
+					}
+				}
+				y[i] = sum / size
+				i++
+			}
+		}
+	}
+}
+
+func (g convGeometry) avgPoolDiffF64(dy, dx []float64) {
+	size := float64(g.kh * g.kw)
+	var i int
+	for nc := 0; nc < g.n*g.c; nc++ {
+		plane := nc * g.h * g.w
+		for oy := 0; oy < g.oh; oy++ {
+			for ox := 0; ox < g.ow; ox++ {
+				d := dy[i] / size
+				for ky := 0; ky < g.kh; ky++ {
+					iy := oy*g.sh - g.ph + ky
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for kx := 0; kx < g.kw; kx++ {
+						ix := ox*g.sw - g.pw + kx
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						dx[plane+iy*g.w+ix] += d
+					}
+				}
+				i++
+			}
+		}
+	}
+}
+
+func (g convGeometry) avgPoolF32(x, y []float32) {
+	size := float32(g.kh * g.kw)
+	var i int
+	for nc := 0; nc < g.n*g.c; nc++ {
+		plane := nc * g.h * g.w
+		for oy := 0; oy < g.oh; oy++ {
+			for ox := 0; ox < g.ow; ox++ {
+				var sum float32
+				for ky := 0; ky < g.kh; ky++ {
+					iy := oy*g.sh - g.ph + ky
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for kx := 0; kx < g.kw; kx++ {
+						ix := ox*g.sw - g.pw + kx
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						sum += x[plane+iy*g.w+ix]
+					}
+				}
+				y[i] = sum / size
+				i++
+			}
+		}
+	}
+}
+
+func (g convGeometry) avgPoolDiffF32(dy, dx []float32) {
+	size := float32(g.kh * g.kw)
+	var i int
+	for nc := 0; nc < g.n*g.c; nc++ {
+		plane := nc * g.h * g.w
+		for oy := 0; oy < g.oh; oy++ {
+			for ox := 0; ox < g.ow; ox++ {
+				d := dy[i] / size
+				for ky := 0; ky < g.kh; ky++ {
+					iy := oy*g.sh - g.ph + ky
+					if iy < 0 || iy >= g.h {
+						continue
+					}
+					for kx := 0; kx < g.kw; kx++ {
+						ix := ox*g.sw - g.pw + kx
+						if ix < 0 || ix >= g.w {
+							continue
+						}
+						dx[plane+iy*g.w+ix] += d
+					}
+				}
+				i++
+			}
+		}
+	}
+}
+
+// batchNormOp normalizes its input per channel, using the statistics of the batch when training,
+// and the running statistics when doing inference. The channels are on axis 1 (i.e. NC or NCHW tensors).
+//
+// The running mean and variance are the 4th and 5th inputs. They are owned by the caller, and are updated in place during every training pass:
+//		running = momentum * running + (1 - momentum) * batch
+//
+// The per channel mean and inverse standard deviation used by the last forwards pass are cached in the op for the backwards pass.
+type batchNormOp struct {
+	momentum, eps float64
+	dims          int
+	training      bool
+
+	// cached from the last forwards pass
+	mean     []float64
+	invStd   []float64
+	wasTrain bool
+}
+
+func newBatchNormOp(momentum, eps float64, dims int) *batchNormOp {
+	return &batchNormOp{
+		momentum: momentum,
+		eps:      eps,
+		dims:     dims,
+		training: true,
+	}
+}
+
+func (op *batchNormOp) Arity() int { return 5 }
+
+// batchNormOp has this type:
+//		op :: Tensor-d a → Tensor-1 a → Tensor-1 a → Tensor-1 a → Tensor-1 a → Tensor-d a
+func (op *batchNormOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	t := newTensorType(op.dims, a)
+	v := newTensorType(1, a)
+	return hm.NewFnType(t, v, v, v, v, t)
+}
+
+func (op *batchNormOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	if x.Dims() < 2 {
+		return nil, errors.Errorf("BatchNorm expects the input to have at least 2 dimensions. Got %v instead", x)
+	}
+	for i, name := range []string{"scale", "bias", "running mean", "running variance"} {
+		s, ok := inputs[i+1].(tensor.Shape)
+		if !ok {
+			return nil, errors.Errorf("Expected a shape for the %s. Got %T instead", name, inputs[i+1])
+		}
+		if s.TotalSize() != x[1] {
+			return nil, errors.Errorf("BatchNorm: input has %d channels, but the %s has shape %v", x[1], name, s)
+		}
+	}
+	return x.Clone(), nil
+}
+
+func (op *batchNormOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, scale, bias *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if scale, err = contiguousDense(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if bias, err = contiguousDense(inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	// the running statistics are updated in place, so they can't be copies
+	var runMean, runVar *tensor.Dense
+	if runMean, err = runningStat(inputs[3]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if runVar, err = runningStat(inputs[4]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	shape := x.Shape()
+	n, c, spatial := shape[0], shape[1], shape.TotalSize()/(shape[0]*shape[1])
+	op.wasTrain = op.training
+	op.mean = make([]float64, c)
+	op.invStd = make([]float64, c)
+
+	y := tensor.New(tensor.Of(x.Dtype()), tensor.WithShape(shape.Clone()...))
+	switch x.Dtype() {
+	case Float64:
+		op.fwdF64(n, c, spatial, float64sBacking(x), float64sBacking(scale), float64sBacking(bias), float64sBacking(runMean), float64sBacking(runVar), float64sBacking(y))
+	case Float32:
+		op.fwdF32(n, c, spatial, float32sBacking(x), float32sBacking(scale), float32sBacking(bias), float32sBacking(runMean), float32sBacking(runVar), float32sBacking(y))
+	default:
+		return nil, errors.Errorf(nyiFail, "batchNormOp.Do()", x.Dtype())
+	}
+	return y, nil
+}
+
+// runningStat returns the running mean or variance passed in as v, which is to be updated in place.
+func runningStat(v Value) (*tensor.Dense, error) {
+	t, ok := v.(*tensor.Dense)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "BatchNorm running statistics", v)
+	}
+	if t.IsMaterializable() {
+		return nil, errors.Errorf("BatchNorm expects the running statistics not to be views, as they are updated in place")
+	}
+	return t, nil
+}
+
+// stats sets the mean and inverse standard deviation of the channel ch, given the statistics of the batch and the running statistics.
+// When training, the batch statistics are used, and the running statistics updated with them are returned. Otherwise the batch
+// statistics are ignored, and the running statistics are used and returned as they are.
+func (op *batchNormOp) stats(ch, m int, mean, variance, runMean, runVar float64) (float64, float64) {
+	if op.training {
+		unbiased := variance
+		if m > 1 {
+			unbiased = variance * float64(m) / float64(m-1)
+		}
+		runMean = op.momentum*runMean + (1-op.momentum)*mean
+		runVar = op.momentum*runVar + (1-op.momentum)*unbiased
+	} else {
+		mean, variance = runMean, runVar
+	}
+	op.mean[ch] = mean
+	op.invStd[ch] = 1 / math.Sqrt(variance+op.eps)
+	return runMean, runVar
+}
+
+func (op *batchNormOp) fwdF64(n, c, spatial int, x, scale, bias, runMean, runVar, y []float64) {
+	m := n * spatial
+	for ch := 0; ch < c; ch++ {
+		var mean, variance float64
+		if op.training {
+			for b := 0; b < n; b++ {
+				for _, v := range x[(b*c+ch)*spatial : (b*c+ch+1)*spatial] {
+					mean += v
+				}
+			}
+			mean /= float64(m)
+			for b := 0; b < n; b++ {
+				for _, v := range x[(b*c+ch)*spatial : (b*c+ch+1)*spatial] {
+					variance += (v - mean) * (v - mean)
+				}
+			}
+			variance /= float64(m)
+		}
+		runMean[ch], runVar[ch] = op.stats(ch, m, mean, variance, runMean[ch], runVar[ch])
+
+		mean, k := op.mean[ch], scale[ch]*op.invStd[ch]
+		for b := 0; b < n; b++ {
+			start := (b*c + ch) * spatial
+			for i := start; i < start+spatial; i++ {
+				y[i] = (x[i]-mean)*k + bias[ch]
+			}
+		}
+	}
+}
+
+func (op *batchNormOp) fwdF32(n, c, spatial int, x, scale, bias, runMean, runVar, y []float32) {
+	m := n * spatial
+	for ch := 0; ch < c; ch++ {
+		var mean, variance float64
+		if op.training {
+			for b := 0; b < n; b++ {
+				for _, v := range x[(b*c+ch)*spatial : (b*c+ch+1)*spatial] {
+					mean += float64(v)
+				}
+			}
+			mean /= float64(m)
+			for b := 0; b < n; b++ {
+				for _, v := range x[(b*c+ch)*spatial : (b*c+ch+1)*spatial] {
+					variance += (float64(v) - mean) * (float64(v) - mean)
+				}
+			}
+			variance /= float64(m)
+		}
+		rm, rv := op.stats(ch, m, mean, variance, float64(runMean[ch]), float64(runVar[ch]))
+		runMean[ch], runVar[ch] = float32(rm), float32(rv)
+
+		mean32, k := float32(op.mean[ch]), scale[ch]*float32(op.invStd[ch])
+		for b := 0; b < n; b++ {
+			start := (b*c + ch) * spatial
+			for i := start; i < start+spatial; i++ {
+				y[i] = (x[i]-mean32)*k + bias[ch]
+			}
+		}
+	}
+}
+
+// diff accumulates the gradients with regards to the input, the scale and the bias into dx, dscale and dbias, given
+// the input x and the gradient of the output dy. Any of dx, dscale and dbias may be nil, in which case they will be skipped.
+func (op *batchNormOp) diff(x, scale, dy, dx, dscale, dbias *tensor.Dense) error {
+	shape := x.Shape()
+	n, c, spatial := shape[0], shape[1], shape.TotalSize()/(shape[0]*shape[1])
+	if len(op.invStd) != c {
+		return errors.Errorf("%v: no forwards pass has been done for an input of shape %v", op, shape)
+	}
+
+	switch x.Dtype() {
+	case Float64:
+		var dxs, dscales, dbiases []float64
+		if dx != nil {
+			dxs = float64sBacking(dx)
+		}
+		if dscale != nil {
+			dscales = float64sBacking(dscale)
+		}
+		if dbias != nil {
+			dbiases = float64sBacking(dbias)
+		}
+		op.bwdF64(n, c, spatial, float64sBacking(x), float64sBacking(scale), float64sBacking(dy), dxs, dscales, dbiases)
+	case Float32:
+		var dxs, dscales, dbiases []float32
+		if dx != nil {
+			dxs = float32sBacking(dx)
+		}
+		if dscale != nil {
+			dscales = float32sBacking(dscale)
+		}
+		if dbias != nil {
+			dbiases = float32sBacking(dbias)
+		}
+		op.bwdF32(n, c, spatial, float32sBacking(x), float32sBacking(scale), float32sBacking(dy), dxs, dscales, dbiases)
+	default:
+		return errors.Errorf(nyiFail, "batchNormOp.diff()", x.Dtype())
+	}
+	return nil
+}
+
+func (op *batchNormOp) bwdF64(n, c, spatial int, x, scale, dy, dx, dscale, dbias []float64) {
+	m := float64(n * spatial)
+	for ch := 0; ch < c; ch++ {
+		mean, invStd := op.mean[ch], op.invStd[ch]
+		var sumDy, sumDyXhat float64
+		for b := 0; b < n; b++ {
+			start := (b*c + ch) * spatial
+			for i := start; i < start+spatial; i++ {
+				sumDy += dy[i]
+				sumDyXhat += dy[i] * (x[i] - mean) * invStd
+			}
+		}
+		if dscale != nil {
+			dscale[ch] += sumDyXhat
+		}
+		if dbias != nil {
+			dbias[ch] += sumDy
+		}
+		if dx == nil {
+			continue
+		}
+
+		k := scale[ch] * invStd
+		for b := 0; b < n; b++ {
+			start := (b*c + ch) * spatial
+			for i := start; i < start+spatial; i++ {
+				if op.wasTrain {
+					// the batch statistics are functions of x as well
+					dx[i] += k * (dy[i] - (sumDy+(x[i]-mean)*invStd*sumDyXhat)/m)
+				} else {
+					dx[i] += k * dy[i]
+				}
+			}
+		}
+	}
+}
+
+func (op *batchNormOp) bwdF32(n, c, spatial int, x, scale, dy, dx, dscale, dbias []float32) {
+	m := float64(n * spatial)
+	for ch := 0; ch < c; ch++ {
+		mean, invStd := op.mean[ch], op.invStd[ch]
+		var sumDy, sumDyXhat float64
+		for b := 0; b < n; b++ {
+			start := (b*c + ch) * spatial
+			for i := start; i < start+spatial; i++ {
+				sumDy += float64(dy[i])
+				sumDyXhat += float64(dy[i]) * (float64(x[i]) - mean) * invStd
+			}
+		}
+		if dscale != nil {
+			dscale[ch] += float32(sumDyXhat)
+		}
+		if dbias != nil {
+			dbias[ch] += float32(sumDy)
+		}
+		if dx == nil {
+			continue
+		}
+
+		k := float32(float64(scale[ch]) * invStd)
+		mean32, invStd32 := float32(mean), float32(invStd)
+		meanDy, meanDyXhat := float32(sumDy/m), float32(sumDyXhat/m)
+		for b := 0; b < n; b++ {
+			start := (b*c + ch) * spatial
+			for i := start; i < start+spatial; i++ {
+				if op.wasTrain {
+					// the batch statistics are functions of x as well
+					dx[i] += k * (dy[i] - meanDy - (x[i]-mean32)*invStd32*meanDyXhat)
+				} else {
+					dx[i] += k * dy[i]
+				}
+			}
+		}
+	}
+}
+
+func (op *batchNormOp) SetTraining(training bool) { op.training = training }
+
+func (op *batchNormOp) ReturnsPtr() bool     { return false }
+func (op *batchNormOp) CallsExtern() bool    { return false }
+func (op *batchNormOp) OverwritesInput() int { return -1 }
+
+func (op *batchNormOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "BatchNorm%v%v%d", op.momentum, op.eps, op.dims)
+}
+
+func (op *batchNormOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op *batchNormOp) String() string {
+	return fmt.Sprintf("BatchNorm(momentum: %v, eps: %v)", op.momentum, op.eps)
+}
+
+func (op *batchNormOp) DiffWRT(inputs int) []bool { return []bool{true, true, true, false, false} }
+
+func (op *batchNormOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	// the output is passed in to ensure that the forwards pass has happened by the time the gradient is computed.
+	// The gradients wrt all three inputs are computed at once, and then split.
+	var all *Node
+	if all, err = applyOp(batchNormDiffOp{op}, inputs[0], inputs[1], output, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	all.setGroup(gradClust)
+
+	start := 0
+	retVal = make(Nodes, 5) // the running statistics have no gradients
+	for i, in := range inputs[:3] {
+		size := in.Shape().TotalSize()
+		var part *Node
+		if part, err = Slice(all, S(start, start+size)); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+		if retVal[i], err = Reshape(part, in.Shape().Clone()...); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+		part.setGroup(gradClust)
+		retVal[i].setGroup(gradClust)
+		start += size
+	}
+	return
+}
+
+func (op *batchNormOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, scale, dy *tensor.Dense
+	if x, err = contiguousDense(inputs[0].boundTo.(*dualValue).Value); err != nil {
+		return
+	}
+	if scale, err = contiguousDense(inputs[1].boundTo.(*dualValue).Value); err != nil {
+		return
+	}
+	if dy, err = contiguousDense(output.boundTo.(*dualValue).d); err != nil {
+		return
+	}
+
+	var ds [3]*tensor.Dense
+	for i := range ds {
+		if ds[i], err = contiguousDense(inputs[i].boundTo.(*dualValue).d); err != nil {
+			return
+		}
+	}
+	if err = op.diff(x, scale, dy, ds[0], ds[1], ds[2]); err != nil {
+		return
+	}
+
+	// the derivatives may have been materialized from a view, in which case they need to be written back
+	for i, d := range ds {
+		dv := inputs[i].boundTo.(*dualValue)
+		if d != dv.d {
+			if _, err = Copy(dv.d, d); err != nil {
+				return
+			}
+		}
+	}
+	return nil
+}
+
+// batchNormDiffOp computes the gradients of a batchNormOp with regards to its input, scale and bias.
+// It takes the input, the scale, the output and the gradient of the output as its inputs, and returns a vector of
+// the gradients wrt the input, the scale and the bias, one after the other.
+type batchNormDiffOp struct {
+	fwd *batchNormOp
+}
+
+func (op batchNormDiffOp) Arity() int { return 4 }
+
+// batchNormDiffOp has this type:
+//		op :: Tensor-d a → Tensor-1 a → Tensor-d a → Tensor-d a → Tensor-1 a
+func (op batchNormDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	t := newTensorType(op.fwd.dims, a)
+	v := newTensorType(1, a)
+	return hm.NewFnType(t, v, t, t, v)
+}
+
+func (op batchNormDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+	scale, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the scale. Got %T instead", inputs[1])
+	}
+	return tensor.Shape{x.TotalSize() + 2*scale.TotalSize()}, nil
+}
+
+func (op batchNormDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, scale, dy *tensor.Dense
+	if x, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if scale, err = contiguousDense(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if dy, err = contiguousDense(inputs[3]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	// the gradients are written into parts of the backing of the result
+	size, c := x.Shape().TotalSize(), scale.Shape().TotalSize()
+	all := tensor.New(tensor.Of(x.Dtype()), tensor.WithShape(size+2*c))
+	var ds [3]*tensor.Dense
+	for i, r := range [][2]int{{0, size}, {size, size + c}, {size + c, size + 2*c}} {
+		switch data := all.Data().(type) {
+		case []float64:
+			ds[i] = tensor.New(tensor.WithBacking(data[r[0]:r[1]]), tensor.WithShape(r[1]-r[0]))
+		case []float32:
+			ds[i] = tensor.New(tensor.WithBacking(data[r[0]:r[1]]), tensor.WithShape(r[1]-r[0]))
+		default:
+			return nil, errors.Errorf(nyiFail, "batchNormDiffOp.Do()", x.Dtype())
+		}
+	}
+	if err = op.fwd.diff(x, scale, dy, ds[0], ds[1], ds[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return all, nil
+}
+
+func (op batchNormDiffOp) ReturnsPtr() bool     { return false }
+func (op batchNormDiffOp) CallsExtern() bool    { return false }
+func (op batchNormDiffOp) OverwritesInput() int { return -1 }
+
+func (op batchNormDiffOp) WriteHash(h hash.Hash) {
+	op.fwd.WriteHash(h)
+	fmt.Fprintf(h, "Diff")
+}
+
+func (op batchNormDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op batchNormDiffOp) String() string { return fmt.Sprintf("∂%v", op.fwd) }
+
+// dropoutOp zeroes out each value of its input with probability prob when training, and scales the values that are kept
+// by 1/(1-prob), so that the expected value of the output is the input. When doing inference, it is the identity.
+// The mask is drawn from the RNG of the graph.
+//
+// The scaled mask of the last forwards pass is kept in the op for the backwards pass. It is nil if the last pass was an inference pass.
+// As the mask (and the mode) are state of the op, a graph with dropout can't be run by two VMs at the same time.
+type dropoutOp struct {
+	prob     float64
+	dims     int
+	training bool
+	rng      *RNG
+
+	mask []float64
+}
+
+func newDropoutOp(prob float64, dims int, rng *RNG) *dropoutOp {
+	return &dropoutOp{
+		prob:     prob,
+		dims:     dims,
+		training: true,
+		rng:      rng,
+	}
+}
+
+func (op *dropoutOp) Arity() int { return 1 }
+
+// dropoutOp has this type:
+//		op :: Tensor-d a → Tensor-d a
+// where a Tensor-0 a is an a.
+func (op *dropoutOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	var t hm.Type = a
+	if op.dims > 0 {
+		t = newTensorType(op.dims, a)
+	}
+	return hm.NewFnType(t, t)
+}
+
+func (op *dropoutOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	return s.Clone(), nil
+}
+
+func (op *dropoutOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x []float64
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	y := make([]float64, len(x))
+	if !op.training {
+		op.mask = nil
+		copy(y, x)
+		return valueFromFloat64s(inputs[0].Dtype(), inputs[0].Shape(), y)
+	}
+
+	scale := 1 / (1 - op.prob)
+	op.mask = op.rng.Uniform64(0, 1, len(x))
+	for i, u := range op.mask {
+		if u < op.prob {
+			op.mask[i] = 0
+		} else {
+			op.mask[i] = scale
+		}
+		y[i] = x[i] * op.mask[i]
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), inputs[0].Shape(), y)
+}
+
+// diff computes the gradient with regards to the input, given the gradient of the output.
+func (op *dropoutOp) diff(dy []float64) ([]float64, error) {
+	dx := make([]float64, len(dy))
+	if op.mask == nil {
+		copy(dx, dy)
+		return dx, nil
+	}
+	if len(op.mask) != len(dy) {
+		return nil, errors.Errorf("%v: no forwards pass has been done for a gradient of size %d", op, len(dy))
+	}
+	for i, m := range op.mask {
+		dx[i] = dy[i] * m
+	}
+	return dx, nil
+}
+
+func (op *dropoutOp) SetTraining(training bool) { op.training = training }
+
+func (op *dropoutOp) ReturnsPtr() bool     { return false }
+func (op *dropoutOp) CallsExtern() bool    { return false }
+func (op *dropoutOp) OverwritesInput() int { return -1 }
+
+// WriteHash writes the address of the op as well, as every dropout draws its own mask.
+func (op *dropoutOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "Dropout%v%d%p", op.prob, op.dims, op) }
+
+func (op *dropoutOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op *dropoutOp) String() string { return fmt.Sprintf("Dropout(%v)", op.prob) }
+
+func (op *dropoutOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op *dropoutOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	// the output is passed in to ensure that the forwards pass has happened by the time the gradient is computed
+	var dx *Node
+	if dx, err = applyOp(dropoutDiffOp{op}, output, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op *dropoutOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = (dropoutDiffOp{op}).Do(ydv.Value, ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if d, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return xdv.SetDeriv(d)
+}
+
+// dropoutDiffOp computes the gradient of a dropoutOp. It takes the output and the gradient of the output as its inputs.
+type dropoutDiffOp struct {
+	fwd *dropoutOp
+}
+
+func (op dropoutDiffOp) Arity() int { return 2 }
+
+// dropoutDiffOp has this type:
+//		op :: Tensor-d a → Tensor-d a → Tensor-d a
+func (op dropoutDiffOp) Type() hm.Type {
+	t := op.fwd.Type().(*hm.FunctionType).Arg()
+	return hm.NewFnType(t, t, t)
+}
+
+func (op dropoutDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[1])
+	}
+	return s.Clone(), nil
+}
+
+func (op dropoutDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dy, dx []float64
+	if dy, err = float64sOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if dx, err = op.fwd.diff(dy); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[1].Dtype(), inputs[1].Shape(), dx)
+}
+
+func (op dropoutDiffOp) ReturnsPtr() bool     { return false }
+func (op dropoutDiffOp) CallsExtern() bool    { return false }
+func (op dropoutDiffOp) OverwritesInput() int { return -1 }
+
+func (op dropoutDiffOp) WriteHash(h hash.Hash) {
+	op.fwd.WriteHash(h)
+	h.Write([]byte("Diff"))
+}
+
+func (op dropoutDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op dropoutDiffOp) String() string { return fmt.Sprintf("∂%v", op.fwd) }
+
+// ifTrainingOp returns its first input when training, and its second input when doing inference. The gradient only flows
+// to the input that was returned by the last forwards pass.
+type ifTrainingOp struct {
+	dims     int
+	training bool
+
+	wasTrain bool
+}
+
+func newIfTrainingOp(dims int) *ifTrainingOp {
+	return &ifTrainingOp{
+		dims:     dims,
+		training: true,
+		wasTrain: true,
+	}
+}
+
+func (op *ifTrainingOp) Arity() int { return 2 }
+
+// ifTrainingOp has this type:
+//		op :: Tensor-d a → Tensor-d a → Tensor-d a
+// where a Tensor-0 a is an a.
+func (op *ifTrainingOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	var t hm.Type = a
+	if op.dims > 0 {
+		t = newTensorType(op.dims, a)
+	}
+	return hm.NewFnType(t, t, t)
+}
+
+func (op *ifTrainingOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	s2, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[1])
+	}
+	if !sameShape(s, s2) {
+		return nil, errors.Errorf("%v expects both inputs to be of the same shape. Got %v and %v instead", op, s, s2)
+	}
+	return s.Clone(), nil
+}
+
+func (op *ifTrainingOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	op.wasTrain = op.training
+	if op.training {
+		return CloneValue(inputs[0])
+	}
+	return CloneValue(inputs[1])
+}
+
+func (op *ifTrainingOp) SetTraining(training bool) { op.training = training }
+
+func (op *ifTrainingOp) ReturnsPtr() bool     { return false }
+func (op *ifTrainingOp) CallsExtern() bool    { return false }
+func (op *ifTrainingOp) OverwritesInput() int { return -1 }
+
+// WriteHash writes the address of the op as well, as the op holds the mode of its last forwards pass.
+func (op *ifTrainingOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "IfTraining%d%p", op.dims, op) }
+
+func (op *ifTrainingOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op *ifTrainingOp) String() string { return "IfTraining" }
+
+func (op *ifTrainingOp) DiffWRT(inputs int) []bool { return []bool{true, true} }
+
+func (op *ifTrainingOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	// the output is passed in to ensure that the forwards pass has happened by the time the gradient is computed
+	retVal = make(Nodes, 2)
+	for i := range retVal {
+		if retVal[i], err = applyOp(ifTrainingDiffOp{op, i == 0}, output, grad); err != nil {
+			return nil, errors.Wrap(err, applyOpFail)
+		}
+		retVal[i].setGroup(gradClust)
+	}
+	return
+}
+
+func (op *ifTrainingOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	ydv := output.boundTo.(*dualValue)
+	for i, in := range inputs {
+		if (i == 0) != op.wasTrain {
+			continue
+		}
+		xdv := in.boundTo.(*dualValue)
+		add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(ydv.d))
+		var d Value
+		if d, err = add.UnsafeDo(xdv.d, ydv.d); err != nil {
+			return errors.Wrapf(err, unsafeDoFail, add)
+		}
+		if err = xdv.SetDeriv(d); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// ifTrainingDiffOp computes the gradient of an ifTrainingOp with regards to one of its inputs: the gradient of the output
+// if that input was returned by the last forwards pass, and zeros otherwise. It takes the output and the gradient of the output as its inputs.
+type ifTrainingDiffOp struct {
+	fwd   *ifTrainingOp
+	train bool // whether the gradient is with regards to the first input
+}
+
+func (op ifTrainingDiffOp) Arity() int { return 2 }
+
+// ifTrainingDiffOp has this type:
+//		op :: Tensor-d a → Tensor-d a → Tensor-d a
+func (op ifTrainingDiffOp) Type() hm.Type {
+	t := op.fwd.Type().(*hm.FunctionType).Arg()
+	return hm.NewFnType(t, t, t)
+}
+
+func (op ifTrainingDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[1])
+	}
+	return s.Clone(), nil
+}
+
+func (op ifTrainingDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	if op.train == op.fwd.wasTrain {
+		return CloneValue(inputs[1])
+	}
+	var dy []float64
+	if dy, err = float64sOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[1].Dtype(), inputs[1].Shape(), make([]float64, len(dy)))
+}
+
+func (op ifTrainingDiffOp) ReturnsPtr() bool     { return false }
+func (op ifTrainingDiffOp) CallsExtern() bool    { return false }
+func (op ifTrainingDiffOp) OverwritesInput() int { return -1 }
+
+func (op ifTrainingDiffOp) WriteHash(h hash.Hash) {
+	op.fwd.WriteHash(h)
+	fmt.Fprintf(h, "Diff%t", op.train)
+}
+
+func (op ifTrainingDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op ifTrainingDiffOp) String() string { return fmt.Sprintf("∂%v/∂%t", op.fwd, op.train) }
+
+// normOp is a fused layer normalization or RMS normalization. The input is normalized over the axes in `along`,
+// and then scaled by an optional gain and shifted by an optional bias. The gain and bias have the shape of
+// the normalized axes, and are broadcast across the other axes.
+//
+// Layer normalization:
+//		y = gain * (x - mean(x)) / sqrt(var(x) + eps) + bias
+// RMS normalization:
+//		y = gain * x / sqrt(mean(x²) + eps) + bias
+//
+// Computations are done in float64, regardless of the Dtype of the input.
+type normOp struct {
+	rms              bool
+	along            axes
+	eps              float64
+	dims             int
+	hasGain, hasBias bool
+}
+
+func (op normOp) Arity() int {
+	arity := 1
+	if op.hasGain {
+		arity++
+	}
+	if op.hasBias {
+		arity++
+	}
+	return arity
+}
+
+func (op normOp) inputTypes() hm.Types {
+	a := hm.TypeVariable('a')
+	retVal := hm.Types{newTensorType(op.dims, a)}
+	if op.hasGain {
+		retVal = append(retVal, newTensorType(len(op.along), a))
+	}
+	if op.hasBias {
+		retVal = append(retVal, newTensorType(len(op.along), a))
+	}
+	return retVal
+}
+
+// normOp has this type:
+//		op :: Tensor-d a → Tensor-k a → Tensor-k a → Tensor-d a
+// where k is the number of axes normalized over. The gain and the bias are optional.
+func (op normOp) Type() hm.Type {
+	ts := op.inputTypes()
+	ts = append(ts, ts[0])
+	return hm.NewFnType(ts...)
+}
+
+func (op normOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	x, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the input. Got %T instead", inputs[0])
+	}
+
+	var paramSize = 1
+	for _, a := range op.along {
+		if a >= x.Dims() {
+			return nil, errors.Errorf("Axis %d is greater or equal to the length of the shape %v", a, x)
+		}
+		paramSize *= x[a]
+	}
+	for _, in := range inputs[1:] {
+		s, ok := in.(tensor.Shape)
+		if !ok {
+			return nil, errors.Errorf("Expected a shape for the gain or bias. Got %T instead", in)
+		}
+		if s.TotalSize() != paramSize {
+			return nil, errors.Errorf("%v: the gain and bias are expected to have %d elements. Got a shape of %v instead", op, paramSize, s)
+		}
+	}
+	return x.Clone(), nil
+}
+
+// indices returns, for each element of a tensor of the given shape, the index of the group that it is normalized in,
+// and the index of the gain and bias that applies to it.
+func (op normOp) indices(shape tensor.Shape) (groups, params []int, nGroups, nParams int) {
+	normed := make([]bool, len(shape))
+	nGroups, nParams = 1, 1
+	for _, a := range op.along {
+		normed[a] = true
+	}
+	for i, s := range shape {
+		if normed[i] {
+			nParams *= s
+		} else {
+			nGroups *= s
+		}
+	}
+
+	size := shape.TotalSize()
+	groups = make([]int, size)
+	params = make([]int, size)
+	coord := make([]int, len(shape))
+	for i := 0; i < size; i++ {
+		var g, p int
+		for d, c := range coord {
+			if normed[d] {
+				p = p*shape[d] + c
+			} else {
+				g = g*shape[d] + c
+			}
+		}
+		groups[i], params[i] = g, p
+
+		// increment the coordinates, row major
+		for d := len(coord) - 1; d >= 0; d-- {
+			coord[d]++
+			if coord[d] < shape[d] {
+				break
+			}
+			coord[d] = 0
+		}
+	}
+	return
+}
+
+// stats computes the normalized input, as well as the reciprocal of the standard deviation (or of the RMS) of each group.
+func (op normOp) stats(x []float64, groups []int, nGroups, nParams int) (xhat, invStd []float64) {
+	mean := make([]float64, nGroups)
+	if !op.rms {
+		for i, v := range x {
+			mean[groups[i]] += v
+		}
+		for g := range mean {
+			mean[g] /= float64(nParams)
+		}
+	}
+
+	invStd = make([]float64, nGroups)
+	for i, v := range x {
+		d := v - mean[groups[i]]
+		invStd[groups[i]] += d * d
+	}
+	for g, v := range invStd {
+		invStd[g] = 1 / math.Sqrt(v/float64(nParams)+op.eps)
+	}
+
+	xhat = make([]float64, len(x))
+	for i, v := range x {
+		xhat[i] = (v - mean[groups[i]]) * invStd[groups[i]]
+	}
+	return
+}
+
+func (op normOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, gain, bias []float64
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	next := 1
+	if op.hasGain {
+		if gain, err = float64sOf(inputs[next]); err != nil {
+			return nil, errors.Wrapf(err, doFail, op)
+		}
+		next++
+	}
+	if op.hasBias {
+		if bias, err = float64sOf(inputs[next]); err != nil {
+			return nil, errors.Wrapf(err, doFail, op)
+		}
+	}
+
+	shape := inputs[0].Shape()
+	groups, params, nGroups, nParams := op.indices(shape)
+	xhat, _ := op.stats(x, groups, nGroups, nParams)
+
+	y := xhat
+	for i := range y {
+		if gain != nil {
+			y[i] *= gain[params[i]]
+		}
+		if bias != nil {
+			y[i] += bias[params[i]]
+		}
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), shape, y)
+}
+
+// diff computes the gradients with regards to the input, gain and bias, given the gradient of the output.
+// The returned gradients are in the same order as the inputs of the op.
+func (op normOp) diff(inputs []Value, grad Value) (retVal [][]float64, err error) {
+	var x, gain, dy []float64
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return
+	}
+	if op.hasGain {
+		if gain, err = float64sOf(inputs[1]); err != nil {
+			return
+		}
+	}
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+
+	groups, params, nGroups, nParams := op.indices(inputs[0].Shape())
+	xhat, invStd := op.stats(x, groups, nGroups, nParams)
+
+	// g is the gradient wrt xhat
+	g := make([]float64, len(dy))
+	copy(g, dy)
+	if gain != nil {
+		for i := range g {
+			g[i] *= gain[params[i]]
+		}
+	}
+
+	meanG := make([]float64, nGroups)
+	meanGX := make([]float64, nGroups)
+	for i := range g {
+		meanG[groups[i]] += g[i]
+		meanGX[groups[i]] += g[i] * xhat[i]
+	}
+	for i := range meanG {
+		meanG[i] /= float64(nParams)
+		meanGX[i] /= float64(nParams)
+	}
+
+	dx := make([]float64, len(x))
+	for i := range dx {
+		grp := groups[i]
+		if op.rms {
+			dx[i] = invStd[grp] * (g[i] - xhat[i]*meanGX[grp])
+		} else {
+			dx[i] = invStd[grp] * (g[i] - meanG[grp] - xhat[i]*meanGX[grp])
+		}
+	}
+	retVal = append(retVal, dx)
+
+	if op.hasGain {
+		dgain := make([]float64, nParams)
+		for i, d := range dy {
+			dgain[params[i]] += d * xhat[i]
+		}
+		retVal = append(retVal, dgain)
+	}
+	if op.hasBias {
+		dbias := make([]float64, nParams)
+		for i, d := range dy {
+			dbias[params[i]] += d
+		}
+		retVal = append(retVal, dbias)
+	}
+	return
+}
+
+func (op normOp) ReturnsPtr() bool     { return false }
+func (op normOp) CallsExtern() bool    { return false }
+func (op normOp) OverwritesInput() int { return -1 }
+
+func (op normOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "%v%v%d%t%t", op, op.eps, op.dims, op.hasGain, op.hasBias)
+}
+
+func (op normOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op normOp) String() string {
+	if op.rms {
+		return fmt.Sprintf("RMSNorm%v", op.along)
+	}
+	return fmt.Sprintf("LayerNorm%v", op.along)
+}
+
+func (op normOp) DiffWRT(inputs int) []bool {
+	retVal := make([]bool, inputs)
+	for i := range retVal {
+		retVal[i] = true
+	}
+	return retVal
+}
+
+func (op normOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	children := make(Nodes, len(inputs), len(inputs)+1)
+	copy(children, inputs)
+	children = append(children, grad)
+
+	retVal = make(Nodes, len(inputs))
+	for i := range inputs {
+		if retVal[i], err = applyOp(normDiffOp{op, i}, children...); err != nil {
+			return nil, errors.Wrap(err, applyOpFail)
+		}
+		retVal[i].setGroup(gradClust)
+	}
+	return
+}
+
+func (op normOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	vals := make([]Value, len(inputs))
+	for i, in := range inputs {
+		vals[i] = in.boundTo.(*dualValue).Value
+	}
+	ydv := output.boundTo.(*dualValue)
+
+	var grads [][]float64
+	if grads, err = op.diff(vals, ydv.d); err != nil {
+		return
+	}
+
+	for i, d := range grads {
+		dv := inputs[i].boundTo.(*dualValue)
+		var v Value
+		if v, err = valueFromFloat64s(dv.d.Dtype(), dv.d.Shape(), d); err != nil {
+			return
+		}
+		add := newEBOByType(addOpType, TypeOf(dv.d), TypeOf(v))
+		if _, err = add.UnsafeDo(dv.d, v); err != nil {
+			return errors.Wrapf(err, unsafeDoFail, add)
+		}
+	}
+	return nil
+}
+
+// normDiffOp computes the gradient of a normOp with regards to one of its inputs.
+// It takes the inputs of the normOp, followed by the gradient of the output as its inputs.
+type normDiffOp struct {
+	fwd normOp
+	wrt int
+}
+
+func (op normDiffOp) Arity() int { return op.fwd.Arity() + 1 }
+
+// normDiffOp has this type:
+//		op :: Tensor-d a → Tensor-k a → Tensor-k a → Tensor-d a → b
+// where b is the type of the input the gradient is taken with regards to.
+func (op normDiffOp) Type() hm.Type {
+	ts := op.fwd.inputTypes()
+	wrt := ts[op.wrt]
+	ts = append(ts, ts[0], wrt)
+	return hm.NewFnType(ts...)
+}
+
+func (op normDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[op.wrt].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[op.wrt])
+	}
+	return s.Clone(), nil
+}
+
+func (op normDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	last := len(inputs) - 1
+	var grads [][]float64
+	if grads, err = op.fwd.diff(inputs[:last], inputs[last]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[op.wrt].Dtype(), inputs[op.wrt].Shape(), grads[op.wrt])
+}
+
+func (op normDiffOp) ReturnsPtr() bool     { return false }
+func (op normDiffOp) CallsExtern() bool    { return false }
+func (op normDiffOp) OverwritesInput() int { return -1 }
+
+func (op normDiffOp) WriteHash(h hash.Hash) {
+	op.fwd.WriteHash(h)
+	fmt.Fprintf(h, "Diff%d", op.wrt)
+}
+
+func (op normDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op normDiffOp) String() string { return fmt.Sprintf("∂%v/∂%d", op.fwd, op.wrt) }
+
+/* EMBEDDING */
+
+// embeddingOp looks up the rows of a table (a matrix of shape (rows, dims)) given a tensor of integer indices.
+// The result has the shape of the indices with an extra trailing dimension of dims.
+//
+// The gradient wrt the table is row sparse - only the rows that were looked up are incremented.
+// The indices are not differentiable.
+type embeddingOp struct {
+	idxDims int // dimensions of the indices
+}
+
+func (op embeddingOp) Arity() int { return 2 }
+
+func (op embeddingOp) indexType() hm.Type {
+	if op.idxDims == 0 {
+		return Int
+	}
+	return newTensorType(op.idxDims, Int)
+}
+
+// embeddingOp has this type:
+//		op :: Tensor-2 a → Tensor-k Int → Tensor-(k+1) a
+// where k is the number of dimensions of the indices. Scalar indices have the type Int.
+func (op embeddingOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(newTensorType(2, a), op.indexType(), newTensorType(op.idxDims+1, a))
+}
+
+func (op embeddingOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	table, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the table. Got %T instead", inputs[0])
+	}
+	idx, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the indices. Got %T instead", inputs[1])
+	}
+	if table.Dims() != 2 {
+		return nil, errors.Errorf("Expected the table to be a matrix. Got %v instead", table)
+	}
+
+	retVal := make(tensor.Shape, 0, len(idx)+1)
+	retVal = append(retVal, idx...)
+	return append(retVal, table[1]), nil
+}
+
+func (op embeddingOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var table *tensor.Dense
+	var idx []int
+	if table, err = contiguousDense(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if idx, err = intsOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	rows, dims := table.Shape()[0], table.Shape()[1]
+	if err = checkEmbeddingIndices(idx, rows); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	shape := make(tensor.Shape, 0, len(inputs[1].Shape())+1)
+	shape = append(shape, inputs[1].Shape()...)
+	shape = append(shape, dims)
+
+	switch data := table.Data().(type) {
+	case []float64:
+		backing := make([]float64, len(idx)*dims)
+		for i, r := range idx {
+			copy(backing[i*dims:(i+1)*dims], data[r*dims:(r+1)*dims])
+		}
+		retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(shape...))
+	case []float32:
+		backing := make([]float32, len(idx)*dims)
+		for i, r := range idx {
+			copy(backing[i*dims:(i+1)*dims], data[r*dims:(r+1)*dims])
+		}
+		retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(shape...))
+	default:
+		err = errors.Errorf(nyiTypeFail, "embeddingOp.Do()", data)
+	}
+	return
+}
+
+// scatter increments the rows of dtable given by idx with the gradient of the output.
+func (op embeddingOp) scatter(dtable *tensor.Dense, idx []int, grad Value) (err error) {
+	var dy []float64
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+
+	dims := dtable.Shape()[1]
+	if err = checkEmbeddingIndices(idx, dtable.Shape()[0]); err != nil {
+		return
+	}
+	if len(dy) != len(idx)*dims {
+		return errors.Errorf("Expected the gradient to have %d elements. Got %d instead", len(idx)*dims, len(dy))
+	}
+
+	switch data := dtable.Data().(type) {
+	case []float64:
+		for i, r := range idx {
+			row := data[r*dims : (r+1)*dims]
+			for j, d := range dy[i*dims : (i+1)*dims] {
+				row[j] += d
+			}
+		}
+	case []float32:
+		for i, r := range idx {
+			row := data[r*dims : (r+1)*dims]
+			for j, d := range dy[i*dims : (i+1)*dims] {
+				row[j] += float32(d)
+			}
+		}
+	default:
+		return errors.Errorf(nyiTypeFail, "embeddingOp.scatter()", data)
+	}
+	return nil
+}
+
+func checkEmbeddingIndices(idx []int, rows int) error {
+	for _, r := range idx {
+		if r < 0 || r >= rows {
+			return errors.Errorf("Index %d is out of bounds for a table of %d rows", r, rows)
+		}
+	}
+	return nil
+}
+
+func (op embeddingOp) ReturnsPtr() bool     { return false }
+func (op embeddingOp) CallsExtern() bool    { return false }
+func (op embeddingOp) OverwritesInput() int { return -1 }
+
+func (op embeddingOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "Embedding%d", op.idxDims) }
+
+func (op embeddingOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op embeddingOp) String() string { return "Embedding" }
+
+func (op embeddingOp) DiffWRT(inputs int) []bool { return []bool{true, false} }
+
+func (op embeddingOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	diff := embeddingDiffOp{op, inputs[0].Shape()}
+	var dtable *Node
+	if dtable, err = applyOp(diff, inputs[1], grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dtable.setGroup(gradClust)
+	return Nodes{dtable, nil}, nil
+}
+
+// DoDiff increments the rows of the table's gradient in place.
+func (op embeddingOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	tdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var idx []int
+	if idx, err = intsOf(inputs[1].Value()); err != nil {
+		return
+	}
+
+	dtable, ok := tdv.d.(*tensor.Dense)
+	if !ok {
+		return errors.Errorf(nyiTypeFail, "embeddingOp.DoDiff()", tdv.d)
+	}
+	return op.scatter(dtable, idx, ydv.d)
+}
+
+// embeddingDiffOp computes the gradient of an embeddingOp wrt to the table.
+// It takes the indices and the gradient of the output as its inputs, and returns a gradient of the shape of the table.
+// Only the rows that were looked up are non-zero.
+type embeddingDiffOp struct {
+	embeddingOp
+	shape tensor.Shape // shape of the table
+}
+
+func (op embeddingDiffOp) Arity() int { return 2 }
+
+// embeddingDiffOp has this type:
+//		op :: Tensor-k Int → Tensor-(k+1) a → Tensor-2 a
+func (op embeddingDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(op.indexType(), newTensorType(op.idxDims+1, a), newTensorType(2, a))
+}
+
+func (op embeddingDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.shape.Clone(), nil
+}
+
+func (op embeddingDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var idx []int
+	if idx, err = intsOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	dtable := tensor.New(tensor.Of(inputs[1].Dtype()), tensor.WithShape(op.shape.Clone()...))
+	if err = op.scatter(dtable, idx, inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return dtable, nil
+}
+
+func (op embeddingDiffOp) WriteHash(h hash.Hash) {
+	op.embeddingOp.WriteHash(h)
+	fmt.Fprintf(h, "Diff%v", op.shape)
+}
+
+func (op embeddingDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op embeddingDiffOp) String() string { return fmt.Sprintf("∂%v", op.embeddingOp) }
+
+func (op embeddingDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false} }
+
+func (op embeddingDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op embeddingDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+/* SOFTMAX CROSS ENTROPY */
+
+// softmaxXentOp computes the cross entropy between the softmax of the logits and the targets along an axis, using the log-sum-exp trick:
+//		loss = Σ t·(logsumexp(x) - x)
+// The targets are either of the same shape as the logits (typically one-hot), or integer classes, in which case the shape is the
+// shape of the logits without the axis. When smoothing is non-zero, the targets are smoothed to (1-smoothing)·t + smoothing/classes.
+// When kl is true, the entropy of the targets is subtracted, which makes the loss the KL divergence of the softmax from the targets:
+//		loss = Σ t·(log(t) + logsumexp(x) - x)
+// where 0·log(0) is taken to be 0. The gradient is the same.
+//
+// The targets are not differentiable.
+type softmaxXentOp struct {
+	axis       int
+	dims       int
+	intTargets bool
+	smoothing  float64
+	kl         bool
+}
+
+func (op softmaxXentOp) Arity() int { return 2 }
+
+func (op softmaxXentOp) reducedType(of hm.Type) hm.Type {
+	if op.dims == 1 {
+		return of
+	}
+	return newTensorType(op.dims-1, of)
+}
+
+func (op softmaxXentOp) inputTypes() hm.Types {
+	a := hm.TypeVariable('a')
+	if op.intTargets {
+		return hm.Types{newTensorType(op.dims, a), op.reducedType(Int)}
+	}
+	return hm.Types{newTensorType(op.dims, a), newTensorType(op.dims, a)}
+}
+
+// softmaxXentOp has either of these types:
+//		op :: Tensor-d a → Tensor-d a → Tensor-(d-1) a
+//		op :: Tensor-d a → Tensor-(d-1) Int → Tensor-(d-1) a
+// A Tensor-0 a is a.
+func (op softmaxXentOp) Type() hm.Type {
+	ts := op.inputTypes()
+	return hm.NewFnType(ts[0], ts[1], op.reducedType(hm.TypeVariable('a')))
+}
+
+func (op softmaxXentOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	logits, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the logits. Got %T instead", inputs[0])
+	}
+	targets, ok := inputs[1].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the targets. Got %T instead", inputs[1])
+	}
+	if op.axis >= logits.Dims() {
+		return nil, errors.Errorf("Axis %d is greater or equal to the length of the shape %v", op.axis, logits)
+	}
+
+	retVal := make(tensor.Shape, 0, len(logits)-1)
+	retVal = append(retVal, logits[:op.axis]...)
+	retVal = append(retVal, logits[op.axis+1:]...)
+
+	expected := logits
+	if op.intTargets {
+		expected = retVal
+	}
+	if expected.TotalSize() != targets.TotalSize() {
+		return nil, errors.Errorf("%v: expected the targets to have a shape of %v. Got %v instead", op, expected, targets)
+	}
+	return retVal, nil
+}
+
+// geometry returns the number of elements before the axis, the number of classes, and the number of elements after the axis
+func (op softmaxXentOp) geometry(shape tensor.Shape) (outer, classes, inner int) {
+	outer, inner = 1, 1
+	for _, s := range shape[:op.axis] {
+		outer *= s
+	}
+	for _, s := range shape[op.axis+1:] {
+		inner *= s
+	}
+	return outer, shape[op.axis], inner
+}
+
+// smoothedTargets returns the targets, laid out like the logits, with the label smoothing applied.
+func (op softmaxXentOp) smoothedTargets(targets Value, shape tensor.Shape) (retVal []float64, err error) {
+	outer, classes, inner := op.geometry(shape)
+	retVal = make([]float64, shape.TotalSize())
+	if op.intTargets {
+		var idx []int
+		if idx, err = intsOf(targets); err != nil {
+			return nil, err
+		}
+		if len(idx) != outer*inner {
+			return nil, errors.Errorf("Expected %d targets. Got %d instead", outer*inner, len(idx))
+		}
+		for o := 0; o < outer; o++ {
+			for i := 0; i < inner; i++ {
+				c := idx[o*inner+i]
+				if c < 0 || c >= classes {
+					return nil, errors.Errorf("Target class %d is out of bounds for %d classes", c, classes)
+				}
+				retVal[(o*classes+c)*inner+i] = 1
+			}
+		}
+	} else {
+		var t []float64
+		if t, err = float64sOf(targets); err != nil {
+			return nil, err
+		}
+		if len(t) != len(retVal) {
+			return nil, errors.Errorf("Expected %d targets. Got %d instead", len(retVal), len(t))
+		}
+		copy(retVal, t)
+	}
+
+	if op.smoothing > 0 {
+		for j := range retVal {
+			retVal[j] = retVal[j]*(1-op.smoothing) + op.smoothing/float64(classes)
+		}
+	}
+	return
+}
+
+// xent computes the loss of each sample, the softmax of the logits, and the sum of the targets of each sample.
+func (op softmaxXentOp) xent(x, t []float64, shape tensor.Shape) (loss, softmax, sumT []float64) {
+	outer, classes, inner := op.geometry(shape)
+	loss = make([]float64, outer*inner)
+	sumT = make([]float64, outer*inner)
+	softmax = make([]float64, len(x))
+	for o := 0; o < outer; o++ {
+		for i := 0; i < inner; i++ {
+			start := o*classes*inner + i
+			max := math.Inf(-1)
+			for k := 0; k < classes; k++ {
+				if v := x[start+k*inner]; v > max {
+					max = v
+				}
+			}
+			var sum float64
+			for k := 0; k < classes; k++ {
+				sum += math.Exp(x[start+k*inner] - max)
+			}
+			lse := max + math.Log(sum)
+
+			s := o*inner + i
+			for k := 0; k < classes; k++ {
+				j := start + k*inner
+				softmax[j] = math.Exp(x[j] - lse)
+				if t[j] != 0 {
+					loss[s] += t[j] * (lse - x[j])
+					sumT[s] += t[j]
+					if op.kl {
+						loss[s] += t[j] * math.Log(t[j])
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func (op softmaxXentOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	shape := inputs[0].Shape()
+	var x, t []float64
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if t, err = op.smoothedTargets(inputs[1], shape); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	loss, _, _ := op.xent(x, t, shape)
+	if op.dims == 1 {
+		switch inputs[0].Dtype() {
+		case Float64:
+			return newF64(loss[0]), nil
+		case Float32:
+			return newF32(float32(loss[0])), nil
+		}
+	}
+
+	outShape := make(tensor.Shape, 0, len(shape)-1)
+	outShape = append(outShape, shape[:op.axis]...)
+	outShape = append(outShape, shape[op.axis+1:]...)
+	return valueFromFloat64s(inputs[0].Dtype(), outShape, loss)
+}
+
+// diff computes the gradient wrt the logits: grad·(softmax·Σt - t), which is grad·(softmax - t) when the targets sum to 1.
+func (op softmaxXentOp) diff(logits, targets, grad Value) (retVal []float64, err error) {
+	shape := logits.Shape()
+	var x, t, dy []float64
+	if x, err = float64sOf(logits); err != nil {
+		return
+	}
+	if t, err = op.smoothedTargets(targets, shape); err != nil {
+		return
+	}
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+
+	_, softmax, sumT := op.xent(x, t, shape)
+	outer, classes, inner := op.geometry(shape)
+	retVal = softmax
+	for o := 0; o < outer; o++ {
+		for k := 0; k < classes; k++ {
+			for i := 0; i < inner; i++ {
+				j := (o*classes+k)*inner + i
+				s := o*inner + i
+				retVal[j] = dy[s] * (softmax[j]*sumT[s] - t[j])
+			}
+		}
+	}
+	return
+}
+
+func (op softmaxXentOp) ReturnsPtr() bool     { return false }
+func (op softmaxXentOp) CallsExtern() bool    { return false }
+func (op softmaxXentOp) OverwritesInput() int { return -1 }
+
+func (op softmaxXentOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "SoftmaxXent%d%d%t%v%t", op.axis, op.dims, op.intTargets, op.smoothing, op.kl)
+}
+
+func (op softmaxXentOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op softmaxXentOp) String() string {
+	if op.kl {
+		return fmt.Sprintf("KLDiv{%d}", op.axis)
+	}
+	if op.smoothing > 0 {
+		return fmt.Sprintf("SoftmaxXent{%d, %v}", op.axis, op.smoothing)
+	}
+	return fmt.Sprintf("SoftmaxXent{%d}", op.axis)
+}
+
+func (op softmaxXentOp) DiffWRT(inputs int) []bool { return []bool{true, false} }
+
+func (op softmaxXentOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(softmaxXentDiffOp{op}, inputs[0], inputs[1], grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx, nil}, nil
+}
+
+func (op softmaxXentOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var dx []float64
+	if dx, err = op.diff(xdv.Value, inputs[1].Value(), ydv.d); err != nil {
+		return
+	}
+
+	var v Value
+	if v, err = valueFromFloat64s(xdv.d.Dtype(), xdv.d.Shape(), dx); err != nil {
+		return
+	}
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(v))
+	if _, err = add.UnsafeDo(xdv.d, v); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// softmaxXentDiffOp computes the gradient of a softmaxXentOp wrt the logits.
+// It takes the logits, the targets and the gradient of the output as its inputs.
+type softmaxXentDiffOp struct {
+	softmaxXentOp
+}
+
+func (op softmaxXentDiffOp) Arity() int { return 3 }
+
+// softmaxXentDiffOp has this type:
+//		op :: Tensor-d a → b → Tensor-(d-1) a → Tensor-d a
+// where b is the type of the targets.
+func (op softmaxXentDiffOp) Type() hm.Type {
+	ts := op.inputTypes()
+	return hm.NewFnType(ts[0], ts[1], op.reducedType(hm.TypeVariable('a')), ts[0])
+}
+
+func (op softmaxXentDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the logits. Got %T instead", inputs[0])
+	}
+	return s.Clone(), nil
+}
+
+func (op softmaxXentDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx []float64
+	if dx, err = op.diff(inputs[0], inputs[1], inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), inputs[0].Shape(), dx)
+}
+
+func (op softmaxXentDiffOp) WriteHash(h hash.Hash) {
+	op.softmaxXentOp.WriteHash(h)
+	fmt.Fprintf(h, "Diff")
+}
+
+func (op softmaxXentDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op softmaxXentDiffOp) String() string { return fmt.Sprintf("∂%v", op.softmaxXentOp) }
+
+func (op softmaxXentDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false, false} }
+
+func (op softmaxXentDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op softmaxXentDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+// nllOp computes the negative log likelihood of the targets along an axis, given log probabilities:
+//		loss = -Σ t·x
+// where 0·x is taken to be 0, so classes that are not targets may have a log probability of -Inf.
+// It embeds a softmaxXentOp for the types, the shapes and the layout of the targets. The smoothing and kl fields are unused.
+//
+// The targets are not differentiable.
+type nllOp struct {
+	softmaxXentOp
+}
+
+func (op nllOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	shape := inputs[0].Shape()
+	var x, t []float64
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if t, err = op.smoothedTargets(inputs[1], shape); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	outer, classes, inner := op.geometry(shape)
+	loss := make([]float64, outer*inner)
+	for o := 0; o < outer; o++ {
+		for k := 0; k < classes; k++ {
+			for i := 0; i < inner; i++ {
+				if j := (o*classes+k)*inner + i; t[j] != 0 {
+					loss[o*inner+i] -= t[j] * x[j]
+				}
+			}
+		}
+	}
+	outShape := make(tensor.Shape, 0, len(shape)-1)
+	outShape = append(outShape, shape[:op.axis]...)
+	outShape = append(outShape, shape[op.axis+1:]...)
+	return valueFromFloat64s(inputs[0].Dtype(), outShape, loss)
+}
+
+// diff computes the gradient wrt the log probabilities: -grad·t
+func (op nllOp) diff(logProbs, targets, grad Value) (retVal []float64, err error) {
+	shape := logProbs.Shape()
+	var t, dy []float64
+	if t, err = op.smoothedTargets(targets, shape); err != nil {
+		return
+	}
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+
+	outer, classes, inner := op.geometry(shape)
+	retVal = t
+	for o := 0; o < outer; o++ {
+		for k := 0; k < classes; k++ {
+			for i := 0; i < inner; i++ {
+				j := (o*classes+k)*inner + i
+				retVal[j] = -t[j] * dy[o*inner+i]
+			}
+		}
+	}
+	return
+}
+
+func (op nllOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "NLL%d%d%t", op.axis, op.dims, op.intTargets)
+}
+
+func (op nllOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op nllOp) String() string { return fmt.Sprintf("NLL{%d}", op.axis) }
+
+func (op nllOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(nllDiffOp{op}, inputs[0], inputs[1], grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx, nil}, nil
+}
+
+func (op nllOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var dx []float64
+	if dx, err = op.diff(xdv.Value, inputs[1].Value(), ydv.d); err != nil {
+		return
+	}
+
+	var v Value
+	if v, err = valueFromFloat64s(xdv.d.Dtype(), xdv.d.Shape(), dx); err != nil {
+		return
+	}
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(v))
+	if _, err = add.UnsafeDo(xdv.d, v); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// nllDiffOp computes the gradient of a nllOp wrt the log probabilities.
+// It takes the log probabilities, the targets and the gradient of the output as its inputs.
+type nllDiffOp struct {
+	nllOp
+}
+
+func (op nllDiffOp) Arity() int { return 3 }
+
+// nllDiffOp has this type:
+//		op :: Tensor-d a → b → Tensor-(d-1) a → Tensor-d a
+// where b is the type of the targets.
+func (op nllDiffOp) Type() hm.Type {
+	ts := op.inputTypes()
+	return hm.NewFnType(ts[0], ts[1], op.reducedType(hm.TypeVariable('a')), ts[0])
+}
+
+func (op nllDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape for the log probabilities. Got %T instead", inputs[0])
+	}
+	return s.Clone(), nil
+}
+
+func (op nllDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx []float64
+	if dx, err = op.diff(inputs[0], inputs[1], inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), inputs[0].Shape(), dx)
+}
+
+func (op nllDiffOp) WriteHash(h hash.Hash) {
+	op.nllOp.WriteHash(h)
+	fmt.Fprintf(h, "Diff")
+}
+
+func (op nllDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op nllDiffOp) String() string { return fmt.Sprintf("∂%v", op.nllOp) }
+
+func (op nllDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false, false} }
+
+func (op nllDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op nllDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"sort"
 
 	"github.com/chewxy/gorgonia/tensor"
 	"github.com/chewxy/hm"
@@ -32,59 +33,27 @@ func newMaxOp(along axes, dim int) *maxOp {
 
 func (op maxOp) Arity() int { return 1 }
 
-func (op maxOp) Type() hm.Type {
-	a := hm.TypeVariable('a')
-	t := newTensorType(op.d, a)
+func (op maxOp) Type() hm.Type { return reductionType(op.d, op.along) }
 
-	var retType hm.Type
-	if op.d == 1 || len(op.along) == 0 || len(op.along) == op.d {
-		// then it redueces down
-		return hm.NewFnType(t, a)
-	}
-	retType = newTensorType(op.d-1, a)
-	return hm.NewFnType(t, retType)
+func (op maxOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	return reductionInferShape(op, op.along, inputs)
 }
 
-func (op maxOp) InferShape(...DimSizer) (tensor.Shape, error) { return scalarShape, nil } // TODO, THIS IS INCORRECT
-func (op maxOp) DiffWRT(i int) []bool                         { return []bool{true} }
+func (op maxOp) DiffWRT(i int) []bool { return []bool{true} }
 
 func (op maxOp) SymDiff(inputs Nodes, output, gradNode *Node) (retVal Nodes, err error) {
-	if err = checkArity(op, len(inputs)); err != nil {
-		return
-	}
-
-	t := inputs[0]
-	opDim := len(t.Shape())
-
-	var leftAxes []byte
-	for i := 0; i < opDim; i++ {
-		for _, ax := range op.along {
-			if i == ax {
-				leftAxes = append(leftAxes, byte(i))
-				break
-			}
-		}
-	}
-
-	var eq *Node
-	bcpat := NewBroadcastPattern(leftAxes, nil)
-	if eq, err = Broadcast(eqOpType, output, t, bcpat); err != nil {
-		return nil, errors.Wrap(err, operationError)
-	}
+	return reductionSymDiff(op, inputs, output, gradNode)
+}
 
-	retVal[0], err = Broadcast(mulOpType, gradNode, eq, bcpat)
-	if err != nil {
-		return nil, errors.Wrap(err, operationError)
-	}
-	return
+func (op maxOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	return reductionDoDiff(op, inputs, output)
 }
 
 func (op maxOp) Do(inputs ...Value) (retVal Value, err error) {
 	if err = checkArity(op, len(inputs)); err != nil {
 		return
 	}
-
-	return nil, errors.Errorf(nyiFail, "maxOp.Do", "maxOp")
+	return extremumDo(op, inputs[0], op.along, (*tensor.Dense).Max)
 }
 
 func (op maxOp) ReturnsPtr() bool     { return true }
@@ -108,15 +77,535 @@ func (op maxOp) Hashcode() uint32 {
 func (op maxOp) String() string { return fmt.Sprintf("MaxAlong%v", op.along) }
 func (op maxOp) isUnary() bool  { return true }
 
+func (op maxOp) inputDims() int     { return op.d }
+func (op maxOp) reducedAlong() axes { return op.along }
+
+// reduceDiff routes the gradient to every element that is equal to the max. In case of ties, each tied element gets the full gradient.
+func (op maxOp) reduceDiff(x, y, dy []float64, groups []int) []float64 {
+	return extremumDiff(x, y, dy, groups)
+}
+
+/* MIN OP */
+
+type minOp struct {
+	along axes
+	d     int
+}
+
+func newMinOp(along axes, dim int) minOp {
+	return minOp{
+		along: along,
+		d:     dim,
+	}
+}
+
+func (op minOp) Arity() int { return 1 }
+
+func (op minOp) Type() hm.Type { return reductionType(op.d, op.along) }
+
+func (op minOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	return reductionInferShape(op, op.along, inputs)
+}
+
+func (op minOp) DiffWRT(i int) []bool { return []bool{true} }
+
+func (op minOp) SymDiff(inputs Nodes, output, gradNode *Node) (retVal Nodes, err error) {
+	return reductionSymDiff(op, inputs, output, gradNode)
+}
+
+func (op minOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	return reductionDoDiff(op, inputs, output)
+}
+
+func (op minOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	return extremumDo(op, inputs[0], op.along, (*tensor.Dense).Min)
+}
+
+func (op minOp) ReturnsPtr() bool     { return false }
+func (op minOp) OverwritesInput() int { return -1 }
+func (op minOp) CallsExtern() bool    { return false }
+
+func (op minOp) WriteHash(h hash.Hash) {
+	h.Write([]byte("min"))
+	fmt.Fprintf(h, "%v->%v", op.d, op.along)
+}
+
+func (op minOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op minOp) String() string { return fmt.Sprintf("MinAlong%v", op.along) }
+func (op minOp) isUnary() bool  { return true }
+
+func (op minOp) inputDims() int     { return op.d }
+func (op minOp) reducedAlong() axes { return op.along }
+
+// reduceDiff routes the gradient to every element that is equal to the min. Ties are handled like maxOp does.
+func (op minOp) reduceDiff(x, y, dy []float64, groups []int) []float64 {
+	return extremumDiff(x, y, dy, groups)
+}
+
+/* PROD OP */
+
+type prodOp struct {
+	along axes
+	d     int
+}
+
+func newProdOp(along axes, dim int) prodOp {
+	return prodOp{
+		along: along,
+		d:     dim,
+	}
+}
+
+func (op prodOp) Arity() int { return 1 }
+
+func (op prodOp) Type() hm.Type { return reductionType(op.d, op.along) }
+
+func (op prodOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	return reductionInferShape(op, op.along, inputs)
+}
+
+func (op prodOp) DiffWRT(i int) []bool { return []bool{true} }
+
+func (op prodOp) SymDiff(inputs Nodes, output, gradNode *Node) (retVal Nodes, err error) {
+	return reductionSymDiff(op, inputs, output, gradNode)
+}
+
+func (op prodOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	return reductionDoDiff(op, inputs, output)
+}
+
+func (op prodOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	shape := inputs[0].Shape()
+	var outShape tensor.Shape
+	if outShape, err = reducedShape(shape, op.along); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	groups, _, nGroups, _ := axisGroups(shape, op.along)
+
+	if inputs[0].Dtype() == Int {
+		return op.reduceInts(inputs[0], outShape, groups, nGroups)
+	}
+
+	var x []float64
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), outShape, op.reduce(x, groups, nGroups))
+}
+
+func (op prodOp) ReturnsPtr() bool     { return false }
+func (op prodOp) OverwritesInput() int { return -1 }
+func (op prodOp) CallsExtern() bool    { return false }
+
+func (op prodOp) WriteHash(h hash.Hash) {
+	h.Write([]byte("prod"))
+	fmt.Fprintf(h, "%v->%v", op.d, op.along)
+}
+
+func (op prodOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op prodOp) String() string { return fmt.Sprintf("ProdAlong%v", op.along) }
+func (op prodOp) isUnary() bool  { return true }
+
+func (op prodOp) inputDims() int     { return op.d }
+func (op prodOp) reducedAlong() axes { return op.along }
+
+// reduce multiplies the elements of each group. groups holds the group of each element of x.
+func (op prodOp) reduce(x []float64, groups []int, nGroups int) []float64 {
+	retVal := make([]float64, nGroups)
+	for i := range retVal {
+		retVal[i] = 1
+	}
+	for i, v := range x {
+		retVal[groups[i]] *= v
+	}
+	return retVal
+}
+
+// reduceInts multiplies the elements of each group of an Int tensor. The products are computed in ints, so that they are exact.
+func (op prodOp) reduceInts(v Value, outShape tensor.Shape, groups []int, nGroups int) (retVal Value, err error) {
+	var t *tensor.Dense
+	if t, err = contiguousDense(v); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	prods := make([]int, nGroups)
+	for i := range prods {
+		prods[i] = 1
+	}
+	for i, x := range t.Data().([]int) {
+		prods[groups[i]] *= x
+	}
+
+	if len(outShape) == 0 {
+		return newI(prods[0]), nil
+	}
+	return tensor.New(tensor.WithBacking(prods), tensor.WithShape(outShape...)), nil
+}
+
+// reduceDiff computes the gradient of the product without dividing by the input, so that zeroes are handled:
+//		- if a group has no zeroes, the gradient of each element is grad * prod / x
+//		- if a group has exactly one zero, only the zero gets a gradient: grad * (product of the other elements)
+//		- if a group has more than one zero, the gradient is zero
+func (op prodOp) reduceDiff(x, y, dy []float64, groups []int) []float64 {
+	nonZero := make([]float64, len(y))
+	zeroes := make([]int, len(y))
+	for i := range nonZero {
+		nonZero[i] = 1
+	}
+	for i, v := range x {
+		if v == 0 {
+			zeroes[groups[i]]++
+		} else {
+			nonZero[groups[i]] *= v
+		}
+	}
+
+	retVal := make([]float64, len(x))
+	for i, v := range x {
+		g := groups[i]
+		switch {
+		case zeroes[g] == 0:
+			retVal[i] = dy[g] * nonZero[g] / v
+		case zeroes[g] == 1 && v == 0:
+			retVal[i] = dy[g] * nonZero[g]
+		}
+	}
+	return retVal
+}
+
+/* REDUCTION HELPERS */
+
+// floatReducer is a reduction whose backward pass is computed in float64.
+type floatReducer interface {
+	Op
+	inputDims() int
+	reducedAlong() axes
+
+	// reduceDiff computes the gradient wrt x, given the output y and the gradient of the output dy
+	reduceDiff(x, y, dy []float64, groups []int) []float64
+}
+
+// reductionType returns the type of a function that reduces a Tensor-d a along the given axes.
+func reductionType(d int, along axes) hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(newTensorType(d, a), reducedType(d, along, a))
+}
+
+func reducedType(d int, along axes, of hm.Type) hm.Type {
+	if d-len(along) <= 0 {
+		return of
+	}
+	return newTensorType(d-len(along), of)
+}
+
+// reducedShape returns the shape of a tensor of the given shape once it has been reduced along the axes.
+func reducedShape(s tensor.Shape, along axes) (tensor.Shape, error) {
+	reduced := make([]bool, len(s))
+	for _, a := range along {
+		if a < 0 || a >= len(s) {
+			return nil, errors.Errorf("Axis %d is out of bounds for the shape %v", a, s)
+		}
+		if reduced[a] {
+			return nil, errors.Errorf("Axis %d is repeated in %v", a, along)
+		}
+		reduced[a] = true
+	}
+
+	retVal := make(tensor.Shape, 0, len(s))
+	for i, d := range s {
+		if !reduced[i] {
+			retVal = append(retVal, d)
+		}
+	}
+	return retVal, nil
+}
+
+func reductionInferShape(op Op, along axes, inputs []DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	return reducedShape(s, along)
+}
+
+func reductionDiff(op floatReducer, x, y, grad Value) (retVal []float64, err error) {
+	var xs, ys, dy []float64
+	if xs, err = float64sOf(x); err != nil {
+		return
+	}
+	if ys, err = float64sOf(y); err != nil {
+		return
+	}
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+	groups, _, _, _ := axisGroups(x.Shape(), op.reducedAlong())
+	return op.reduceDiff(xs, ys, dy, groups), nil
+}
+
+func reductionSymDiff(op floatReducer, inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(reductionDiffOp{op}, inputs[0], output, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func reductionDoDiff(op floatReducer, inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var dx []float64
+	if dx, err = reductionDiff(op, xdv.Value, ydv.Value, ydv.d); err != nil {
+		return
+	}
+
+	var v Value
+	if v, err = valueFromFloat64s(xdv.d.Dtype(), xdv.d.Shape(), dx); err != nil {
+		return
+	}
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(v))
+	if _, err = add.UnsafeDo(xdv.d, v); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// extremumDo reduces v along the given axes with reduce, which is either (*tensor.Dense).Max or (*tensor.Dense).Min.
+// The axes are reduced from the last to the first, so that reducing an axis does not shift the axes that are left to reduce.
+func extremumDo(op Op, v Value, along axes, reduce func(*tensor.Dense, ...int) (*tensor.Dense, error)) (retVal Value, err error) {
+	t, ok := v.(*tensor.Dense)
+	if !ok {
+		return nil, errors.Errorf(nyiFail, fmt.Sprintf("%v.Do()", op), v)
+	}
+
+	ret := t
+	if len(along) == t.Dims() {
+		if ret, err = reduce(t); err != nil {
+			return nil, errors.Wrapf(err, doFail, op)
+		}
+	} else {
+		desc := make([]int, len(along))
+		copy(desc, along)
+		sort.Sort(sort.Reverse(sort.IntSlice(desc)))
+		for _, a := range desc {
+			if ret, err = reduce(ret, a); err != nil {
+				return nil, errors.Wrapf(err, doFail, op)
+			}
+		}
+	}
+
+	if ret.IsScalar() {
+		retVal, _ = anyToScalar(ret.ScalarValue())
+		return
+	}
+	return ret, nil
+}
+
+func extremumDiff(x, y, dy []float64, groups []int) []float64 {
+	retVal := make([]float64, len(x))
+	for i, v := range x {
+		if g := groups[i]; v == y[g] {
+			retVal[i] = dy[g]
+		}
+	}
+	return retVal
+}
+
+// reductionDiffOp computes the gradient of a floatReducer. It takes the input, the output and the gradient of the output as its inputs.
+type reductionDiffOp struct {
+	fwd floatReducer
+}
+
+func (op reductionDiffOp) Arity() int { return 3 }
+
+// reductionDiffOp has this type:
+//		op :: Tensor-d a → Tensor-(d-k) a → Tensor-(d-k) a → Tensor-d a
+// where k is the number of axes reduced along.
+func (op reductionDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	d := op.fwd.inputDims()
+	t := newTensorType(d, a)
+	r := reducedType(d, op.fwd.reducedAlong(), a)
+	return hm.NewFnType(t, r, r, t)
+}
+
+func (op reductionDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	return s.Clone(), nil
+}
+
+func (op reductionDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx []float64
+	if dx, err = reductionDiff(op.fwd, inputs[0], inputs[1], inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), inputs[0].Shape(), dx)
+}
+
+func (op reductionDiffOp) ReturnsPtr() bool     { return false }
+func (op reductionDiffOp) CallsExtern() bool    { return false }
+func (op reductionDiffOp) OverwritesInput() int { return -1 }
+
+func (op reductionDiffOp) WriteHash(h hash.Hash) {
+	op.fwd.WriteHash(h)
+	fmt.Fprintf(h, "Diff")
+}
+
+func (op reductionDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op reductionDiffOp) String() string { return fmt.Sprintf("∂%v", op.fwd) }
+
+func (op reductionDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false, false} }
+
+func (op reductionDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op reductionDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
 /* ARGMAX OP */
-// type argmaxOp struct {
-// 	along int // axis
-// }
 
-// func (op argmaxOp) Type() hm.Type {
-// 	a := hm.TypeVariable('a')
+// argmaxOp finds the indices of the max values along an axis. If min is true, the indices of the min values are found instead.
+// argmaxOp is not differentiable.
+type argmaxOp struct {
+	along int // axis
+	d     int
+	min   bool
+}
+
+func (op argmaxOp) Arity() int { return 1 }
+
+// argmaxOp is a function with this type:
+//		argmaxOp :: (Ord a) ⇒ Tensor d a → Tensor d-1 Int
+// A vector is reduced to an Int.
+func (op argmaxOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	t := newTensorType(op.d, a)
+	if op.d == 1 {
+		return hm.NewFnType(t, Int)
+	}
+	return hm.NewFnType(t, newTensorType(op.d-1, Int))
+}
+
+func (op argmaxOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	if op.along >= s.Dims() {
+		return nil, errors.Errorf("Axis %d is greater or equal to the length of the shape %v", op.along, s)
+	}
+
+	retVal := make(tensor.Shape, 0, len(s)-1)
+	retVal = append(retVal, s[:op.along]...)
+	return append(retVal, s[op.along+1:]...), nil
+}
+
+func (op argmaxOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(tensor.Tensor)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, op, inputs[0])
+	}
+
+	var ret tensor.Tensor
+	if op.min {
+		ret, err = tensor.Argmin(t, op.along)
+	} else {
+		ret, err = tensor.Argmax(t, op.along)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	if ret.IsScalar() {
+		retVal, _ = anyToScalar(ret.Data())
+		return
+	}
+	return ret, nil
+}
+
+func (op argmaxOp) ReturnsPtr() bool     { return false }
+func (op argmaxOp) CallsExtern() bool    { return false }
+func (op argmaxOp) OverwritesInput() int { return -1 }
 
-// }
+func (op argmaxOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "%v%d", op, op.d)
+}
+
+func (op argmaxOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op argmaxOp) String() string {
+	if op.min {
+		return fmt.Sprintf("ArgminAlong%d", op.along)
+	}
+	return fmt.Sprintf("ArgmaxAlong%d", op.along)
+}
+
+func (op argmaxOp) isUnary() bool { return true }
+
+func (op argmaxOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+func (op argmaxOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+// DoDiff is a no-op, as there is nothing to backpropagate through the indices.
+func (op argmaxOp) DoDiff(inputs Nodes, output *Node) error { return nil }
 
 /* SUM OP */
 
@@ -142,7 +631,8 @@ func (op sumOp) Type() hm.Type {
 	a := hm.TypeVariable('a')
 	t := newTensorType(op.d, a)
 
-	if op.inputShape.IsVector() {
+	// row and column vectors that are summed along one axis are still vectors
+	if op.inputShape.Dims() == 1 {
 		return hm.NewFnType(t, a)
 	}
 
@@ -190,6 +680,9 @@ func (op sumOp) InferShape(inputs ...DimSizer) (shape tensor.Shape, err error) {
 			shape = shape[:1]
 		case shape.IsRowVec():
 			shape = shape[1:]
+		case len(shape) > 2:
+			// the summed axes are removed, as the type of the op says
+			return reducedShape(in, op.along)
 		}
 
 	}
@@ -333,3 +826,228 @@ func (op sumOp) Hashcode() uint32 {
 
 func (op sumOp) String() string { return fmt.Sprintf("Σ%v", op.along) }
 func (op sumOp) isUnary() bool  { return true }
+
+/* CUMULATIVE OPS */
+
+// cumOp does a cumulative sum along an axis, or a cumulative product if prod is true. See tensor.Cumsum for the flags.
+// Unlike the reductions, the result has the same shape as the input.
+type cumOp struct {
+	along     int // axis
+	d         int
+	exclusive bool
+	reverse   bool
+	prod      bool
+}
+
+func (op cumOp) Arity() int { return 1 }
+
+// cumOp is a function with this type:
+//		cumOp :: (Num a) ⇒ Tensor d a → Tensor d a
+func (op cumOp) Type() hm.Type {
+	t := newTensorType(op.d, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t)
+}
+
+func (op cumOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	if op.along >= s.Dims() {
+		return nil, errors.Errorf("Axis %d is greater or equal to the length of the shape %v", op.along, s)
+	}
+	return s.Clone(), nil
+}
+
+func (op cumOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(tensor.Tensor)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, op, inputs[0])
+	}
+
+	if op.prod {
+		retVal, err = tensor.Cumprod(t, op.along, op.exclusive, op.reverse)
+	} else {
+		retVal, err = tensor.Cumsum(t, op.along, op.exclusive, op.reverse)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return
+}
+
+func (op cumOp) ReturnsPtr() bool     { return false }
+func (op cumOp) CallsExtern() bool    { return false }
+func (op cumOp) OverwritesInput() int { return -1 }
+
+func (op cumOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "%v%d", op, op.d)
+}
+
+func (op cumOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op cumOp) String() string {
+	name := "Cumsum"
+	if op.prod {
+		name = "Cumprod"
+	}
+	return fmt.Sprintf("%s(along=%d, exclusive=%t, reverse=%t)", name, op.along, op.exclusive, op.reverse)
+}
+
+func (op cumOp) isUnary() bool { return true }
+
+func (op cumOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+// SymDiff returns the gradient of a cumulative sum, which is the cumulative sum of the gradient in the other direction.
+// The gradient of a cumulative product is computed by a cumprodDiffOp.
+func (op cumOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if op.prod {
+		dx, err = applyOp(cumprodDiffOp{op}, inputs[0], grad)
+	} else {
+		back := op
+		back.reverse = !op.reverse
+		dx, err = applyOp(back, grad)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op cumOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if op.prod {
+		d, err = (cumprodDiffOp{op}).Do(xdv.Value, ydv.d)
+	} else {
+		back := op
+		back.reverse = !op.reverse
+		d, err = back.Do(ydv.d)
+	}
+	if err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if _, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// cumprodDiffOp is the gradient of a cumulative product. It takes the input and the gradient of the output.
+//
+// For an inclusive product y[i] = x[0]·…·x[i] along the axis, the gradient of x[j] is p[j]·s[j], where p[j] is the product of the
+// values before j, and s[j] = g[j] + x[j+1]·s[j+1] is the sum of the gradients after j, weighted by the products of the values between them.
+// Unlike dividing y by x, this works when x has zeros. The exclusive case is the same with s[j] = g[j+1] + x[j+1]·s[j+1].
+type cumprodDiffOp struct {
+	cumOp
+}
+
+func (op cumprodDiffOp) Arity() int { return 2 }
+
+// cumprodDiffOp is a function with this type:
+//		cumprodDiffOp :: (Num a) ⇒ Tensor d a → Tensor d a → Tensor d a
+func (op cumprodDiffOp) Type() hm.Type {
+	t := newTensorType(op.d, hm.TypeVariable('a'))
+	return hm.NewFnType(t, t, t)
+}
+
+func (op cumprodDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.cumOp.InferShape(inputs[0])
+}
+
+func (op cumprodDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var xs [][]float64
+	if xs, err = float64sOfValues(inputs); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	x, g := xs[0], xs[1]
+
+	shape := inputs[0].Shape()
+	outer, n, inner := axisLayout(shape, op.along)
+	res := make([]float64, len(x))
+	at := func(o, i, in int) int {
+		if op.reverse {
+			i = n - 1 - i
+		}
+		return (o*n+i)*inner + in
+	}
+	for o := 0; o < outer; o++ {
+		for in := 0; in < inner; in++ {
+			var s float64
+			for j := n - 1; j >= 0; j-- {
+				if j < n-1 {
+					next := at(o, j+1, in)
+					if op.exclusive {
+						s = g[next] + x[next]*s
+					} else {
+						s *= x[next]
+					}
+				}
+				if !op.exclusive {
+					s += g[at(o, j, in)]
+				}
+				res[at(o, j, in)] = s
+			}
+
+			p := 1.0
+			for j := 0; j < n; j++ {
+				res[at(o, j, in)] *= p
+				p *= x[at(o, j, in)]
+			}
+		}
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), shape, res)
+}
+
+func (op cumprodDiffOp) WriteHash(h hash.Hash) {
+	op.cumOp.WriteHash(h)
+	h.Write([]byte("Diff"))
+}
+
+func (op cumprodDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op cumprodDiffOp) String() string { return "∂" + op.cumOp.String() }
+
+func (op cumprodDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false} }
+
+func (op cumprodDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op cumprodDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
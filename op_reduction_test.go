@@ -1,10 +1,12 @@
 package gorgonia
 
 import (
+	"fmt"
 	"io/ioutil"
 	"runtime"
 	"testing"
 
+	"github.com/chewxy/gorgonia/tensor"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -196,3 +198,304 @@ func TestSumOpDiff(t *testing.T) {
 	assert.True(ValueEq(yG, bG))
 	assert.True(ValueEq(z.Value(), c.Value()))
 }
+
+// TestSum_ColumnVector checks that summing a column vector along its columns returns a vector, not a scalar
+func TestSum_ColumnVector(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(3, 1), WithName("x"), WithValue(tensor.New(tensor.WithShape(3, 1), tensor.WithBacking([]float64{1, 2, 3}))))
+		y := Must(Sum(x, 1))
+		assert.Equal(tensor.Shape{3}, y.Shape())
+		cost := Must(Sum(y))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal([]float64{1, 2, 3}, extractF64s(y.Value()))
+		dx, _ := x.Grad()
+		assert.Equal(tensor.Shape{3, 1}, dx.Shape())
+		assert.Equal([]float64{1, 1, 1}, extractF64s(dx))
+	}
+}
+
+func TestArgmaxArgmin(t *testing.T) {
+	assert := assert.New(t)
+
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float64{1, 5, 3, 7, 0, 2}), tensor.WithShape(2, 3))))
+		v := NewVector(g, Float32, WithShape(3), WithName("v"), WithValue(tensor.New(tensor.WithBacking([]float32{1, 5, 3}), tensor.WithShape(3))))
+		argmax := Must(Argmax(x, 1))
+		argmin := Must(Argmin(x, 0))
+		vArgmax := Must(Argmax(v, 0))
+		assert.Equal(tensor.Shape{2}, argmax.Shape())
+		assert.Equal(tensor.Shape{3}, argmin.Shape())
+		assert.True(vArgmax.IsScalar())
+		assert.Equal([]bool{false}, argmax.diffWRT())
+
+		// the indices live alongside a differentiable cost
+		cost := Must(Sum(x))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap)
+		} else {
+			// the lisp machine only backpropagates from scalar roots
+			m = NewLispMachine(g, ExecuteFwdOnly())
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		assert.Equal([]int{1, 0}, argmax.Value().Data())
+		assert.Equal([]int{0, 1, 1}, argmin.Value().Data())
+		assert.Equal(1, vArgmax.Value().Data())
+		assert.Equal(Int, argmax.Value().Dtype())
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"))
+	if _, err := Argmax(x, 2); err == nil {
+		t.Error("Expected an error for an axis out of bounds")
+	}
+}
+
+func TestMinMaxProd(t *testing.T) {
+	assert := assert.New(t)
+	xBack := []float64{
+		2, 3, 4,
+		0, 2, 5,
+		0, 0, 5,
+	}
+
+	reductions := []struct {
+		name        string
+		fn          func(a *Node, along ...int) (*Node, error)
+		along       []int
+		correct     []float64
+		correctGrad []float64
+	}{
+		{"Min", Min, []int{1}, []float64{2, 0, 0}, []float64{1, 0, 0, 1, 0, 0, 1, 1, 0}},
+		{"Max", Max, []int{1}, []float64{4, 5, 5}, []float64{0, 0, 1, 0, 0, 1, 0, 0, 1}},
+		{"Prod", Prod, []int{1}, []float64{24, 0, 0}, []float64{12, 8, 6, 10, 0, 0, 0, 0, 0}},
+		{"Min (all)", Min, nil, []float64{0}, []float64{0, 0, 0, 1, 0, 0, 1, 1, 0}},
+		{"Prod (along 0)", Prod, []int{0}, []float64{0, 0, 100}, []float64{0, 0, 25, 0, 0, 20, 0, 6, 20}},
+	}
+
+	for _, r := range reductions {
+		var grads [2]Value
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			x := NewMatrix(g, Float64, WithShape(3, 3), WithName("x"), WithValue(tensor.New(tensor.WithBacking(xBack), tensor.WithShape(3, 3))))
+			y := Must(r.fn(x, r.along...))
+			cost := Must(Sum(y))
+
+			var m VM
+			if i == 0 {
+				if _, err := Grad(cost, x); err != nil {
+					t.Fatalf("%v: %+v", r.name, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", r.name, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err := m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", r.name, err)
+			}
+
+			if len(r.along) == 0 {
+				assert.True(y.IsScalar(), r.name)
+				assert.Equal(r.correct[0], extractF64(y.Value()), r.name)
+			} else {
+				assert.Equal(r.correct, extractF64s(y.Value()), r.name)
+			}
+
+			var err error
+			if grads[i], err = x.Grad(); err != nil {
+				t.Fatalf("%v: %+v", r.name, err)
+			}
+			assert.Equal(r.correctGrad, extractF64s(grads[i]), r.name)
+		}
+	}
+
+	g := NewGraph()
+	x := NewTensor(g, Float32, 3, WithShape(2, 3, 4), WithName("x"))
+	assert.Equal(tensor.Shape{3}, Must(Prod(x, 0, 2)).Shape())
+	assert.Equal(tensor.Shape{2, 4}, Must(Min(x, 1)).Shape())
+	if _, err := Min(x, 3); err == nil {
+		t.Error("Expected an error for an axis out of bounds")
+	}
+}
+
+func TestMinMaxProd_Dtypes(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGraph()
+	back := tensor.New(tensor.WithBacking([]int{
+		3, 1, 4, 1,
+		5, 9, 2, 6,
+
+		5, 3, 5, 8,
+		9, 7, 9, 3,
+	}), tensor.WithShape(2, 2, 4))
+	x := NewTensor(g, Int, 3, WithShape(2, 2, 4), WithName("x"), WithValue(back))
+	max := Must(Max(x, 2))
+	min := Must(Min(x, 0, 2))
+	all := Must(Max(x))
+	prod := Must(Prod(x, 1))
+	allProd := Must(Prod(x))
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]int{4, 9, 8, 9}, max.Value().Data())
+	assert.Equal([]int{1, 2}, min.Value().Data())
+	assert.Equal(9, all.Value().Data())
+	assert.Equal([]int{15, 9, 8, 6, 45, 21, 45, 24}, prod.Value().Data())
+	assert.Equal(3*1*4*1*5*9*2*6*5*3*5*8*9*7*9*3, allProd.Value().Data())
+}
+
+// naiveCum is the reference for Cumsum and Cumprod on a (rows, cols) matrix
+func naiveCum(x []float64, rows, cols, axis int, exclusive, reverse, prod bool) []float64 {
+	retVal := make([]float64, len(x))
+	n, lanes := rows, cols
+	if axis == 1 {
+		n, lanes = cols, rows
+	}
+	for l := 0; l < lanes; l++ {
+		acc := 0.0
+		if prod {
+			acc = 1
+		}
+		for k := 0; k < n; k++ {
+			i := k
+			if reverse {
+				i = n - 1 - k
+			}
+			at := i*cols + l
+			if axis == 1 {
+				at = l*cols + i
+			}
+			if exclusive {
+				retVal[at] = acc
+			}
+			if prod {
+				acc *= x[at]
+			} else {
+				acc += x[at]
+			}
+			if !exclusive {
+				retVal[at] = acc
+			}
+		}
+	}
+	return retVal
+}
+
+func TestCumsumCumprod(t *testing.T) {
+	assert := assert.New(t)
+	xBack := []float64{1, 2, 3, 0, 2, -1}
+	c := []float64{1, 2, 3, 4, 5, 6}
+
+	for _, prod := range []bool{false, true} {
+		for _, axis := range []int{0, 1} {
+			for _, exclusive := range []bool{false, true} {
+				for _, reverse := range []bool{false, true} {
+					name := fmt.Sprintf("prod %t, axis %d, exclusive %t, reverse %t", prod, axis, exclusive, reverse)
+					correct := naiveCum(xBack, 2, 3, axis, exclusive, reverse, prod)
+
+					// the cost is Σ y ⊙ c, which is linear in each value of x, so central differences give the exact gradients
+					correctGrad := make([]float64, len(xBack))
+					for i := range xBack {
+						xs := append([]float64(nil), xBack...)
+						var fp, fm float64
+						xs[i] = xBack[i] + 1
+						for j, v := range naiveCum(xs, 2, 3, axis, exclusive, reverse, prod) {
+							fp += v * c[j]
+						}
+						xs[i] = xBack[i] - 1
+						for j, v := range naiveCum(xs, 2, 3, axis, exclusive, reverse, prod) {
+							fm += v * c[j]
+						}
+						correctGrad[i] = (fp - fm) / 2
+					}
+
+					for i := 0; i < 2; i++ {
+						g := NewGraph()
+						x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithBacking(append([]float64(nil), xBack...)), tensor.WithShape(2, 3))))
+						var y *Node
+						if prod {
+							y = Must(Cumprod(x, axis, exclusive, reverse))
+						} else {
+							y = Must(Cumsum(x, axis, exclusive, reverse))
+						}
+						assert.Equal(tensor.Shape{2, 3}, y.Shape(), name)
+						cost := Must(Sum(Must(HadamardProd(y, NewConstant(tensor.New(tensor.WithBacking(c), tensor.WithShape(2, 3)))))))
+
+						var m VM
+						if i == 0 {
+							if _, err := Grad(cost, x); err != nil {
+								t.Fatalf("%v: %+v", name, err)
+							}
+							prog, locMap, err := Compile(g)
+							if err != nil {
+								t.Fatalf("%v: %+v", name, err)
+							}
+							m = NewTapeMachine(prog, locMap, BindDualValues())
+						} else {
+							m = NewLispMachine(g)
+						}
+						if err := m.RunAll(); err != nil {
+							t.Fatalf("%v: %+v", name, err)
+						}
+
+						// the tape machine may reuse the register of y when computing the gradients
+						if i == 1 {
+							assert.Equal(correct, extractF64s(y.Value()), name)
+						}
+						gx, err := x.Grad()
+						if err != nil {
+							t.Fatalf("%v: %+v", name, err)
+						}
+						assert.InDeltaSlice(correctGrad, extractF64s(gx), 1e-10, name)
+					}
+				}
+			}
+		}
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"))
+	s := NewScalar(g, Float64, WithName("s"))
+	if _, err := Cumsum(x, 2, false, false); err == nil {
+		t.Error("Expected an error for an axis out of bounds")
+	}
+	if _, err := Cumprod(s, 0, false, false); err == nil {
+		t.Error("Expected an error for a scalar")
+	}
+}
@@ -0,0 +1,594 @@
+package gorgonia
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/chewxy/hm"
+	"github.com/gonum/blas"
+	"github.com/pkg/errors"
+)
+
+/*
+	This file contains the fused recurrent cell Ops. Each cell does its forwards pass in a single kernel,
+	and caches the activations of the gates, so that the backwards pass only has to do the matrix multiplications
+	required for the gradient that is asked for.
+
+	Computations are done in float64, regardless of the Dtype of the inputs.
+
+	See also: LSTMCell() and GRUCell() in nn.go
+*/
+
+// gemm64 performs c = op(a) × op(b) + beta × c, where op(a) is a m×k matrix, op(b) is a k×n matrix, and c is a m×n matrix.
+// All matrices are assumed to be contiguous and row major.
+func gemm64(transA, transB bool, m, n, k int, a, b []float64, beta float64, c []float64) {
+	tA, tB := blas.NoTrans, blas.NoTrans
+	lda, ldb := k, n
+	if transA {
+		tA, lda = blas.Trans, m
+	}
+	if transB {
+		tB, ldb = blas.Trans, k
+	}
+	whichblas.Dgemm(tA, tB, m, n, k, 1, a, lda, b, ldb, beta, c, n)
+}
+
+func sigmoid64(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
+
+// rnnCellSizes returns the batch size, input size and hidden size, given the shapes of the input and the hidden state.
+// Inputs may either be vectors (a batch of 1) or matrices of (batch, size).
+func rnnCellSizes(x, h tensor.Shape) (batch, inputSize, hiddenSize int) {
+	if x.Dims() == 1 {
+		return 1, x[0], h[0]
+	}
+	return x[0], x[1], h[1]
+}
+
+// checkRNNCellShapes checks the shapes of the inputs of a recurrent cell with the given number of gates.
+// The inputs are expected to be the input, the states (hidden, and optionally the cell), the input weights, the hidden weights and the bias.
+func checkRNNCellShapes(name string, gates int, inputs []DimSizer) (x, h tensor.Shape, err error) {
+	shapes := make([]tensor.Shape, len(inputs))
+	for i, in := range inputs {
+		var ok bool
+		if shapes[i], ok = in.(tensor.Shape); !ok {
+			return nil, nil, errors.Errorf("%s: expected a shape. Got %T instead", name, in)
+		}
+	}
+	x, h = shapes[0], shapes[1]
+	states := shapes[1 : len(shapes)-3]
+	wx, wh, b := shapes[len(shapes)-3], shapes[len(shapes)-2], shapes[len(shapes)-1]
+
+	if x.Dims() != h.Dims() || x.Dims() < 1 || x.Dims() > 2 {
+		return nil, nil, errors.Errorf("%s: the input and hidden states should both be vectors or both be (batch, size) matrices. Got %v and %v", name, x, h)
+	}
+	for _, s := range states {
+		if !s.Eq(h) {
+			return nil, nil, errors.Errorf("%s: the states should have the same shape. Got %v and %v", name, h, s)
+		}
+	}
+	batch, inputSize, hiddenSize := rnnCellSizes(x, h)
+	if x.Dims() == 2 && h[0] != batch {
+		return nil, nil, errors.Errorf("%s: the input and hidden states have different batch sizes. Got %v and %v", name, x, h)
+	}
+	if wx.Dims() != 2 || wx[0] != gates*hiddenSize || wx[1] != inputSize {
+		return nil, nil, errors.Errorf("%s: expected the input weights to have a shape of (%d, %d). Got %v instead", name, gates*hiddenSize, inputSize, wx)
+	}
+	if wh.Dims() != 2 || wh[0] != gates*hiddenSize || wh[1] != hiddenSize {
+		return nil, nil, errors.Errorf("%s: expected the hidden weights to have a shape of (%d, %d). Got %v instead", name, gates*hiddenSize, hiddenSize, wh)
+	}
+	if b.TotalSize() != gates*hiddenSize {
+		return nil, nil, errors.Errorf("%s: expected the bias to have %d elements. Got a shape of %v instead", name, gates*hiddenSize, b)
+	}
+	return x, h, nil
+}
+
+// rnnCellTypes returns the types of the inputs of a recurrent cell: the input, the states, the input weights, the hidden weights and the bias
+func rnnCellTypes(dims, states int) hm.Types {
+	a := hm.TypeVariable('a')
+	t := newTensorType(dims, a)
+	retVal := hm.Types{t}
+	for i := 0; i < states; i++ {
+		retVal = append(retVal, t)
+	}
+	return append(retVal, newTensorType(2, a), newTensorType(2, a), newTensorType(1, a))
+}
+
+// addDiffs accumulates the gradients into the derivatives of the dual values bound to the inputs.
+func addDiffs(inputs Nodes, grads [][]float64) (err error) {
+	for i, d := range grads {
+		if d == nil {
+			continue
+		}
+		dv := inputs[i].boundTo.(*dualValue)
+		var v Value
+		if v, err = valueFromFloat64s(dv.d.Dtype(), dv.d.Shape(), d); err != nil {
+			return
+		}
+		add := newEBOByType(addOpType, TypeOf(dv.d), TypeOf(v))
+		if _, err = add.UnsafeDo(dv.d, v); err != nil {
+			return errors.Wrapf(err, unsafeDoFail, add)
+		}
+	}
+	return nil
+}
+
+// lstmOp is a fused LSTM cell. It takes the input, the previous hidden state, the previous cell state,
+// the stacked input weights, the stacked hidden weights and the stacked bias as inputs.
+// The weights and biases are stacked in the order of the input gate, the forget gate, the output gate and the cell write.
+//
+// The output is the new hidden state and the new cell state stacked on a new first axis.
+type lstmOp struct {
+	dims int // dims of the input and states
+
+	// cached from the last forwards pass
+	acts  []float64 // activations of the gates: (batch, 4×hidden)
+	cell  []float64 // new cell state: (batch, hidden)
+	tanhC []float64 // tanh of the new cell state: (batch, hidden)
+}
+
+func newLSTMOp(dims int) *lstmOp { return &lstmOp{dims: dims} }
+
+func (op *lstmOp) Arity() int { return 6 }
+
+// lstmOp has this type:
+//		op :: Tensor-d a → Tensor-d a → Tensor-d a → Matrix a → Matrix a → Vector a → Tensor-(d+1) a
+func (op *lstmOp) Type() hm.Type { return hm.NewFnType(op.types()...) }
+
+// types returns the types of the inputs, followed by the type of the output
+func (op *lstmOp) types() hm.Types {
+	ts := rnnCellTypes(op.dims, 2)
+	return append(ts, newTensorType(op.dims+1, hm.TypeVariable('a')))
+}
+
+func (op *lstmOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	_, h, err := checkRNNCellShapes("LSTMCell", 4, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return append(tensor.Shape{2}, h...), nil
+}
+
+// forward computes the gates and the new states, and caches them
+func (op *lstmOp) forward(x, h, c, wx, wh, b []float64, batch, inputSize, hiddenSize int) {
+	gates := 4 * hiddenSize
+	z := make([]float64, batch*gates)
+	for i := 0; i < batch; i++ {
+		copy(z[i*gates:], b)
+	}
+	gemm64(false, true, batch, gates, inputSize, x, wx, 1, z)
+	gemm64(false, true, batch, gates, hiddenSize, h, wh, 1, z)
+
+	op.acts = z
+	op.cell = make([]float64, batch*hiddenSize)
+	op.tanhC = make([]float64, batch*hiddenSize)
+	for bt := 0; bt < batch; bt++ {
+		row := z[bt*gates : (bt+1)*gates]
+		for j := 0; j < hiddenSize; j++ {
+			row[j] = sigmoid64(row[j])                           // input gate
+			row[hiddenSize+j] = sigmoid64(row[hiddenSize+j])     // forget gate
+			row[2*hiddenSize+j] = sigmoid64(row[2*hiddenSize+j]) // output gate
+			row[3*hiddenSize+j] = math.Tanh(row[3*hiddenSize+j]) // cell write
+
+			k := bt*hiddenSize + j
+			op.cell[k] = row[hiddenSize+j]*c[k] + row[j]*row[3*hiddenSize+j]
+			op.tanhC[k] = math.Tanh(op.cell[k])
+		}
+	}
+}
+
+func (op *lstmOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var vals [][]float64
+	if vals, err = float64sOfValues(inputs); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	hShape := inputs[1].Shape()
+	batch, inputSize, hiddenSize := rnnCellSizes(inputs[0].Shape(), hShape)
+	op.forward(vals[0], vals[1], vals[2], vals[3], vals[4], vals[5], batch, inputSize, hiddenSize)
+
+	out := make([]float64, 2*batch*hiddenSize)
+	for bt := 0; bt < batch; bt++ {
+		for j := 0; j < hiddenSize; j++ {
+			k := bt*hiddenSize + j
+			out[k] = op.acts[bt*4*hiddenSize+2*hiddenSize+j] * op.tanhC[k]
+		}
+	}
+	copy(out[batch*hiddenSize:], op.cell)
+	return valueFromFloat64s(inputs[0].Dtype(), append(tensor.Shape{2}, hShape...), out)
+}
+
+// backward computes the gradients of the inputs listed in wrt. The gradients of the other inputs are left as nil.
+func (op *lstmOp) backward(inputs []Value, grad Value, wrt ...int) (retVal [][]float64, err error) {
+	var vals [][]float64
+	if vals, err = float64sOfValues(inputs); err != nil {
+		return
+	}
+	var dy []float64
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+	x, h, c, wx, wh := vals[0], vals[1], vals[2], vals[3], vals[4]
+	batch, inputSize, hiddenSize := rnnCellSizes(inputs[0].Shape(), inputs[1].Shape())
+	gates := 4 * hiddenSize
+
+	// recompute the forwards pass if nothing has been cached for these inputs
+	if len(op.acts) != batch*gates {
+		op.forward(x, h, c, wx, wh, vals[5], batch, inputSize, hiddenSize)
+	}
+
+	dh, dcNext := dy[:batch*hiddenSize], dy[batch*hiddenSize:]
+	dz := make([]float64, batch*gates)
+	dc := make([]float64, batch*hiddenSize)
+	for bt := 0; bt < batch; bt++ {
+		act := op.acts[bt*gates : (bt+1)*gates]
+		d := dz[bt*gates : (bt+1)*gates]
+		for j := 0; j < hiddenSize; j++ {
+			k := bt*hiddenSize + j
+			i, f, o, g := act[j], act[hiddenSize+j], act[2*hiddenSize+j], act[3*hiddenSize+j]
+
+			dcTotal := dcNext[k] + dh[k]*o*(1-op.tanhC[k]*op.tanhC[k])
+			d[j] = dcTotal * g * i * (1 - i)
+			d[hiddenSize+j] = dcTotal * c[k] * f * (1 - f)
+			d[2*hiddenSize+j] = dh[k] * op.tanhC[k] * o * (1 - o)
+			d[3*hiddenSize+j] = dcTotal * i * (1 - g*g)
+			dc[k] = dcTotal * f
+		}
+	}
+
+	retVal = make([][]float64, 6)
+	for _, w := range wrt {
+		switch w {
+		case 0:
+			retVal[0] = make([]float64, batch*inputSize)
+			gemm64(false, false, batch, inputSize, gates, dz, wx, 0, retVal[0])
+		case 1:
+			retVal[1] = make([]float64, batch*hiddenSize)
+			gemm64(false, false, batch, hiddenSize, gates, dz, wh, 0, retVal[1])
+		case 2:
+			retVal[2] = dc
+		case 3:
+			retVal[3] = make([]float64, gates*inputSize)
+			gemm64(true, false, gates, inputSize, batch, dz, x, 0, retVal[3])
+		case 4:
+			retVal[4] = make([]float64, gates*hiddenSize)
+			gemm64(true, false, gates, hiddenSize, batch, dz, h, 0, retVal[4])
+		case 5:
+			retVal[5] = make([]float64, gates)
+			for bt := 0; bt < batch; bt++ {
+				for j, d := range dz[bt*gates : (bt+1)*gates] {
+					retVal[5][j] += d
+				}
+			}
+		}
+	}
+	return
+}
+
+func (op *lstmOp) ReturnsPtr() bool     { return false }
+func (op *lstmOp) CallsExtern() bool    { return false }
+func (op *lstmOp) OverwritesInput() int { return -1 }
+
+func (op *lstmOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "LSTMCell%d", op.dims) }
+
+func (op *lstmOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op *lstmOp) String() string { return "LSTMCell" }
+
+func (op *lstmOp) DiffWRT(inputs int) []bool { return []bool{true, true, true, true, true, true} }
+
+func (op *lstmOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	return rnnCellSymDiff(op, inputs, output, grad)
+}
+
+func (op *lstmOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	return rnnCellDoDiff(op, inputs, output)
+}
+
+// gruOp is a fused GRU cell. It takes the input, the previous hidden state, the stacked input weights,
+// the stacked hidden weights and the stacked bias as inputs. The weights and biases are stacked in the order
+// of the reset gate, the update gate and the candidate:
+//		r = σ(Wxr·x + Whr·h + br)
+//		z = σ(Wxz·x + Whz·h + bz)
+//		n = tanh(Wxn·x + bn + r ⊙ (Whn·h))
+//		h' = (1 - z) ⊙ n + z ⊙ h
+type gruOp struct {
+	dims int // dims of the input and hidden state
+
+	// cached from the last forwards pass
+	acts []float64 // activations of the gates: (batch, 3×hidden)
+	hn   []float64 // Whn·h: (batch, hidden)
+}
+
+func newGRUOp(dims int) *gruOp { return &gruOp{dims: dims} }
+
+func (op *gruOp) Arity() int { return 5 }
+
+// gruOp has this type:
+//		op :: Tensor-d a → Tensor-d a → Matrix a → Matrix a → Vector a → Tensor-d a
+func (op *gruOp) Type() hm.Type { return hm.NewFnType(op.types()...) }
+
+// types returns the types of the inputs, followed by the type of the output
+func (op *gruOp) types() hm.Types {
+	ts := rnnCellTypes(op.dims, 1)
+	return append(ts, ts[0])
+}
+
+func (op *gruOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	_, h, err := checkRNNCellShapes("GRUCell", 3, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return h.Clone(), nil
+}
+
+// forward computes the gates, and caches them
+func (op *gruOp) forward(x, h, wx, wh, b []float64, batch, inputSize, hiddenSize int) {
+	gates := 3 * hiddenSize
+	zx := make([]float64, batch*gates)
+	zh := make([]float64, batch*gates)
+	for i := 0; i < batch; i++ {
+		copy(zx[i*gates:], b)
+	}
+	gemm64(false, true, batch, gates, inputSize, x, wx, 1, zx)
+	gemm64(false, true, batch, gates, hiddenSize, h, wh, 0, zh)
+
+	op.acts = zx
+	op.hn = make([]float64, batch*hiddenSize)
+	for bt := 0; bt < batch; bt++ {
+		row := zx[bt*gates : (bt+1)*gates]
+		hrow := zh[bt*gates : (bt+1)*gates]
+		for j := 0; j < hiddenSize; j++ {
+			r := sigmoid64(row[j] + hrow[j])
+			row[j] = r
+			row[hiddenSize+j] = sigmoid64(row[hiddenSize+j] + hrow[hiddenSize+j])
+			row[2*hiddenSize+j] = math.Tanh(row[2*hiddenSize+j] + r*hrow[2*hiddenSize+j])
+			op.hn[bt*hiddenSize+j] = hrow[2*hiddenSize+j]
+		}
+	}
+}
+
+func (op *gruOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var vals [][]float64
+	if vals, err = float64sOfValues(inputs); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	hShape := inputs[1].Shape()
+	batch, inputSize, hiddenSize := rnnCellSizes(inputs[0].Shape(), hShape)
+	h := vals[1]
+	op.forward(vals[0], h, vals[2], vals[3], vals[4], batch, inputSize, hiddenSize)
+
+	out := make([]float64, batch*hiddenSize)
+	for bt := 0; bt < batch; bt++ {
+		act := op.acts[bt*3*hiddenSize : (bt+1)*3*hiddenSize]
+		for j := 0; j < hiddenSize; j++ {
+			k := bt*hiddenSize + j
+			z, n := act[hiddenSize+j], act[2*hiddenSize+j]
+			out[k] = (1-z)*n + z*h[k]
+		}
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), hShape, out)
+}
+
+// backward computes the gradients of the inputs listed in wrt. The gradients of the other inputs are left as nil.
+func (op *gruOp) backward(inputs []Value, grad Value, wrt ...int) (retVal [][]float64, err error) {
+	var vals [][]float64
+	if vals, err = float64sOfValues(inputs); err != nil {
+		return
+	}
+	var dy []float64
+	if dy, err = float64sOf(grad); err != nil {
+		return
+	}
+	x, h, wx, wh := vals[0], vals[1], vals[2], vals[3]
+	batch, inputSize, hiddenSize := rnnCellSizes(inputs[0].Shape(), inputs[1].Shape())
+	gates := 3 * hiddenSize
+
+	// recompute the forwards pass if nothing has been cached for these inputs
+	if len(op.acts) != batch*gates {
+		op.forward(x, h, wx, wh, vals[4], batch, inputSize, hiddenSize)
+	}
+
+	// dzx is the gradient wrt the input projections, dzh is the gradient wrt the hidden projections
+	dzx := make([]float64, batch*gates)
+	dzh := make([]float64, batch*gates)
+	dhDirect := make([]float64, batch*hiddenSize)
+	for bt := 0; bt < batch; bt++ {
+		act := op.acts[bt*gates : (bt+1)*gates]
+		dx := dzx[bt*gates : (bt+1)*gates]
+		dh := dzh[bt*gates : (bt+1)*gates]
+		for j := 0; j < hiddenSize; j++ {
+			k := bt*hiddenSize + j
+			r, z, n := act[j], act[hiddenSize+j], act[2*hiddenSize+j]
+
+			dn := dy[k] * (1 - z) * (1 - n*n)
+			dz := dy[k] * (h[k] - n) * z * (1 - z)
+			dr := dn * op.hn[k] * r * (1 - r)
+
+			dx[j], dx[hiddenSize+j], dx[2*hiddenSize+j] = dr, dz, dn
+			dh[j], dh[hiddenSize+j], dh[2*hiddenSize+j] = dr, dz, dn*r
+			dhDirect[k] = dy[k] * z
+		}
+	}
+
+	retVal = make([][]float64, 5)
+	for _, w := range wrt {
+		switch w {
+		case 0:
+			retVal[0] = make([]float64, batch*inputSize)
+			gemm64(false, false, batch, inputSize, gates, dzx, wx, 0, retVal[0])
+		case 1:
+			retVal[1] = dhDirect
+			gemm64(false, false, batch, hiddenSize, gates, dzh, wh, 1, retVal[1])
+		case 2:
+			retVal[2] = make([]float64, gates*inputSize)
+			gemm64(true, false, gates, inputSize, batch, dzx, x, 0, retVal[2])
+		case 3:
+			retVal[3] = make([]float64, gates*hiddenSize)
+			gemm64(true, false, gates, hiddenSize, batch, dzh, h, 0, retVal[3])
+		case 4:
+			retVal[4] = make([]float64, gates)
+			for bt := 0; bt < batch; bt++ {
+				for j, d := range dzx[bt*gates : (bt+1)*gates] {
+					retVal[4][j] += d
+				}
+			}
+		}
+	}
+	return
+}
+
+func (op *gruOp) ReturnsPtr() bool     { return false }
+func (op *gruOp) CallsExtern() bool    { return false }
+func (op *gruOp) OverwritesInput() int { return -1 }
+
+func (op *gruOp) WriteHash(h hash.Hash) { fmt.Fprintf(h, "GRUCell%d", op.dims) }
+
+func (op *gruOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op *gruOp) String() string { return "GRUCell" }
+
+func (op *gruOp) DiffWRT(inputs int) []bool { return []bool{true, true, true, true, true} }
+
+func (op *gruOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	return rnnCellSymDiff(op, inputs, output, grad)
+}
+
+func (op *gruOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	return rnnCellDoDiff(op, inputs, output)
+}
+
+// rnnCell is the common interface of the fused recurrent cell ops
+type rnnCell interface {
+	Op
+	types() hm.Types
+	backward(inputs []Value, grad Value, wrt ...int) ([][]float64, error)
+}
+
+func rnnCellSymDiff(op rnnCell, inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	// the output is passed in to ensure that the forwards pass has happened by the time the gradient is computed
+	children := make(Nodes, len(inputs), len(inputs)+2)
+	copy(children, inputs)
+	children = append(children, output, grad)
+
+	retVal = make(Nodes, len(inputs))
+	for i := range inputs {
+		if retVal[i], err = applyOp(rnnCellDiffOp{op, i}, children...); err != nil {
+			return nil, errors.Wrap(err, applyOpFail)
+		}
+		retVal[i].setGroup(gradClust)
+	}
+	return
+}
+
+func rnnCellDoDiff(op rnnCell, inputs Nodes, output *Node) (err error) {
+	vals := make([]Value, len(inputs))
+	wrt := make([]int, len(inputs))
+	for i, in := range inputs {
+		vals[i] = in.boundTo.(*dualValue).Value
+		wrt[i] = i
+	}
+	ydv := output.boundTo.(*dualValue)
+
+	var grads [][]float64
+	if grads, err = op.backward(vals, ydv.d, wrt...); err != nil {
+		return
+	}
+	return addDiffs(inputs, grads)
+}
+
+// rnnCellDiffOp computes the gradient of a recurrent cell op with regards to one of its inputs.
+// It takes the inputs of the cell, followed by the output and the gradient of the output as its inputs.
+type rnnCellDiffOp struct {
+	fwd rnnCell
+	wrt int
+}
+
+func (op rnnCellDiffOp) Arity() int { return op.fwd.Arity() + 2 }
+
+// rnnCellDiffOp has this type:
+//		op :: ... → b
+//
+// where ... are the input types of the cell, followed by the output type of the cell twice,
+// and b is the type of the input the gradient is taken with regards to.
+func (op rnnCellDiffOp) Type() hm.Type {
+	ts := op.fwd.types()
+	ts = append(ts, ts[len(ts)-1], ts[op.wrt])
+	return hm.NewFnType(ts...)
+}
+
+func (op rnnCellDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[op.wrt].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[op.wrt])
+	}
+	return s.Clone(), nil
+}
+
+func (op rnnCellDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	n := op.fwd.Arity()
+	var grads [][]float64
+	if grads, err = op.fwd.backward(inputs[:n], inputs[n+1], op.wrt); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[op.wrt].Dtype(), inputs[op.wrt].Shape(), grads[op.wrt])
+}
+
+func (op rnnCellDiffOp) ReturnsPtr() bool     { return false }
+func (op rnnCellDiffOp) CallsExtern() bool    { return false }
+func (op rnnCellDiffOp) OverwritesInput() int { return -1 }
+
+func (op rnnCellDiffOp) WriteHash(h hash.Hash) {
+	op.fwd.WriteHash(h)
+	fmt.Fprintf(h, "Diff%d", op.wrt)
+}
+
+func (op rnnCellDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op rnnCellDiffOp) String() string { return fmt.Sprintf("∂%v/∂%d", op.fwd, op.wrt) }
@@ -0,0 +1,173 @@
+package gorgonia
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkRNNCellGrad compares the analytic gradients of a cell with the numeric gradients of sum(w ⊙ cell(inputs))
+func checkRNNCellGrad(t *testing.T, op rnnCell, inputs []Value) {
+	y, err := op.Do(inputs...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	w := Gaussian64(0, 1, y.Shape()...)
+	loss := func() float64 {
+		y, _ := op.Do(inputs...)
+		var s float64
+		for i, v := range y.Data().([]float64) {
+			s += v * w[i]
+		}
+		return s
+	}
+
+	wrt := make([]int, len(inputs))
+	for i := range wrt {
+		wrt[i] = i
+	}
+	loss()
+	grads, err := op.backward(inputs, tensor.New(tensor.WithBacking(w), tensor.WithShape(y.Shape()...)), wrt...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	const h = 1e-6
+	for i, in := range inputs {
+		data := in.Data().([]float64)
+		for j := range data {
+			orig := data[j]
+			data[j] = orig + h
+			a := loss()
+			data[j] = orig - h
+			b := loss()
+			data[j] = orig
+
+			if numeric := (a - b) / (2 * h); math.Abs(numeric-grads[i][j]) > 1e-5 {
+				t.Errorf("%v: input %d, element %d. Numeric gradient %v. Got %v instead", op, i, j, numeric, grads[i][j])
+			}
+		}
+	}
+}
+
+func randomValue(shape ...int) Value {
+	return tensor.New(tensor.WithBacking(Gaussian64(0, 1, shape...)), tensor.WithShape(shape...))
+}
+
+func TestLSTMOp(t *testing.T) {
+	assert := assert.New(t)
+
+	// a single unit with no input, where all the gates are fully open
+	op := newLSTMOp(1)
+	inputs := []Value{
+		tensor.New(tensor.WithBacking([]float64{0}), tensor.WithShape(1)),
+		tensor.New(tensor.WithBacking([]float64{0}), tensor.WithShape(1)),
+		tensor.New(tensor.WithBacking([]float64{2}), tensor.WithShape(1)),
+		tensor.New(tensor.WithBacking([]float64{0, 0, 0, 0}), tensor.WithShape(4, 1)),
+		tensor.New(tensor.WithBacking([]float64{0, 0, 0, 0}), tensor.WithShape(4, 1)),
+		tensor.New(tensor.WithBacking([]float64{100, 100, 100, 100}), tensor.WithShape(4)),
+	}
+	y, err := op.Do(inputs...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.True(floatsEqual64([]float64{math.Tanh(3), 3}, extractF64s(y)), "Got %v", y)
+
+	checkRNNCellGrad(t, newLSTMOp(1), []Value{randomValue(3), randomValue(2), randomValue(2), randomValue(8, 3), randomValue(8, 2), randomValue(8)})
+	checkRNNCellGrad(t, newLSTMOp(2), []Value{randomValue(4, 3), randomValue(4, 2), randomValue(4, 2), randomValue(8, 3), randomValue(8, 2), randomValue(8)})
+}
+
+func TestGRUOp(t *testing.T) {
+	assert := assert.New(t)
+
+	// a single unit with no input, where the update gate is fully closed
+	op := newGRUOp(1)
+	inputs := []Value{
+		tensor.New(tensor.WithBacking([]float64{0}), tensor.WithShape(1)),
+		tensor.New(tensor.WithBacking([]float64{5}), tensor.WithShape(1)),
+		tensor.New(tensor.WithBacking([]float64{0, 0, 0}), tensor.WithShape(3, 1)),
+		tensor.New(tensor.WithBacking([]float64{0, 0, 0}), tensor.WithShape(3, 1)),
+		tensor.New(tensor.WithBacking([]float64{0, -100, 0.5}), tensor.WithShape(3)),
+	}
+	y, err := op.Do(inputs...)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.True(floatEquals64(math.Tanh(0.5), y.Data().(float64)), "Got %v", y)
+
+	checkRNNCellGrad(t, newGRUOp(1), []Value{randomValue(3), randomValue(2), randomValue(6, 3), randomValue(6, 2), randomValue(6)})
+	checkRNNCellGrad(t, newGRUOp(2), []Value{randomValue(4, 3), randomValue(4, 2), randomValue(6, 3), randomValue(6, 2), randomValue(6)})
+}
+
+func TestLSTMCellGRUCell(t *testing.T) {
+	assert := assert.New(t)
+
+	vals := map[string]Value{
+		"x":   randomValue(4, 3),
+		"h":   randomValue(4, 2),
+		"c":   randomValue(4, 2),
+		"lwx": randomValue(8, 3),
+		"lwh": randomValue(8, 2),
+		"lb":  randomValue(8),
+		"gwx": randomValue(6, 3),
+		"gwh": randomValue(6, 2),
+		"gb":  randomValue(6),
+	}
+
+	var grads [2]map[string]Value
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		nodes := make(map[string]*Node)
+		var params Nodes
+		for name, v := range vals {
+			nodes[name] = NodeFromAny(g, v, WithName(name))
+			params = append(params, nodes[name])
+		}
+
+		h, c, err := LSTMCell(nodes["x"], nodes["h"], nodes["c"], nodes["lwx"], nodes["lwh"], nodes["lb"])
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(tensor.Shape{4, 2}, h.Shape())
+		assert.Equal(tensor.Shape{4, 2}, c.Shape())
+
+		if _, err = GRUCell(nodes["x"], h, nodes["lwx"], nodes["lwh"], nodes["lb"]); err == nil {
+			t.Error("Expected an error when the weights have the wrong shape")
+		}
+		h2, err := GRUCell(nodes["x"], h, nodes["gwx"], nodes["gwh"], nodes["gb"])
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		cost := Must(Sum(Must(Add(Must(HadamardProd(h2, c)), h))))
+
+		var m VM
+		if i == 0 {
+			if _, err = Grad(cost, params...); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err = m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		grads[i] = make(map[string]Value)
+		for name, n := range nodes {
+			if grads[i][name], err = n.Grad(); err != nil {
+				t.Fatalf("%v: %+v", name, err)
+			}
+		}
+	}
+
+	for name, v := range grads[0] {
+		assert.True(floatsEqual64(extractF64s(v), extractF64s(grads[1][name])), "%v: %v vs %v", name, v, grads[1][name])
+	}
+}
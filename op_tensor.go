@@ -174,6 +174,7 @@ func (op sizeOp) DimSize(d int) (int, error) {
 
 type repeatOp struct {
 	along axes
+	reps  []int // the number of repeats along each axis, if they are known when the op is created
 
 	inputShape tensor.Shape
 	d          int
@@ -183,8 +184,15 @@ type repeatOp struct {
 }
 
 func newRepeatOp(along axes, children Nodes) *repeatOp {
+	return newKnownRepeatOp(along, nil, children)
+}
+
+// newKnownRepeatOp creates a repeatOp whose number of repeats along each axis is already known, as opposed to one that repeats
+// to the sizes given by sizeOps. The repeats are still passed in as the children after the first.
+func newKnownRepeatOp(along axes, reps []int, children Nodes) *repeatOp {
 	retVal := &repeatOp{
 		along:    along,
+		reps:     reps,
 		children: len(children),
 		arg0Dim:  children[0].Dims(),
 	}
@@ -254,6 +262,9 @@ func (op repeatOp) InferShape(inputs ...DimSizer) (retVal tensor.Shape, err erro
 			knownRepeats[i] = r.val
 		}
 	}
+	if op.reps != nil {
+		copy(knownRepeats, op.reps)
+	}
 
 	if monotonic, incr := tensor.IsMonotonicInts(op.along); monotonic && incr && input.IsScalar() {
 		if input.IsScalar() {
@@ -287,6 +298,12 @@ func (op repeatOp) DiffWRT(i int) []bool {
 func (op repeatOp) SymDiff(inputs Nodes, output, gradNode *Node) (retVal Nodes, err error) {
 	var n *Node
 	if n, err = Sum(gradNode, op.along...); err == nil {
+		// the summed axes may be axes of size 1 of the input, which the sum removes
+		if !n.IsScalar() && !n.shape.Eq(inputs[0].shape) && n.shape.TotalSize() == inputs[0].shape.TotalSize() {
+			if n, err = Reshape(n, inputs[0].shape...); err != nil {
+				return nil, errors.Wrap(err, operationError)
+			}
+		}
 		n.setGroup(gradClust)
 	}
 	retVal = make(Nodes, len(inputs))
@@ -361,6 +378,13 @@ func (op repeatOp) DoDiff(inputs Nodes, output *Node) (err error) {
 		}
 	}
 
+	// the summed axes may be axes of size 1 of x, which the sum removes
+	if t, ok := d.(tensor.Tensor); ok && !t.Shape().Eq(xdv.Shape()) && t.Shape().TotalSize() == xdv.Shape().TotalSize() {
+		if err = t.Reshape(xdv.Shape()...); err != nil {
+			return errors.Wrapf(err, reshapeFail, xdv.Shape(), t.DataSize())
+		}
+	}
+
 	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
 	if d, err = add.UnsafeDo(xdv.d, d); err != nil {
 		return
@@ -481,6 +505,10 @@ func (op repeatOp) WriteHash(h hash.Hash) {
 	} else {
 		h.Write([]byte{0})
 	}
+
+	if op.reps != nil {
+		fmt.Fprintf(h, "%v", op.reps)
+	}
 }
 
 func (op repeatOp) Hashcode() uint32 {
@@ -976,6 +1004,130 @@ func (op transposeOp) String() string {
 	return buf.String()
 }
 
+// reshapeOp changes the shape of a value without changing its data. The result shares the backing data of the input
+// unless the input is a view that has to be materialized first.
+// A reshapeOp with an empty target shape returns a scalar, and the input may be a scalar if it is reshaped into a shape of size 1.
+type reshapeOp struct {
+	from, to tensor.Shape
+}
+
+func (op reshapeOp) Arity() int { return 1 }
+
+// reshapeOp has this type:
+//		op :: Tensor-m a → Tensor-n a
+// where m and n are the number of dimensions of the from and to shapes. A Tensor-0 a is an a.
+func (op reshapeOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	from, to := hm.Type(a), hm.Type(a)
+	if len(op.from) > 0 {
+		from = newTensorType(len(op.from), a)
+	}
+	if len(op.to) > 0 {
+		to = newTensorType(len(op.to), a)
+	}
+	return hm.NewFnType(from, to)
+}
+
+func (op reshapeOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	if len(op.to) == 0 {
+		return scalarShape, nil
+	}
+	return op.to.Clone(), nil
+}
+
+func (op reshapeOp) DiffWRT(i int) []bool { return []bool{true} }
+
+func (op reshapeOp) SymDiff(inputs Nodes, outputNode, gradNode *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(reshapeOp{from: op.to, to: op.from}, gradNode); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	return Nodes{dx}, nil
+}
+
+func (op reshapeOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	back := reshapeOp{from: op.to, to: op.from}
+	if d, err = back.Do(ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, back)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if d, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return xdv.SetDeriv(d)
+}
+
+func (op reshapeOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var t *tensor.Dense
+	switch v := inputs[0].(type) {
+	case *tensor.Dense:
+		switch {
+		case v.IsMaterializable():
+			t = v.Materialize().(*tensor.Dense)
+		case v.IsScalar():
+			t = tensor.New(tensor.Of(v.Dtype()), tensor.WithShape(1))
+			t.Set(0, v.ScalarValue())
+		default:
+			// share the backing data
+			t = tensor.New(tensor.Of(v.Dtype()), tensor.WithBacking(v.Data()), tensor.WithShape(v.Shape().Clone()...))
+		}
+	case Scalar:
+		t = tensor.New(tensor.Of(v.Dtype()), tensor.WithShape(1))
+		t.Set(0, v.Data())
+	default:
+		return nil, errors.Errorf(nyiTypeFail, "reshapeOp.Do", inputs[0])
+	}
+
+	if len(op.to) == 0 {
+		if t.Shape().TotalSize() > 1 {
+			return nil, errors.Errorf("Cannot reshape a value of shape %v into a scalar", t.Shape())
+		}
+		retVal, _ = anyToScalar(t.Get(0))
+		return
+	}
+	if err = t.Reshape(op.to...); err != nil {
+		return nil, errors.Wrapf(err, reshapeFail, op.to, t.DataSize())
+	}
+	return t, nil
+}
+
+func (op reshapeOp) ReturnsPtr() bool     { return true }
+func (op reshapeOp) CallsExtern() bool    { return false }
+func (op reshapeOp) OverwritesInput() int { return -1 }
+func (op reshapeOp) isView() bool         { return true }
+
+func (op reshapeOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "reshape%v→%v", op.from, op.to)
+}
+
+func (op reshapeOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op reshapeOp) String() string { return fmt.Sprintf("Reshape%v→%v", op.from, op.to) }
+
 type concatOp struct {
 	axis     int
 	d        int
@@ -1099,3 +1251,929 @@ func (op concatOp) DoDiff(inputs Nodes, output *Node) error {
 	}
 	return nil
 }
+
+// gatherOp takes the slices of x along an axis at the given Int indices, like Numpy's take.
+// The result has the shape of x, with the axis replaced by the shape of the indices.
+//
+// If along is true, the indices select the values along the axis for each position of the other axes instead, like Numpy's
+// take_along_axis. The indices then have the shape of x, except for the axis, and the result has the shape of the indices.
+type gatherOp struct {
+	axis     int
+	xShape   tensor.Shape // shape of x
+	idxShape tensor.Shape // shape of the indices, which is empty if the index is a scalar
+	along    bool
+}
+
+func (op gatherOp) Arity() int { return 2 }
+
+// gatherOp has this type:
+//		op :: Tensor-d a → Tensor-k Int → Tensor-(d+k-1) a
+// where a Tensor-0 a is an a.
+func (op gatherOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(shapedType(op.xShape, a), shapedType(op.idxShape, Int), shapedType(op.shape(), a))
+}
+
+// shape returns the shape of the result
+func (op gatherOp) shape() tensor.Shape {
+	if op.along {
+		return op.idxShape.Clone()
+	}
+	retVal := make(tensor.Shape, 0, len(op.xShape)+len(op.idxShape)-1)
+	retVal = append(retVal, op.xShape[:op.axis]...)
+	retVal = append(retVal, op.idxShape...)
+	return append(retVal, op.xShape[op.axis+1:]...)
+}
+
+func (op gatherOp) layout() (outer, n, inner int) { return axisLayout(op.xShape, op.axis) }
+
+// alongPos returns the position in x of the ith index, which is idx along the axis, when the indices are along the axis
+func (op gatherOp) alongPos(i, idx int) int {
+	_, n, inner := op.layout()
+	k := op.idxShape[op.axis]
+	return (i/(k*inner)*n+idx)*inner + i%inner
+}
+
+func (op gatherOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	for i, want := range []tensor.Shape{op.xShape, op.idxShape} {
+		s, ok := inputs[i].(tensor.Shape)
+		if !ok {
+			return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[i])
+		}
+		if !sameShape(s, want) && !(len(want) == 0 && s.IsScalar()) {
+			return nil, errors.Errorf("%v expects input %d to be of shape %v. Got %v instead", op, i, want, s)
+		}
+	}
+	if s := op.shape(); len(s) > 0 {
+		return s, nil
+	}
+	return scalarShape, nil
+}
+
+func (op gatherOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x []float64
+	var indices []int
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if indices, err = intsOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	outer, n, inner := op.layout()
+	if op.along {
+		res := make([]float64, len(indices))
+		for i, idx := range indices {
+			if idx < 0 || idx >= n {
+				return nil, errors.Errorf("Index %d is out of bounds for axis %d of shape %v", idx, op.axis, op.xShape)
+			}
+			res[i] = x[op.alongPos(i, idx)]
+		}
+		return valueFromFloat64s(inputs[0].Dtype(), op.shape(), res)
+	}
+
+	res := make([]float64, outer*len(indices)*inner)
+	for i, idx := range indices {
+		if idx < 0 || idx >= n {
+			return nil, errors.Errorf("Index %d is out of bounds for axis %d of shape %v", idx, op.axis, op.xShape)
+		}
+		for o := 0; o < outer; o++ {
+			copy(res[(o*len(indices)+i)*inner:], x[(o*n+idx)*inner:(o*n+idx+1)*inner])
+		}
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), op.shape(), res)
+}
+
+func (op gatherOp) ReturnsPtr() bool     { return false }
+func (op gatherOp) CallsExtern() bool    { return false }
+func (op gatherOp) OverwritesInput() int { return -1 }
+
+func (op gatherOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "gather%d%v%v%t", op.axis, op.xShape, op.idxShape, op.along)
+}
+
+func (op gatherOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op gatherOp) String() string {
+	if op.along {
+		return fmt.Sprintf("GatherAlong(axis=%d)", op.axis)
+	}
+	return fmt.Sprintf("Gather(axis=%d)", op.axis)
+}
+
+func (op gatherOp) DiffWRT(inputs int) []bool { return []bool{true, false} }
+
+func (op gatherOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(gatherDiffOp{op}, inputs[1], grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx, nil}, nil
+}
+
+func (op gatherOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = (gatherDiffOp{op}).Do(inputs[1].Value(), ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if _, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// scatterAdd adds the slices of updates into the slices of dst along the axis of op at the given indices.
+// Repeated indices are added to the same slice.
+func (op gatherOp) scatterAdd(dst, updates []float64, indices []int) error {
+	outer, n, inner := op.layout()
+	if op.along {
+		if len(updates) != len(indices) {
+			return errors.Errorf("Expected %d updates for the indices of shape %v along axis %d. Got %d instead", len(indices), op.idxShape, op.axis, len(updates))
+		}
+		for i, idx := range indices {
+			if idx < 0 || idx >= n {
+				return errors.Errorf("Index %d is out of bounds for axis %d of shape %v", idx, op.axis, op.xShape)
+			}
+			dst[op.alongPos(i, idx)] += updates[i]
+		}
+		return nil
+	}
+	if len(updates) != outer*len(indices)*inner {
+		return errors.Errorf("Expected %d updates for %d indices along axis %d of shape %v. Got %d instead", outer*len(indices)*inner, len(indices), op.axis, op.xShape, len(updates))
+	}
+	for i, idx := range indices {
+		if idx < 0 || idx >= n {
+			return errors.Errorf("Index %d is out of bounds for axis %d of shape %v", idx, op.axis, op.xShape)
+		}
+		for o := 0; o < outer; o++ {
+			d := dst[(o*n+idx)*inner : (o*n+idx+1)*inner]
+			u := updates[(o*len(indices)+i)*inner:]
+			for j := range d {
+				d[j] += u[j]
+			}
+		}
+	}
+	return nil
+}
+
+// gatherDiffOp is the gradient of a gatherOp. It scatters the gradient back into a zero value of the shape of x.
+type gatherDiffOp struct {
+	gatherOp
+}
+
+// gatherDiffOp has this type:
+//		op :: Tensor-k Int → Tensor-(d+k-1) a → Tensor-d a
+func (op gatherDiffOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	return hm.NewFnType(shapedType(op.idxShape, Int), shapedType(op.shape(), a), shapedType(op.xShape, a))
+}
+
+func (op gatherDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.xShape.Clone(), nil
+}
+
+func (op gatherDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var indices []int
+	var grad []float64
+	if indices, err = intsOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if grad, err = float64sOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	res := make([]float64, shapeSize(op.xShape))
+	if err = op.scatterAdd(res, grad, indices); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[1].Dtype(), op.xShape, res)
+}
+
+func (op gatherDiffOp) WriteHash(h hash.Hash) {
+	op.gatherOp.WriteHash(h)
+	h.Write([]byte("Diff"))
+}
+
+func (op gatherDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op gatherDiffOp) String() string { return fmt.Sprintf("∂%v", op.gatherOp) }
+
+func (op gatherDiffOp) DiffWRT(inputs int) []bool { return []bool{false, false} }
+
+func (op gatherDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op gatherDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+// scatterAddOp adds the slices of updates into a copy of the value along an axis at the given Int indices. It is the
+// inverse of a gatherOp with the same axis and shapes: updates has the shape of the result of the gather.
+type scatterAddOp struct {
+	gatherOp
+}
+
+func (op scatterAddOp) Arity() int { return 3 }
+
+// scatterAddOp has this type:
+//		op :: Tensor-d a → Tensor-k Int → Tensor-(d+k-1) a → Tensor-d a
+func (op scatterAddOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	x := shapedType(op.xShape, a)
+	return hm.NewFnType(x, shapedType(op.idxShape, Int), shapedType(op.shape(), a), x)
+}
+
+func (op scatterAddOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	if _, err := op.gatherOp.InferShape(inputs[:2]...); err != nil {
+		return nil, err
+	}
+	if s, ok := inputs[2].(tensor.Shape); !ok || !sameShape(s, op.shape()) {
+		return nil, errors.Errorf("%v expects the updates to be of shape %v. Got %v instead", op, op.shape(), inputs[2])
+	}
+	return op.xShape.Clone(), nil
+}
+
+func (op scatterAddOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var x, updates []float64
+	var indices []int
+	if x, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if indices, err = intsOf(inputs[1]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	if updates, err = float64sOf(inputs[2]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	res := make([]float64, len(x))
+	copy(res, x)
+	if err = op.scatterAdd(res, updates, indices); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), op.xShape, res)
+}
+
+func (op scatterAddOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "scatterAdd%d%v%v", op.axis, op.xShape, op.idxShape)
+}
+
+func (op scatterAddOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op scatterAddOp) String() string { return fmt.Sprintf("ScatterAdd(axis=%d)", op.axis) }
+
+func (op scatterAddOp) DiffWRT(inputs int) []bool { return []bool{true, false, true} }
+
+func (op scatterAddOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dupdates *Node
+	if dupdates, err = applyOp(op.gatherOp, grad, inputs[1]); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dupdates.setGroup(gradClust)
+	return Nodes{grad, nil, dupdates}, nil
+}
+
+func (op scatterAddOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	udv := inputs[2].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = op.gatherOp.Do(ydv.d, inputs[1].Value()); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(udv.d), TypeOf(d))
+	if d, err = add.UnsafeDo(udv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	if err = udv.SetDeriv(d); err != nil {
+		return
+	}
+
+	add = newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(ydv.d))
+	if _, err = add.UnsafeDo(xdv.d, ydv.d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// topKOp finds the k largest values along an axis with tensor.TopK, and returns their indices along the axis.
+// The values themselves are gathered with a gatherOp along the axis.
+type topKOp struct {
+	k, axis int
+	shape   tensor.Shape // shape of x
+}
+
+func (op topKOp) Arity() int { return 1 }
+
+// topKOp has this type:
+//		op :: Tensor-d a → Tensor-d Int
+func (op topKOp) Type() hm.Type {
+	return hm.NewFnType(newTensorType(len(op.shape), hm.TypeVariable('a')), newTensorType(len(op.shape), Int))
+}
+
+// topShape returns the shape of the result
+func (op topKOp) topShape() tensor.Shape {
+	retVal := op.shape.Clone()
+	retVal[op.axis] = op.k
+	return retVal
+}
+
+func (op topKOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	s, ok := inputs[0].(tensor.Shape)
+	if !ok {
+		return nil, errors.Errorf("Expected a shape. Got %T instead", inputs[0])
+	}
+	if !sameShape(s, op.shape) {
+		return nil, errors.Errorf("%v expects an input of shape %v. Got %v instead", op, op.shape, s)
+	}
+	return op.topShape(), nil
+}
+
+func (op topKOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(*tensor.Dense)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "topKOp", inputs[0])
+	}
+	var indices *tensor.Dense
+	if _, indices, err = t.TopK(op.k, op.axis); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	return indices, nil
+}
+
+func (op topKOp) ReturnsPtr() bool     { return false }
+func (op topKOp) CallsExtern() bool    { return false }
+func (op topKOp) OverwritesInput() int { return -1 }
+
+func (op topKOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "topK%d%d%v", op.k, op.axis, op.shape)
+}
+
+func (op topKOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op topKOp) String() string { return fmt.Sprintf("TopKIndices(k=%d, axis=%d)", op.k, op.axis) }
+
+func (op topKOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+func (op topKOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+// DoDiff is a no-op, as there is nothing to backpropagate through Ints.
+func (op topKOp) DoDiff(inputs Nodes, output *Node) error { return nil }
+
+// padOp pads a value along each axis with tensor.Pad.
+type padOp struct {
+	widths [][2]int
+	mode   tensor.PadMode
+	value  float64      // the value of the padding in constant mode
+	shape  tensor.Shape // shape of the input
+}
+
+func (op padOp) Arity() int { return 1 }
+
+// padOp has this type:
+//		op :: Tensor-d a → Tensor-d a
+func (op padOp) Type() hm.Type {
+	t := newTensorType(len(op.shape), hm.TypeVariable('a'))
+	return hm.NewFnType(t, t)
+}
+
+func (op padOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	retVal := make(tensor.Shape, len(op.shape))
+	for i, w := range op.widths {
+		retVal[i] = op.shape[i] + w[0] + w[1]
+	}
+	return retVal, nil
+}
+
+func (op padOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(tensor.Tensor)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "padOp.Do", inputs[0])
+	}
+
+	var value interface{}
+	switch t.Dtype() {
+	case Float64:
+		value = op.value
+	case Float32:
+		value = float32(op.value)
+	default:
+		return nil, errors.Errorf(nyiFail, "padOp.Do", t.Dtype())
+	}
+	return tensor.Pad(t, op.widths, op.mode, value)
+}
+
+func (op padOp) ReturnsPtr() bool     { return false }
+func (op padOp) CallsExtern() bool    { return false }
+func (op padOp) OverwritesInput() int { return -1 }
+
+func (op padOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "pad%v%v%v%v", op.widths, op.mode, op.value, op.shape)
+}
+
+func (op padOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op padOp) String() string { return fmt.Sprintf("Pad(%v, %v)", op.widths, op.mode) }
+
+func (op padOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op padOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(padDiffOp{op}, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op padOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = (padDiffOp{op}).Do(ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if _, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// padDiffOp is the gradient of a padOp. The gradient of each padded value is added to the gradient of the value it was copied from,
+// which is found by padding the indices of the input. In constant mode, this is the gradient with the padding sliced off.
+type padDiffOp struct {
+	padOp
+}
+
+func (op padDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.shape.Clone(), nil
+}
+
+func (op padDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var grad []float64
+	if grad, err = float64sOf(inputs[0]); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	size := op.shape.TotalSize()
+	indices := tensor.New(tensor.WithShape(op.shape.Clone()...), tensor.WithBacking(tensor.Range(Int, 0, size)))
+	var from tensor.Tensor
+	if from, err = tensor.Pad(indices, op.widths, op.mode, -1); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+
+	res := make([]float64, size)
+	for i, at := range from.Data().([]int) {
+		if at >= 0 {
+			res[at] += grad[i]
+		}
+	}
+	return valueFromFloat64s(inputs[0].Dtype(), op.shape, res)
+}
+
+func (op padDiffOp) WriteHash(h hash.Hash) {
+	op.padOp.WriteHash(h)
+	h.Write([]byte("Diff"))
+}
+
+func (op padDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op padDiffOp) String() string { return fmt.Sprintf("∂Pad(%v, %v)", op.widths, op.mode) }
+
+func (op padDiffOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+func (op padDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op padDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
+
+// stackOp stacks values of the same shape along a new axis.
+type stackOp struct {
+	axis  int
+	shape tensor.Shape // shape of each input
+	n     int          // number of inputs
+}
+
+func (op stackOp) Arity() int { return op.n }
+
+// stackOp has this type:
+//		op :: Tensor-d a → Tensor-d a → ... → Tensor-(d+1) a
+// where a Tensor-0 a is an a.
+func (op stackOp) Type() hm.Type {
+	a := hm.TypeVariable('a')
+	ts := make(hm.Types, op.n+1)
+	for i := 0; i < op.n; i++ {
+		ts[i] = shapedType(op.shape, a)
+	}
+	ts[op.n] = newTensorType(len(op.shape)+1, a)
+	return hm.NewFnType(ts...)
+}
+
+// stacked returns the shape of the result
+func (op stackOp) stacked() tensor.Shape {
+	retVal := make(tensor.Shape, 0, len(op.shape)+1)
+	retVal = append(retVal, op.shape[:op.axis]...)
+	retVal = append(retVal, op.n)
+	return append(retVal, op.shape[op.axis:]...)
+}
+
+func (op stackOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	for i, in := range inputs {
+		s, ok := in.(tensor.Shape)
+		if !ok {
+			return nil, errors.Errorf("Expected a shape. Got %T instead", in)
+		}
+		if !sameShape(s, op.shape) && !(len(op.shape) == 0 && s.IsScalar()) {
+			return nil, errors.Errorf("%v expects input %d to be of shape %v. Got %v instead", op, i, op.shape, s)
+		}
+	}
+	return op.stacked(), nil
+}
+
+func (op stackOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	// scalars are stacked into a vector
+	if len(op.shape) == 0 {
+		ret := tensor.New(tensor.Of(inputs[0].Dtype()), tensor.WithShape(op.n))
+		for i, in := range inputs {
+			ret.Set(i, in.Data())
+		}
+		return ret, nil
+	}
+
+	var ts []tensor.Tensor
+	if ts, err = valuesToTensors(inputs); err != nil {
+		return nil, errors.Wrapf(err, doFail, op)
+	}
+	ds := make([]*tensor.Dense, len(ts))
+	for i, t := range ts {
+		var ok bool
+		if ds[i], ok = t.(*tensor.Dense); !ok {
+			return nil, errors.Errorf(nyiTypeFail, "stackOp.Do()", t)
+		}
+	}
+	return ds[0].Stack(op.axis, ds[1:]...)
+}
+
+func (op stackOp) ReturnsPtr() bool     { return false }
+func (op stackOp) CallsExtern() bool    { return false }
+func (op stackOp) OverwritesInput() int { return -1 }
+
+func (op stackOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "stack%d%v%d", op.axis, op.shape, op.n)
+}
+
+func (op stackOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op stackOp) String() string { return fmt.Sprintf("Stack(axis=%d)", op.axis) }
+
+func (op stackOp) DiffWRT(inputs int) []bool {
+	retVal := make([]bool, inputs)
+	for i := range retVal {
+		retVal[i] = true
+	}
+	return retVal
+}
+
+func (op stackOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	if retVal, err = Unstack(grad, op.axis); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	for _, n := range retVal {
+		n.setGroup(gradClust)
+	}
+	return
+}
+
+func (op stackOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	ydv := output.boundTo.(*dualValue)
+	for i, in := range inputs {
+		xdv := in.boundTo.(*dualValue)
+
+		slice := newSliceOp(S(i), op.axis, op.stacked().Dims())
+		var d Value
+		if d, err = slice.Do(ydv.d); err != nil {
+			return errors.Wrapf(err, doFail, slice)
+		}
+
+		add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+		if d, err = add.UnsafeDo(xdv.d, d); err != nil {
+			return errors.Wrapf(err, unsafeDoFail, add)
+		}
+		if err = xdv.SetDeriv(d); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// repeatEachOp repeats each value along an axis by its own number of repeats, like Numpy's repeat.
+// Repeating all of the values the same number of times is done by a repeatOp instead.
+type repeatEachOp struct {
+	axis    int
+	repeats []int
+	shape   tensor.Shape // shape of the input
+}
+
+func (op repeatEachOp) Arity() int { return 1 }
+
+// repeatEachOp has this type:
+//		op :: Tensor-d a → Tensor-d a
+func (op repeatEachOp) Type() hm.Type {
+	t := newTensorType(len(op.shape), hm.TypeVariable('a'))
+	return hm.NewFnType(t, t)
+}
+
+func (op repeatEachOp) InferShape(inputs ...DimSizer) (retVal tensor.Shape, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+	retVal, _, _, err = op.shape.Repeat(op.axis, op.repeats...)
+	return
+}
+
+func (op repeatEachOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(tensor.Tensor)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "repeatEachOp.Do()", inputs[0])
+	}
+	// tensor.Repeat copies the backing data, so views have to be materialized first
+	if d, ok := t.(*tensor.Dense); ok && d.IsMaterializable() {
+		t = d.Materialize()
+	}
+	if retVal, err = tensor.Repeat(t, op.axis, op.repeats...); err != nil {
+		return nil, errors.Wrapf(err, repFail, op.axis, op.repeats)
+	}
+	return
+}
+
+// unrepeated returns the shape of the input without the axis
+func (op repeatEachOp) unrepeated() tensor.Shape {
+	retVal := make(tensor.Shape, 0, len(op.shape)-1)
+	retVal = append(retVal, op.shape[:op.axis]...)
+	return append(retVal, op.shape[op.axis+1:]...)
+}
+
+func (op repeatEachOp) ReturnsPtr() bool     { return false }
+func (op repeatEachOp) CallsExtern() bool    { return false }
+func (op repeatEachOp) OverwritesInput() int { return -1 }
+
+func (op repeatEachOp) WriteHash(h hash.Hash) {
+	fmt.Fprintf(h, "repeatEach%d%v%v", op.axis, op.repeats, op.shape)
+}
+
+func (op repeatEachOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op repeatEachOp) String() string { return fmt.Sprintf("Repeat(axis=%d)%v", op.axis, op.repeats) }
+
+func (op repeatEachOp) DiffWRT(inputs int) []bool { return []bool{true} }
+
+func (op repeatEachOp) SymDiff(inputs Nodes, output, grad *Node) (retVal Nodes, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	var dx *Node
+	if dx, err = applyOp(repeatEachDiffOp{op}, grad); err != nil {
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+	dx.setGroup(gradClust)
+	return Nodes{dx}, nil
+}
+
+func (op repeatEachOp) DoDiff(inputs Nodes, output *Node) (err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	xdv := inputs[0].boundTo.(*dualValue)
+	ydv := output.boundTo.(*dualValue)
+
+	var d Value
+	if d, err = (repeatEachDiffOp{op}).Do(ydv.d); err != nil {
+		return errors.Wrapf(err, doFail, op)
+	}
+
+	add := newEBOByType(addOpType, TypeOf(xdv.d), TypeOf(d))
+	if _, err = add.UnsafeDo(xdv.d, d); err != nil {
+		return errors.Wrapf(err, unsafeDoFail, add)
+	}
+	return nil
+}
+
+// repeatEachDiffOp is the gradient of a repeatEachOp. The gradient of each value is the sum of the gradients of its repeats.
+type repeatEachDiffOp struct {
+	repeatEachOp
+}
+
+func (op repeatEachDiffOp) InferShape(inputs ...DimSizer) (tensor.Shape, error) {
+	if err := checkArity(op, len(inputs)); err != nil {
+		return nil, err
+	}
+	return op.shape.Clone(), nil
+}
+
+func (op repeatEachDiffOp) Do(inputs ...Value) (retVal Value, err error) {
+	if err = checkArity(op, len(inputs)); err != nil {
+		return
+	}
+
+	t, ok := inputs[0].(tensor.Tensor)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "repeatEachDiffOp.Do()", inputs[0])
+	}
+
+	// the gradients of the repeats of each value are summed into a part, which has the shape of the input without the axis.
+	// Stacking the parts along the axis gives the gradient of the input.
+	parts := make([]tensor.Tensor, len(op.repeats))
+	slices := make([]tensor.Slice, op.axis+1)
+	var start int
+	for i, r := range op.repeats {
+		switch r {
+		case 0:
+			parts[i] = tensor.New(tensor.Of(t.Dtype()), tensor.WithShape(op.unrepeated()...))
+		case 1:
+			slices[op.axis] = S(start)
+			if parts[i], err = t.Slice(slices...); err != nil {
+				return nil, errors.Wrapf(err, sliceFail, slices)
+			}
+		default:
+			slices[op.axis] = S(start, start+r)
+			var repeated tensor.Tensor
+			if repeated, err = t.Slice(slices...); err != nil {
+				return nil, errors.Wrapf(err, sliceFail, slices)
+			}
+			if parts[i], err = tensor.Sum(repeated.Materialize(), op.axis); err != nil {
+				return nil, errors.Wrap(err, "Failed to sum the gradients of the repeats")
+			}
+		}
+		start += r
+	}
+
+	// the parts of a vector are scalars
+	if len(op.shape) == 1 {
+		ret := tensor.New(tensor.Of(t.Dtype()), tensor.WithShape(op.shape...))
+		for i, part := range parts {
+			ret.Set(i, part.ScalarValue())
+		}
+		return ret, nil
+	}
+
+	ds := make([]*tensor.Dense, len(parts))
+	for i, part := range parts {
+		if ds[i], ok = part.Materialize().(*tensor.Dense); !ok {
+			return nil, errors.Errorf(nyiTypeFail, "repeatEachDiffOp.Do()", part)
+		}
+	}
+	return ds[0].Stack(op.axis, ds[1:]...)
+}
+
+func (op repeatEachDiffOp) WriteHash(h hash.Hash) {
+	op.repeatEachOp.WriteHash(h)
+	h.Write([]byte("Diff"))
+}
+
+func (op repeatEachDiffOp) Hashcode() uint32 {
+	h := fnv.New32a()
+	op.WriteHash(h)
+	return h.Sum32()
+}
+
+func (op repeatEachDiffOp) String() string { return "∂" + op.repeatEachOp.String() }
+
+func (op repeatEachDiffOp) DiffWRT(inputs int) []bool { return []bool{false} }
+
+func (op repeatEachDiffOp) SymDiff(inputs Nodes, output, grad *Node) (Nodes, error) {
+	return nil, nondiffErr(op)
+}
+
+func (op repeatEachDiffOp) DoDiff(inputs Nodes, output *Node) error { return nondiffErr(op) }
@@ -304,3 +304,621 @@ func TestConcatOp(t *testing.T) {
 	assert.True(ValueEq(xx.Value(), aa.Value()))
 
 }
+
+func TestReshape(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGraph()
+	x := NewTensor(g, Float64, 3, WithShape(2, 3, 4), WithName("x"))
+	v := NewVector(g, Float64, WithShape(3), WithName("v"))
+
+	shapes := []struct {
+		name    string
+		fn      func() (*Node, error)
+		correct tensor.Shape
+		err     bool
+	}{
+		{"Reshape(x, -1, 4)", func() (*Node, error) { return Reshape(x, -1, 4) }, tensor.Shape{6, 4}, false},
+		{"Reshape(x, 2, -1)", func() (*Node, error) { return Reshape(x, 2, -1) }, tensor.Shape{2, 12}, false},
+		{"Reshape(x, 4, 3, 2)", func() (*Node, error) { return Reshape(x, 4, 3, 2) }, tensor.Shape{4, 3, 2}, false},
+		{"Reshape(x, 5, -1)", func() (*Node, error) { return Reshape(x, 5, -1) }, nil, true},
+		{"Reshape(x, -1, -1)", func() (*Node, error) { return Reshape(x, -1, -1) }, nil, true},
+		{"Reshape(x, 4, 5)", func() (*Node, error) { return Reshape(x, 4, 5) }, nil, true},
+		{"Flatten(x)", func() (*Node, error) { return Flatten(x) }, tensor.Shape{24}, false},
+		{"ExpandDims(v, 0)", func() (*Node, error) { return ExpandDims(v, 0) }, tensor.Shape{1, 3}, false},
+		{"ExpandDims(v, 1)", func() (*Node, error) { return ExpandDims(v, 1) }, tensor.Shape{3, 1}, false},
+		{"ExpandDims(v, 2)", func() (*Node, error) { return ExpandDims(v, 2) }, nil, true},
+		{"Squeeze(ExpandDims(v, 0))", func() (*Node, error) { return Squeeze(Must(ExpandDims(v, 0))) }, tensor.Shape{3}, false},
+		{"Squeeze(x, 1)", func() (*Node, error) { return Squeeze(x, 1) }, nil, true},
+	}
+	for _, s := range shapes {
+		n, err := s.fn()
+		if s.err {
+			assert.Error(err, s.name)
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %+v", s.name, err)
+			continue
+		}
+		assert.Equal(s.correct, n.Shape(), s.name)
+	}
+
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking(tensor.Range(tensor.Float64, 0, 6)))))
+		c := NewConstant(tensor.New(tensor.WithShape(3, 2), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6})), WithName("c"))
+		y := Must(Reshape(x, 3, -1))
+		z := Must(Squeeze(Must(ExpandDims(y, 0))))
+		cost := Must(Sum(Must(HadamardProd(z, c))))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		assert.Equal(tensor.Shape{3, 2}, z.Value().Shape())
+		assert.Equal(0*1+1*2+2*3+3*4+4*5+5*6.0, extractF64(cost.Value()))
+
+		gx, err := x.Grad()
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(tensor.Shape{2, 3}, gx.Shape())
+		assert.Equal([]float64{1, 2, 3, 4, 5, 6}, extractF64s(gx))
+	}
+
+	// reshaping shares the data
+	op := reshapeOp{from: tensor.Shape{2, 3}, to: tensor.Shape{6}}
+	xT := tensor.New(tensor.WithShape(2, 3), tensor.WithBacking(tensor.Range(tensor.Float64, 0, 6)))
+	yV, err := op.Do(xT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(tensor.Shape{6}, yV.Shape())
+	xT.Data().([]float64)[4] = 100
+	assert.Equal(100.0, yV.Data().([]float64)[4])
+}
+
+// TestReshape_Aliasing checks that the tape machine does not overwrite a reshaped value while the value it shares its data with is still used
+func TestReshape_Aliasing(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGraph()
+	a := NewMatrix(g, Float64, WithShape(2, 3), WithName("a"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking(tensor.Range(tensor.Float64, 0, 6)))))
+	x := Must(Neg(a))
+	y := Must(Add(Must(Reshape(x, 6)), NewConstant(1.0)))
+	z := Must(Add(x, NewConstant(2.0)))
+
+	prog, locMap, err := Compile(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewTapeMachine(prog, locMap)
+	if err = m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]float64{1, 0, -1, -2, -3, -4}, extractF64s(y.Value()))
+	assert.Equal([]float64{2, 1, 0, -1, -2, -3}, extractF64s(z.Value()))
+}
+
+func TestGather(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{0, 1, 2, 3, 4, 5}))))
+		v := NewVector(g, Float64, WithShape(3), WithName("v"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{10, 20, 30}))))
+		idx := NewVector(g, Int, WithShape(3), WithName("idx"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]int{2, 0, 2}))))
+		idxM := NewMatrix(g, Int, WithShape(2, 2), WithName("idxM"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]int{1, 0, 1, 1}))))
+		idxS := NewScalar(g, Int, WithName("idxS"), WithValue(1))
+
+		z := Must(Gather(x, idx, 1))
+		y := Must(Gather(x, idxM, 0))
+		s := Must(Gather(v, idxS, 0))
+		assert.Equal(tensor.Shape{2, 3}, z.Shape())
+		assert.Equal(tensor.Shape{2, 2, 3}, y.Shape())
+		assert.True(s.IsScalar())
+
+		c := NewConstant(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6})))
+		cost := Must(Add(Must(Add(Must(Sum(Must(HadamardProd(z, c)))), Must(Sum(y)))), s))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x, v); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		// the tape machine may reuse the registers of z and y when computing the gradients
+		if i == 1 {
+			assert.Equal([]float64{2, 0, 2, 5, 3, 5}, extractF64s(z.Value()))
+			assert.Equal([]float64{3, 4, 5, 0, 1, 2, 3, 4, 5, 3, 4, 5}, extractF64s(y.Value()))
+			assert.Equal(20.0, extractF64(s.Value()))
+		}
+		assert.Equal(73+39+20.0, extractF64(cost.Value()))
+
+		gx, _ := x.Grad()
+		gv, _ := v.Grad()
+		assert.Equal([]float64{3, 1, 5, 8, 3, 13}, extractF64s(gx))
+		assert.Equal([]float64{0, 1, 0}, extractF64s(gv))
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"))
+	idx := NewVector(g, Int, WithShape(2), WithName("idx"), WithValue(tensor.New(tensor.WithShape(2), tensor.WithBacking([]int{0, 3}))))
+	fidx := NewVector(g, Float64, WithShape(2), WithName("fidx"))
+	if _, err := Gather(x, fidx, 0); err == nil {
+		t.Error("Expected an error for Float64 indices")
+	}
+	if _, err := Gather(x, idx, 2); err == nil {
+		t.Error("Expected an error for an axis out of range")
+	}
+
+	op := gatherOp{axis: 1, xShape: tensor.Shape{2, 3}, idxShape: tensor.Shape{2}}
+	if _, err := op.Do(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking(make([]float64, 6))), idx.Value()); err == nil {
+		t.Error("Expected an error for an index out of bounds")
+	}
+}
+
+func TestTopK(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{0, 5, 2, 7, 1, 3}))))
+		values, indices, err := TopK(x, 2, 1)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(tensor.Shape{2, 2}, values.Shape())
+		assert.Equal(tensor.Shape{2, 2}, indices.Shape())
+
+		// the indices are used to look up v, as beam searches do
+		v := NewVector(g, Float64, WithShape(3), WithName("v"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{10, 20, 30}))))
+		c := NewConstant(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float64{1, 2, 3, 4})))
+		cost := Must(Add(Must(Sum(Must(HadamardProd(values, c)))), Must(Sum(Must(Gather(v, indices, 0))))))
+
+		var m VM
+		if i == 0 {
+			if _, err = Grad(cost, x, v); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err = m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		// the tape machine may reuse the register of values when computing the gradients
+		if i == 1 {
+			assert.Equal([]float64{5, 2, 7, 3}, extractF64s(values.Value()))
+		}
+		assert.Equal([]int{1, 2, 0, 2}, indices.Value().Data())
+		assert.Equal(5+4+21+12+90.0, extractF64(cost.Value()))
+
+		gx, _ := x.Grad()
+		gv, _ := v.Grad()
+		assert.Equal([]float64{0, 1, 2, 3, 0, 4}, extractF64s(gx))
+		assert.Equal([]float64{1, 1, 2}, extractF64s(gv))
+	}
+
+	// the values are gathered along the middle axis at the indices
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewTensor(g, Float64, 3, WithShape(2, 3, 2), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3, 2), tensor.WithBacking([]float64{0, 9, 4, 1, 2, 5, 3, 8, 6, 2, 7, 0}))))
+		values, indices, err := TopK(x, 2, 1)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(Nodes{x, indices}, values.children)
+		c := NewConstant(tensor.New(tensor.WithShape(2, 2, 2), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6, 7, 8})))
+		cost := Must(Sum(Must(HadamardProd(values, c))))
+
+		var m VM
+		if i == 0 {
+			if _, err = Grad(cost, x); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err = m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		if i == 1 {
+			assert.Equal([]float64{4, 9, 2, 5, 7, 8, 6, 2}, extractF64s(values.Value()))
+		}
+		assert.Equal([]int{1, 0, 2, 2, 2, 0, 1, 1}, indices.Value().Data())
+		gx, _ := x.Grad()
+		assert.Equal([]float64{0, 2, 1, 0, 3, 4, 0, 6, 7, 8, 5, 0}, extractF64s(gx))
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"))
+	if _, _, err := TopK(x, 4, 1); err == nil {
+		t.Error("Expected an error when k is larger than the axis")
+	}
+	if _, _, err := TopK(x, 1, 2); err == nil {
+		t.Error("Expected an error for an axis out of range")
+	}
+}
+
+func TestScatterAdd(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		into := NewMatrix(g, Float64, WithShape(3, 2), WithName("into"), WithValue(tensor.New(tensor.WithShape(3, 2), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}))))
+		updates := NewMatrix(g, Float64, WithShape(3, 2), WithName("updates"), WithValue(tensor.New(tensor.WithShape(3, 2), tensor.WithBacking([]float64{10, 20, 30, 40, 50, 60}))))
+		idx := NewVector(g, Int, WithShape(3), WithName("idx"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]int{2, 0, 2}))))
+
+		z := Must(ScatterAdd(into, idx, updates, 0))
+		assert.Equal(tensor.Shape{3, 2}, z.Shape())
+		c := NewConstant(tensor.New(tensor.WithShape(3, 2), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6})))
+		cost := Must(Sum(Must(HadamardProd(z, c))))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, into, updates); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		if i == 1 {
+			assert.Equal([]float64{31, 42, 3, 4, 65, 86}, extractF64s(z.Value()))
+		}
+		assert.Equal([]float64{1, 2, 3, 4, 5, 6}, extractF64s(into.Value()))
+
+		gi, _ := into.Grad()
+		gu, _ := updates.Grad()
+		assert.Equal([]float64{1, 2, 3, 4, 5, 6}, extractF64s(gi))
+		assert.Equal([]float64{5, 6, 1, 2, 5, 6}, extractF64s(gu))
+	}
+
+	g := NewGraph()
+	into := NewMatrix(g, Float64, WithShape(3, 2), WithName("into"))
+	idx := NewVector(g, Int, WithShape(2), WithName("idx"))
+	updates := NewMatrix(g, Float64, WithShape(3, 2), WithName("updates"))
+	if _, err := ScatterAdd(into, idx, updates, 0); err == nil {
+		t.Error("Expected an error for updates of the wrong shape")
+	}
+}
+
+func TestPad(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{0, 1, 2, 3, 4, 5}))))
+		v := NewVector(g, Float64, WithShape(3), WithName("v"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{1, 2, 3}))))
+
+		z := Must(Pad(x, [][2]int{{1, 0}, {0, 1}}, PadConstant, -1))
+		r := Must(Pad(v, [][2]int{{2, 1}}, PadReflect, 0))
+		e := Must(Pad(v, [][2]int{{1, 2}}, PadEdge, 0))
+		assert.Equal(tensor.Shape{3, 4}, z.Shape())
+		assert.Equal(tensor.Shape{6}, r.Shape())
+		assert.Equal(tensor.Shape{6}, e.Shape())
+
+		cz := NewConstant(tensor.New(tensor.WithShape(3, 4), tensor.WithBacking(tensor.Range(Float64, 1, 13))))
+		cv := NewConstant(tensor.New(tensor.WithShape(6), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6})))
+		cost := Must(Add(Must(Sum(Must(HadamardProd(z, cz)))), Must(Sum(Must(HadamardProd(Must(Add(r, e)), cv))))))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x, v); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		// the tape machine may reuse the registers of z, r and e when computing the gradients
+		if i == 1 {
+			assert.Equal([]float64{-1, -1, -1, -1, 0, 1, 2, -1, 3, 4, 5, -1}, extractF64s(z.Value()))
+			assert.Equal([]float64{3, 2, 1, 2, 3, 2}, extractF64s(r.Value()))
+			assert.Equal([]float64{1, 1, 2, 3, 3, 3}, extractF64s(e.Value()))
+		}
+
+		// the gradient of x is cz with the padding sliced off. The padded values of r and e add to the gradients of the values they are copied from
+		gx, _ := x.Grad()
+		gv, _ := v.Grad()
+		assert.Equal([]float64{5, 6, 7, 9, 10, 11}, extractF64s(gx))
+		assert.Equal([]float64{3 + 1 + 2, 4 + 2 + 6 + 3, 5 + 1 + 4 + 5 + 6}, extractF64s(gv))
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"))
+	if _, err := Pad(x, [][2]int{{1, 1}}, PadConstant, 0); err == nil {
+		t.Error("Expected an error for too few widths")
+	}
+	if _, err := Pad(x, [][2]int{{2, 0}, {0, 0}}, PadReflect, 0); err == nil {
+		t.Error("Expected an error for a reflected padding wider than the axis")
+	}
+}
+
+func TestStack(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		a := NewVector(g, Float64, WithShape(3), WithName("a"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{1, 2, 3}))))
+		b := NewVector(g, Float64, WithShape(3), WithName("b"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{4, 5, 6}))))
+		c := NewScalar(g, Float64, WithName("c"), WithValue(7.0))
+		d := NewScalar(g, Float64, WithName("d"), WithValue(8.0))
+		m := NewMatrix(g, Float64, WithShape(2, 3), WithName("m"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{0, 1, 2, 3, 4, 5}))))
+
+		s0 := Must(Stack(0, a, b))
+		s1 := Must(Stack(1, a, b))
+		sc := Must(Stack(0, c, d))
+		us, err := Unstack(m, 1)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		assert.Equal(tensor.Shape{2, 3}, s0.Shape())
+		assert.Equal(tensor.Shape{3, 2}, s1.Shape())
+		assert.Equal(tensor.Shape{2}, sc.Shape())
+		assert.Equal(3, len(us))
+		assert.Equal(tensor.Shape{2}, us[0].Shape())
+		restacked := Must(Stack(1, us...))
+
+		c6 := tensor.Range(Float64, 1, 7).([]float64)
+		costs := Nodes{
+			Must(Sum(Must(HadamardProd(s0, NewConstant(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking(c6))))))),
+			Must(Sum(Must(HadamardProd(s1, NewConstant(tensor.New(tensor.WithShape(3, 2), tensor.WithBacking(c6))))))),
+			Must(Sum(Must(HadamardProd(sc, NewConstant(tensor.New(tensor.WithShape(2), tensor.WithBacking([]float64{1, 2}))))))),
+			Must(Sum(Must(HadamardProd(us[0], NewConstant(tensor.New(tensor.WithShape(2), tensor.WithBacking([]float64{1, 2}))))))),
+			Must(Sum(us[2])),
+			Must(Sum(restacked)),
+		}
+		cost := Must(ReduceAdd(costs))
+
+		var vm VM
+		if i == 0 {
+			if _, err := Grad(cost, a, b, c, d, m); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			vm = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			vm = NewLispMachine(g)
+		}
+		if err := vm.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		assert.Equal([]float64{1, 2, 3, 4, 5, 6}, extractF64s(s0.Value()))
+		assert.Equal([]float64{1, 4, 2, 5, 3, 6}, extractF64s(s1.Value()))
+		assert.Equal([]float64{7, 8}, extractF64s(sc.Value()))
+		assert.Equal([]float64{0, 3}, extractF64s(us[0].Value().(tensor.Tensor).Materialize()))
+		assert.Equal([]float64{2, 5}, extractF64s(us[2].Value().(tensor.Tensor).Materialize()))
+		assert.Equal([]float64{0, 1, 2, 3, 4, 5}, extractF64s(restacked.Value()))
+
+		ga, _ := a.Grad()
+		gb, _ := b.Grad()
+		gc, _ := c.Grad()
+		gd, _ := d.Grad()
+		gm, _ := m.Grad()
+		assert.Equal([]float64{2, 5, 8}, extractF64s(ga))
+		assert.Equal([]float64{6, 9, 12}, extractF64s(gb))
+		assert.Equal(1.0, extractF64(gc))
+		assert.Equal(2.0, extractF64(gd))
+		assert.Equal([]float64{2, 1, 2, 3, 1, 2}, extractF64s(gm))
+	}
+
+	g := NewGraph()
+	a := NewVector(g, Float64, WithShape(3), WithName("a"))
+	b := NewVector(g, Float64, WithShape(2), WithName("b"))
+	c := NewScalar(g, Float64, WithName("c"))
+	if _, err := Stack(0, a, b); err == nil {
+		t.Error("Expected an error when stacking nodes of different shapes")
+	}
+	if _, err := Stack(2, a, a); err == nil {
+		t.Error("Expected an error for an axis out of range")
+	}
+	if _, err := Unstack(c, 0); err == nil {
+		t.Error("Expected an error when unstacking a scalar")
+	}
+}
+
+func TestTileRepeat(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewMatrix(g, Float64, WithShape(2, 2), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 2), tensor.WithBacking([]float64{1, 2, 3, 4}))))
+		v := NewVector(g, Float64, WithShape(3), WithName("v"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{1, 2, 3}))))
+
+		tx := Must(Tile(x, 1, 2))
+		tx2 := Must(Tile(x, 2, 1))
+		rx := Must(Repeat(x, 0, 1, 2))
+		rx1 := Must(Repeat(x, 1, 2))
+		tv := Must(Tile(v, 2))
+		rv := Must(Repeat(v, 0, 2))
+		rv2 := Must(Repeat(v, 0, 0, 1, 3))
+		assert.Equal(tensor.Shape{2, 4}, tx.Shape())
+		assert.Equal(tensor.Shape{4, 2}, tx2.Shape())
+		assert.Equal(tensor.Shape{3, 2}, rx.Shape())
+		assert.Equal(tensor.Shape{2, 4}, rx1.Shape())
+		assert.Equal(tensor.Shape{6}, tv.Shape())
+		assert.Equal(tensor.Shape{6}, rv.Shape())
+		assert.Equal(tensor.Shape{4}, rv2.Shape())
+
+		ct := NewConstant(tensor.New(tensor.WithShape(2, 4), tensor.WithBacking(tensor.Range(Float64, 1, 9))))
+		cr := NewConstant(tensor.New(tensor.WithShape(3, 2), tensor.WithBacking(tensor.Range(Float64, 1, 7))))
+		cv := NewConstant(tensor.New(tensor.WithShape(6), tensor.WithBacking(tensor.Range(Float64, 1, 7))))
+		cv2 := NewConstant(tensor.New(tensor.WithShape(4), tensor.WithBacking(tensor.Range(Float64, 1, 5))))
+		cost := Must(ReduceAdd(Nodes{
+			Must(Sum(Must(HadamardProd(tx, ct)))),
+			Must(Sum(tx2)),
+			Must(Sum(Must(HadamardProd(rx, cr)))),
+			Must(Sum(Must(HadamardProd(rx1, ct)))),
+			Must(Sum(Must(HadamardProd(tv, cv)))),
+			Must(Sum(rv)),
+			Must(Sum(Must(HadamardProd(rv2, cv2)))),
+		}))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x, v); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		assert.Equal([]float64{1, 2, 1, 2, 3, 4, 3, 4}, extractF64s(tx.Value()))
+		assert.Equal([]float64{1, 2, 3, 4, 1, 2, 3, 4}, extractF64s(tx2.Value()))
+		assert.Equal([]float64{1, 2, 3, 4, 3, 4}, extractF64s(rx.Value()))
+		assert.Equal([]float64{1, 1, 2, 2, 3, 3, 4, 4}, extractF64s(rx1.Value()))
+		assert.Equal([]float64{1, 2, 3, 1, 2, 3}, extractF64s(tv.Value()))
+		assert.Equal([]float64{1, 1, 2, 2, 3, 3}, extractF64s(rv.Value()))
+		assert.Equal([]float64{2, 3, 3, 3}, extractF64s(rv2.Value()))
+
+		gx, _ := x.Grad()
+		gv, _ := v.Grad()
+		assert.Equal([]float64{10, 17, 33, 41}, extractF64s(gx))
+		assert.Equal([]float64{7, 10, 20}, extractF64s(gv))
+	}
+
+	g := NewGraph()
+	x := NewMatrix(g, Float64, WithShape(2, 2), WithName("x"))
+	if _, err := Tile(x, 2); err == nil {
+		t.Error("Expected an error for too few repetitions")
+	}
+	if _, err := Repeat(x, 0, 1, 2, 3); err == nil {
+		t.Error("Expected an error for the wrong number of repeats")
+	}
+	if _, err := Repeat(x, 2, 1); err == nil {
+		t.Error("Expected an error for an axis out of range")
+	}
+	if _, err := Repeat(x, 0, 0); err == nil {
+		t.Error("Expected an error for an empty result")
+	}
+}
+
+// TestTile_Gradient checks the gradient of Tile with upstream gradients that differ for every tile
+func TestTile_Gradient(t *testing.T) {
+	assert := assert.New(t)
+	w := []float64{5, 7, 8, 0, 3, 5, 7, 6, 8, 3, 9, 7, 5, 8, 2, 3, 2, 1}
+	w2 := []float64{5, 7, 8, 0, 3, 5, 7, 6, 8, 3, 9, 7, 5, 8, 2, 3, 2, 1, 1, 2, 3, 2, 8, 5, 7, 9, 3, 8, 6, 7, 5, 3, 0, 8, 7, 5}
+	tiles := []struct {
+		reps    []int
+		w       []float64
+		correct []float64
+	}{
+		{[]int{1, 3}, w, []float64{12, 16, 21, 11, 19, 10}},
+		{[]int{2, 3}, w2, []float64{22, 35, 32, 32, 35, 22}},
+	}
+
+	for _, tt := range tiles {
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			x := NewMatrix(g, Float64, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking(tensor.Range(Float64, 1, 7)))))
+			y := Must(Tile(x, tt.reps...))
+			w := NewConstant(tensor.New(tensor.WithShape(y.Shape().Clone()...), tensor.WithBacking(tt.w)))
+			cost := Must(Sum(Must(HadamardProd(y, w))))
+
+			var m VM
+			if i == 0 {
+				if _, err := Grad(cost, x); err != nil {
+					t.Fatalf("%v: %+v", tt.reps, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", tt.reps, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err := m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", tt.reps, err)
+			}
+			gx, _ := x.Grad()
+			assert.Equal(tt.correct, extractF64s(gx), "%v", tt.reps)
+		}
+	}
+}
+
+func TestStackTileRepeat_Dtypes(t *testing.T) {
+	assert := assert.New(t)
+	g := NewGraph()
+	x := NewMatrix(g, Int, WithShape(2, 3), WithName("x"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]int{0, 1, 2, 3, 4, 5}))))
+
+	s := Must(Stack(0, x, x))
+	us, err := Unstack(x, 0)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	tx := Must(Tile(x, 2, 1))
+	rx := Must(Repeat(x, 1, 2))
+	rx0 := Must(Repeat(x, 0, 2, 1))
+	assert.Equal(tensor.Shape{2, 2, 3}, s.Shape())
+	assert.Equal(tensor.Shape{4, 3}, tx.Shape())
+
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]int{0, 1, 2, 3, 4, 5, 0, 1, 2, 3, 4, 5}, s.Value().Data())
+	assert.Equal([]int{3, 4, 5}, us[1].Value().(tensor.Tensor).Materialize().Data())
+	assert.Equal([]int{0, 1, 2, 3, 4, 5, 0, 1, 2, 3, 4, 5}, tx.Value().Data())
+	assert.Equal([]int{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 5, 5}, rx.Value().Data())
+	assert.Equal([]int{0, 1, 2, 0, 1, 2, 3, 4, 5}, rx0.Value().Data())
+}
@@ -100,22 +100,30 @@ func binOpNode(op BinaryOp, a, b *Node) (retVal *Node, err error) {
 	return applyOp(op, a, b)
 }
 
+// elemBinOpNode creates a node for an elementwise binary operation. The operands are broadcast to a common shape
+// following NumPy's rules first, so the gradients flowing back are summed to the shapes of the operands.
+func elemBinOpNode(ot ʘBinaryOperatorType, a, b *Node, retSame bool) (retVal *Node, err error) {
+	if a, b, err = broadcastOperands(a, b); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	op := newElemBinOp(ot, a, b)
+	op.retSame = retSame
+	return binOpNode(op, a, b)
+}
+
 // Add performs pointwise a + b
 func Add(a, b *Node) (retVal *Node, err error) {
-	op := newElemBinOp(addOpType, a, b)
-	return binOpNode(op, a, b)
+	return elemBinOpNode(addOpType, a, b, false)
 }
 
 // Sub performs pointwise a - b
 func Sub(a, b *Node) (retVal *Node, err error) {
-	op := newElemBinOp(subOpType, a, b)
-	return binOpNode(op, a, b)
+	return elemBinOpNode(subOpType, a, b, false)
 }
 
 // HadamardProd performs pointwise a * b
 func HadamardProd(a, b *Node) (retVal *Node, err error) {
-	op := newElemBinOp(mulOpType, a, b)
-	return binOpNode(op, a, b)
+	return elemBinOpNode(mulOpType, a, b, false)
 }
 
 // Mul is the general handler for multiplication of nodes. It is extremely overloaded. Only use if you know what you're doing
@@ -125,6 +133,7 @@ func HadamardProd(a, b *Node) (retVal *Node, err error) {
 // If only one of the nodes is a vector, then the operator used will be a matrix-vector multiplication will be used, and most importantly,
 // a transpose will be used (when necessary)
 // If both nodes are matrices, then well, matrix multiplication will be done
+// If both nodes are 3-tensors, then a batched matrix multiplication will be done
 func Mul(a, b *Node) (retVal *Node, err error) {
 	if a.IsScalar() || b.IsScalar() {
 		return HadamardProd(a, b)
@@ -144,12 +153,26 @@ func Mul(a, b *Node) (retVal *Node, err error) {
 	case a.IsMatrix() && b.IsMatrix():
 		op = linAlgBinOp{āBinaryOperator: matMulOperator}
 		return binOpNode(op, a, b)
+	case a.Dims() == 3 && b.Dims() == 3:
+		return BatchedMatMul(a, b, false, false)
 	default:
 		return nil, errors.Errorf(nyiFail, "Mul", fmt.Sprintf("a %v b %v", a.shape, b.shape))
 	}
 
 }
 
+// BatchedMatMul performs a matrix multiplication for each matrix along the leading batch axis of a and b, which have to be 3-tensors
+// of shapes (batch, m, k) and (batch, k, n). transA and transB indicate that the matrices of a and b respectively are to be transposed first.
+// It calls GEMM on the BLAS set by Use once for each matrix in the batch.
+func BatchedMatMul(a, b *Node, transA, transB bool) (retVal *Node, err error) {
+	if a.Dims() != 3 || b.Dims() != 3 {
+		return nil, errors.Errorf("BatchedMatMul expects both nodes to be 3-tensors. Got %v and %v", a.shape, b.shape)
+	}
+
+	op := linAlgBinOp{āBinaryOperator: batchedMatMulOperator, transA: transA, transB: transB}
+	return binOpNode(op, a, b)
+}
+
 // OuterProd returns a Node representing the outer product of two vectors. This function will return an error if both input nodes are not vectors
 func OuterProd(a, b *Node) (retVal *Node, err error) {
 	if !a.IsVector() || !b.IsVector() {
@@ -163,8 +186,7 @@ func OuterProd(a, b *Node) (retVal *Node, err error) {
 
 // HadamardDiv performs pointwise a / b
 func HadamardDiv(a, b *Node) (retVal *Node, err error) {
-	op := newElemBinOp(divOpType, a, b)
-	return binOpNode(op, a, b)
+	return elemBinOpNode(divOpType, a, b, false)
 }
 
 // Div is a shortcut function for HadamardDiv for scalar values. For matrix/tensor values, the matrix division operation is not yet handled, and will panic.
@@ -179,22 +201,158 @@ func Div(a, b *Node) (retVal *Node, err error) {
 
 // Pow performs pointwise exponentiation
 func Pow(a, b *Node) (retVal *Node, err error) {
-	op := newElemBinOp(powOpType, a, b)
-	return binOpNode(op, a, b)
+	return elemBinOpNode(powOpType, a, b, false)
 }
 
 // Gt performs a pointwise comparison a > b. retSame indicates if the return value should be the same type as the input values
 func Gt(a, b *Node, retSame bool) (retVal *Node, err error) {
-	op := newElemBinOp(gtOpType, a, b)
-	op.retSame = retSame
-	return binOpNode(op, a, b)
+	return elemBinOpNode(gtOpType, a, b, retSame)
 }
 
 // Gte performs pointwise comparison a >= b. retSame indicates if the return value should be the same type as the input values
 func Gte(a, b *Node, retSame bool) (retVal *Node, err error) {
-	op := newElemBinOp(gteOpType, a, b)
-	op.retSame = retSame
-	return binOpNode(op, a, b)
+	return elemBinOpNode(gteOpType, a, b, retSame)
+}
+
+// Lt performs a pointwise comparison a < b. retSame indicates if the return value should be the same type as the input values
+func Lt(a, b *Node, retSame bool) (retVal *Node, err error) {
+	return elemBinOpNode(ltOpType, a, b, retSame)
+}
+
+// Lte performs pointwise comparison a <= b. retSame indicates if the return value should be the same type as the input values
+func Lte(a, b *Node, retSame bool) (retVal *Node, err error) {
+	return elemBinOpNode(lteOpType, a, b, retSame)
+}
+
+// Eq performs a pointwise comparison a == b. retSame indicates if the return value should be the same type as the input values
+func Eq(a, b *Node, retSame bool) (retVal *Node, err error) {
+	return elemBinOpNode(eqOpType, a, b, retSame)
+}
+
+// Ne performs a pointwise comparison a != b. retSame indicates if the return value should be the same type as the input values
+func Ne(a, b *Node, retSame bool) (retVal *Node, err error) {
+	return elemBinOpNode(neOpType, a, b, retSame)
+}
+
+/* LOGICAL STUFF */
+
+// And performs a pointwise logical and on a and b, which have to be Bools of the same shape
+func And(a, b *Node) (retVal *Node, err error) {
+	return logicalOpNode("And", andOpType, a, b)
+}
+
+// Or performs a pointwise logical or on a and b, which have to be Bools of the same shape
+func Or(a, b *Node) (retVal *Node, err error) {
+	return logicalOpNode("Or", orOpType, a, b)
+}
+
+// Not performs a pointwise logical not on a, which has to be a Bool
+func Not(a *Node) (retVal *Node, err error) {
+	return logicalOpNode("Not", notOpType, a)
+}
+
+func logicalOpNode(fn string, which logicalOpType, children ...*Node) (retVal *Node, err error) {
+	for _, n := range children {
+		var dt tensor.Dtype
+		if dt, err = dtypeOf(n.t); err != nil {
+			return nil, errors.Wrap(err, dtypeOfFail)
+		}
+		if dt != Bool {
+			return nil, errors.Errorf(nyiFail, fn, dt)
+		}
+	}
+
+	op := logicalOp{
+		which: which,
+		d:     children[0].Dims(),
+	}
+	return applyOp(op, children...)
+}
+
+// Where selects elementwise from a where cond is true, and from b otherwise. cond is either a Bool or a 0/1 value of the same type as a and b.
+// The non scalar inputs are broadcast to a common shape following NumPy's rules, while scalars are used for every element.
+// The gradient only flows to the selected branch.
+func Where(cond, a, b *Node) (retVal *Node, err error) {
+	for _, n := range []*Node{a, b} {
+		if err = checkFloatDtype("Where", n); err != nil {
+			return nil, err
+		}
+	}
+
+	var cdt tensor.Dtype
+	if cdt, err = dtypeOf(cond.t); err != nil {
+		return nil, errors.Wrap(err, dtypeOfFail)
+	}
+
+	op := whereOp{condBool: cdt == Bool}
+	children := Nodes{cond, a, b}
+	for i, n := range children {
+		if n.IsScalar() {
+			op.scalars[i] = true
+			continue
+		}
+		if op.shape == nil {
+			op.shape = n.shape.Clone()
+			continue
+		}
+		if op.shape, err = broadcastShape(op.shape, n.shape); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+	}
+
+	for i, n := range children {
+		if op.scalars[i] {
+			continue
+		}
+		if children[i], err = broadcastTo(n, op.shape); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+	}
+	return applyOp(op, children...)
+}
+
+// Clamp clamps the values of x to [min, max]. min and max may be float64s, float32s or ints.
+// The gradient is masked: it is zero wherever x is outside of [min, max].
+func Clamp(x *Node, min, max interface{}) (retVal *Node, err error) {
+	return clamp("Clamp", x, min, max, false)
+}
+
+// ClampStraightThrough clamps the values of x to [min, max] like Clamp, but the gradient passes through unchanged, as if
+// the clamp was the identity (i.e. the straight-through estimator). This is useful for quantization-aware training,
+// or for bounding values that should still be learnt, such as log-variances.
+func ClampStraightThrough(x *Node, min, max interface{}) (retVal *Node, err error) {
+	return clamp("ClampStraightThrough", x, min, max, true)
+}
+
+func clamp(fn string, x *Node, min, max interface{}, straightThrough bool) (retVal *Node, err error) {
+	if err = checkFloatDtype(fn, x); err != nil {
+		return nil, err
+	}
+
+	op := clampOp{straightThrough: straightThrough}
+	if op.min, err = clampBound(min); err != nil {
+		return nil, errors.Wrapf(err, "%v min", fn)
+	}
+	if op.max, err = clampBound(max); err != nil {
+		return nil, errors.Wrapf(err, "%v max", fn)
+	}
+	if op.min > op.max {
+		return nil, errors.Errorf("%v expects min <= max. Got %v and %v instead", fn, min, max)
+	}
+	return applyOp(op, x)
+}
+
+func clampBound(v interface{}) (float64, error) {
+	switch b := v.(type) {
+	case float64:
+		return b, nil
+	case float32:
+		return float64(b), nil
+	case int:
+		return float64(b), nil
+	default:
+		return 0, errors.Errorf("Expected a float64, float32 or int bound. Got %T instead", v)
+	}
 }
 
 /* UNARY STUFF */
@@ -381,6 +539,60 @@ func Softplus(a *Node) (retVal *Node, err error) {
 	return unaryOpNode(op, a)
 }
 
+// activation functions
+
+// LeakyRelu performs pointwise leaky ReLU on the input: a where a > 0, and alpha * a elsewhere.
+func LeakyRelu(a *Node, alpha float64) (retVal *Node, err error) {
+	op := newElemUnaryOp(leakyReluOpType, a)
+	op.alpha = alpha
+	return unaryOpNode(op, a)
+}
+
+// Elu performs pointwise ELU on the input: a where a > 0, and exp(a) - 1 elsewhere.
+func Elu(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(eluOpType, a)
+	return unaryOpNode(op, a)
+}
+
+// Selu performs pointwise SELU on the input, which is a scaled ELU: λa where a > 0, and λα(exp(a) - 1) elsewhere,
+// with λ ≈ 1.0507 and α ≈ 1.6733.
+func Selu(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(seluOpType, a)
+	return unaryOpNode(op, a)
+}
+
+// Gelu performs pointwise GELU on the input: aΦ(a), where Φ is the CDF of the standard normal distribution.
+func Gelu(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(geluOpType, a)
+	return unaryOpNode(op, a)
+}
+
+// GeluTanh performs pointwise GELU on the input, using the tanh approximation:
+//		0.5a(1 + tanh(√(2/π)(a + 0.044715a³)))
+func GeluTanh(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(geluTanhOpType, a)
+	return unaryOpNode(op, a)
+}
+
+// Swish performs pointwise swish, also known as SiLU, on the input: a * sigmoid(a).
+func Swish(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(swishOpType, a)
+	return unaryOpNode(op, a)
+}
+
+// Mish performs pointwise mish on the input: a * tanh(softplus(a)).
+func Mish(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(mishOpType, a)
+	return unaryOpNode(op, a)
+}
+
+// HardSigmoid performs pointwise hard sigmoid on the input, which is a piecewise linear approximation of sigmoid:
+// 0 where a <= -3, 1 where a >= 3, and a/6 + 0.5 in between.
+func HardSigmoid(a *Node) (retVal *Node, err error) {
+	op := newElemUnaryOp(hardSigmoidOpType, a)
+	return unaryOpNode(op, a)
+}
+
 /* Aggregate Functions */
 
 // At is a symbolic operation for getting a value at the provided coordinates.
@@ -421,6 +633,66 @@ func Max(a *Node, along ...int) (retVal *Node, err error) {
 	return applyOp(op, a)
 }
 
+// Min performs a min() on the input and the provided axes.
+// The gradient is routed to the elements that are equal to the min. In the case of ties, each of the tied elements get the gradient.
+func Min(a *Node, along ...int) (retVal *Node, err error) {
+	if a.IsScalar() {
+		return a, nil
+	}
+
+	dims := a.Dims()
+	if len(along) == 0 {
+		along = intRange(0, dims)
+	}
+
+	op := newMinOp(along, dims)
+	return applyOp(op, a)
+}
+
+// Prod performs a product of the input along the provided axes. The input may be a Float64, Float32 or Int tensor.
+// The gradient does not divide by the input, so inputs with zeroes are safe to differentiate.
+func Prod(a *Node, along ...int) (retVal *Node, err error) {
+	if a.IsScalar() {
+		return a, nil
+	}
+
+	dims := a.Dims()
+	if len(along) == 0 {
+		along = intRange(0, dims)
+	}
+
+	op := newProdOp(along, dims)
+	return applyOp(op, a)
+}
+
+// Argmax returns the indices of the max values of the input along the provided axis, as a Tensor of Int.
+// Argmax is not differentiable.
+func Argmax(a *Node, axis int) (retVal *Node, err error) {
+	return argmax("Argmax", a, axis, false)
+}
+
+// Argmin returns the indices of the min values of the input along the provided axis, as a Tensor of Int.
+// Argmin is not differentiable.
+func Argmin(a *Node, axis int) (retVal *Node, err error) {
+	return argmax("Argmin", a, axis, true)
+}
+
+func argmax(fn string, a *Node, axis int, min bool) (retVal *Node, err error) {
+	if a.IsScalar() {
+		return nil, errors.Errorf("%v expects a Tensor. Got a scalar instead", fn)
+	}
+	if axis < 0 || axis >= a.Dims() {
+		return nil, errors.Errorf("%v: invalid axis %d for a Tensor of shape %v", fn, axis, a.Shape())
+	}
+
+	op := argmaxOp{
+		along: axis,
+		d:     a.Dims(),
+		min:   min,
+	}
+	return applyOp(op, a)
+}
+
 // Mean performs a mean() on the input and the provided axes.
 func Mean(a *Node, along ...int) (retVal *Node, err error) {
 	if a.IsScalar() {
@@ -476,6 +748,36 @@ func Sum(a *Node, along ...int) (retVal *Node, err error) {
 	return applyOp(op, a)
 }
 
+// Cumsum returns the cumulative sums of a along the axis. The result has the same shape as a.
+// If exclusive is true, each sum leaves out the value at its own index, so the first sum is 0.
+// If reverse is true, the values are summed from the end of the axis instead.
+//
+// The gradient is the cumulative sum of the gradient in the other direction.
+func Cumsum(a *Node, axis int, exclusive, reverse bool) (retVal *Node, err error) {
+	return cumNode("Cumsum", a, axis, exclusive, reverse, false)
+}
+
+// Cumprod returns the cumulative products of a along the axis. The result has the same shape as a.
+// If exclusive is true, each product leaves out the value at its own index, so the first product is 1.
+// If reverse is true, the values are multiplied from the end of the axis instead.
+func Cumprod(a *Node, axis int, exclusive, reverse bool) (retVal *Node, err error) {
+	return cumNode("Cumprod", a, axis, exclusive, reverse, true)
+}
+
+func cumNode(fn string, a *Node, axis int, exclusive, reverse, prod bool) (retVal *Node, err error) {
+	if a.IsScalar() || axis < 0 || axis >= a.Dims() {
+		return nil, errors.Errorf("%v cannot be done along axis %d of a node of shape %v", fn, axis, a.shape)
+	}
+	op := cumOp{
+		along:     axis,
+		d:         a.Dims(),
+		exclusive: exclusive,
+		reverse:   reverse,
+		prod:      prod,
+	}
+	return applyOp(op, a)
+}
+
 // Norm returns the p-norm of a Value. Use p=2 if you want to use unordered norms.
 //
 // This is a simpler version of the norms found in the Tensor package, which specializes and optimizes even more
@@ -675,6 +977,65 @@ func Transpose(n *Node, axes ...int) (retVal *Node, err error) {
 	return applyOp(op, n)
 }
 
+// Reshape reshapes n into the given shape. One of the dimensions may be -1, in which case it is inferred from the size of n.
+// The result shares the data of n unless n is a view that has to be materialized. Reshaping a value of size 1 into an empty shape returns a scalar.
+func Reshape(n *Node, shape ...int) (retVal *Node, err error) {
+	var to tensor.Shape
+	if to, err = inferReshape(n.shape, shape); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return reshape(n, to)
+}
+
+// Squeeze removes the given axes, which have to be of size 1, from the shape of n. If no axes are given, all axes of size 1 are removed.
+func Squeeze(n *Node, axes ...int) (retVal *Node, err error) {
+	squeeze := make([]bool, len(n.shape))
+	for _, a := range axes {
+		if a < 0 || a >= len(n.shape) {
+			return nil, errors.Errorf("Cannot squeeze axis %d of a node of shape %v", a, n.shape)
+		}
+		if n.shape[a] != 1 {
+			return nil, errors.Errorf("Cannot squeeze axis %d of a node of shape %v: its size is not 1", a, n.shape)
+		}
+		squeeze[a] = true
+	}
+
+	to := make(tensor.Shape, 0, len(n.shape))
+	for i, d := range n.shape {
+		if squeeze[i] || (len(axes) == 0 && d == 1) {
+			continue
+		}
+		to = append(to, d)
+	}
+	return reshape(n, to)
+}
+
+// ExpandDims inserts an axis of size 1 into the shape of n at the given position, which ranges from 0 to n.Dims().
+func ExpandDims(n *Node, axis int) (retVal *Node, err error) {
+	if axis < 0 || axis > len(n.shape) {
+		return nil, errors.Errorf("Cannot expand a node of shape %v at axis %d", n.shape, axis)
+	}
+
+	to := make(tensor.Shape, 0, len(n.shape)+1)
+	to = append(to, n.shape[:axis]...)
+	to = append(to, 1)
+	to = append(to, n.shape[axis:]...)
+	return reshape(n, to)
+}
+
+// Flatten reshapes n into a vector. To flatten all but the first (batch) axis, use Reshape(n, batchSize, -1) instead.
+func Flatten(n *Node) (retVal *Node, err error) {
+	return reshape(n, tensor.Shape{shapeSize(n.shape)})
+}
+
+func reshape(n *Node, to tensor.Shape) (retVal *Node, err error) {
+	if sameShape(n.shape, to) {
+		return n, nil
+	}
+	op := reshapeOp{from: n.shape.Clone(), to: to}
+	return applyOp(op, n)
+}
+
 // Concat performs a concatenate on the provided axis and inputs.
 func Concat(axis int, ns ...*Node) (retVal *Node, err error) {
 	// check that all the nodes have the same number of dimensions
@@ -704,3 +1065,260 @@ func Concat(axis int, ns ...*Node) (retVal *Node, err error) {
 	op := concatOp{axis: axis, d: d, children: len(ns)}
 	return applyOp(op, ns...)
 }
+
+// Gather takes the slices of x along the axis at the given indices, like Numpy's take. indices is an Int scalar or tensor,
+// and the result has the shape of x with the axis replaced by the shape of indices.
+// The gradient of x is the gradient of the result scattered back with ScatterAdd, so repeated indices have their gradients summed.
+func Gather(x, indices *Node, axis int) (retVal *Node, err error) {
+	var op gatherOp
+	if op, err = newGatherOp("Gather", x, indices, axis); err != nil {
+		return nil, err
+	}
+	return applyOp(op, x, indices)
+}
+
+// ScatterAdd adds the slices of updates into the slices of into along the axis at the given indices, and returns the result as a new value.
+// indices is an Int scalar or tensor, and updates has the shape of into with the axis replaced by the shape of indices,
+// i.e. the shape of Gather(into, indices, axis). Repeated indices have their updates summed.
+func ScatterAdd(into, indices, updates *Node, axis int) (retVal *Node, err error) {
+	var op gatherOp
+	if op, err = newGatherOp("ScatterAdd", into, indices, axis); err != nil {
+		return nil, err
+	}
+	if err = checkFloatDtype("ScatterAdd", updates); err != nil {
+		return nil, err
+	}
+	if !sameShape(updates.shape, op.shape()) {
+		return nil, errors.Errorf("ScatterAdd expects the updates to be of shape %v. Got %v instead", op.shape(), updates.shape)
+	}
+	return applyOp(scatterAddOp{op}, into, indices, updates)
+}
+
+// TopK returns the k largest values of x along the axis in descending order, and their indices along the axis as an Int node.
+// Both have the shape of x, except that the axis has size k. The values are gathered from x at the indices, so the gradient of
+// the values is scattered back into the positions that they were selected from.
+func TopK(x *Node, k, axis int) (values, indices *Node, err error) {
+	if err = checkFloatDtype("TopK", x); err != nil {
+		return nil, nil, err
+	}
+	if x.IsScalar() || axis < 0 || axis >= x.Dims() {
+		return nil, nil, errors.Errorf("TopK cannot be done along axis %d of a node of shape %v", axis, x.shape)
+	}
+	if k < 1 || k > x.shape[axis] {
+		return nil, nil, errors.Errorf("TopK expects k to be between 1 and %d. Got %d instead", x.shape[axis], k)
+	}
+
+	op := topKOp{k: k, axis: axis, shape: x.shape.Clone()}
+	if indices, err = applyOp(op, x); err != nil {
+		return nil, nil, errors.Wrap(err, applyOpFail)
+	}
+	gather := gatherOp{axis: axis, xShape: x.shape.Clone(), idxShape: op.topShape(), along: true}
+	if values, err = applyOp(gather, x, indices); err != nil {
+		return nil, nil, errors.Wrap(err, applyOpFail)
+	}
+	return values, indices, nil
+}
+
+func newGatherOp(fn string, x, indices *Node, axis int) (op gatherOp, err error) {
+	if err = checkFloatDtype(fn, x); err != nil {
+		return op, err
+	}
+	if x.IsScalar() || axis < 0 || axis >= x.Dims() {
+		return op, errors.Errorf("%v cannot be done along axis %d of a node of shape %v", fn, axis, x.shape)
+	}
+
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(indices.t); err != nil {
+		return op, errors.Wrap(err, dtypeOfFail)
+	}
+	if dt != Int {
+		return op, errors.Errorf("%v expects Int indices. Got %v instead", fn, dt)
+	}
+
+	op = gatherOp{axis: axis, xShape: x.shape.Clone()}
+	if !indices.IsScalar() {
+		op.idxShape = indices.shape.Clone()
+	}
+	return op, nil
+}
+
+// PadMode is the way Pad fills the padding. It is the same as tensor.PadMode.
+type PadMode = tensor.PadMode
+
+// The modes of Pad
+const (
+	PadConstant = tensor.PadConstant // fills the padding with the given value
+	PadReflect  = tensor.PadReflect  // fills the padding with the values mirrored about the edge, without repeating the edge
+	PadEdge     = tensor.PadEdge     // fills the padding with the edge values
+)
+
+// Pad pads x with widths[i][0] values before and widths[i][1] values after each axis i. value is the value of the padding
+// in PadConstant mode, and is ignored in the other modes. In PadReflect mode, the padding of an axis has to be narrower than the axis.
+//
+// The gradient of x is the gradient of the result with the padding sliced off, plus, in the reflect and edge modes, the gradients of
+// the padded values that were copied from x.
+func Pad(x *Node, widths [][2]int, mode PadMode, value float64) (retVal *Node, err error) {
+	if err = checkFloatDtype("Pad", x); err != nil {
+		return nil, err
+	}
+	if x.IsScalar() || len(widths) != x.Dims() {
+		return nil, errors.Errorf("Pad expects %d pairs of widths for a node of shape %v. Got %v instead", x.Dims(), x.shape, widths)
+	}
+	for i, w := range widths {
+		if w[0] < 0 || w[1] < 0 || (mode == PadReflect && (w[0] >= x.shape[i] || w[1] >= x.shape[i])) {
+			return nil, errors.Errorf("Cannot pad axis %d of a node of shape %v by %v in %v mode", i, x.shape, w, mode)
+		}
+	}
+
+	op := padOp{
+		widths: make([][2]int, len(widths)),
+		mode:   mode,
+		value:  value,
+		shape:  x.shape.Clone(),
+	}
+	copy(op.widths, widths)
+	return applyOp(op, x)
+}
+
+// Stack stacks the nodes, which have to be of the same shape, along a new axis. The axis ranges from 0 to the number of dimensions of the nodes.
+// Scalars are stacked into a vector.
+func Stack(axis int, nodes ...*Node) (retVal *Node, err error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("Stack expects at least one node")
+	}
+	for _, n := range nodes {
+		if !sameShape(n.shape, nodes[0].shape) {
+			return nil, errors.Errorf("Stack expects all the nodes to be of shape %v. Got %v instead", nodes[0].shape, n.shape)
+		}
+	}
+	if axis < 0 || axis > nodes[0].Dims() {
+		return nil, errors.Errorf("Cannot stack nodes of shape %v along axis %d", nodes[0].shape, axis)
+	}
+
+	op := stackOp{axis: axis, n: len(nodes)}
+	if !nodes[0].IsScalar() {
+		op.shape = nodes[0].shape.Clone()
+	}
+	return applyOp(op, nodes...)
+}
+
+// Unstack splits x along the axis into x.Shape()[axis] slices, each with the axis removed. It is the inverse of Stack.
+// Unstacking a vector returns scalars.
+func Unstack(x *Node, axis int) (retVal Nodes, err error) {
+	if x.IsScalar() || axis < 0 || axis >= x.Dims() {
+		return nil, errors.Errorf("Cannot unstack a node of shape %v along axis %d", x.shape, axis)
+	}
+
+	retVal = make(Nodes, x.shape[axis])
+	for i := range retVal {
+		if retVal[i], err = applyOp(newSliceOp(S(i), axis, x.Dims()), x); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// Tile repeats the whole of x reps[i] times along each axis i, like Numpy's tile. There has to be one repetition per axis of x.
+func Tile(x *Node, reps ...int) (retVal *Node, err error) {
+	if x.IsScalar() || len(reps) != x.Dims() {
+		return nil, errors.Errorf("Tile expects %d repetitions for a node of shape %v. Got %v instead", x.Dims(), x.shape, reps)
+	}
+
+	// x is reshaped so that each axis is preceded by an axis of size 1, which is repeated.
+	// e.g. tiling a (2, 3) by (4, 5) repeats a (1, 2, 1, 3) into a (4, 2, 5, 3), which is reshaped into a (8, 15)
+	spread := make(tensor.Shape, 0, 2*len(reps))
+	tiled := make(tensor.Shape, len(reps))
+	for i, r := range reps {
+		if r < 1 {
+			return nil, errors.Errorf("Tile expects positive repetitions. Got %v", reps)
+		}
+		spread = append(spread, 1, x.shape[i])
+		tiled[i] = r * x.shape[i]
+	}
+
+	if retVal, err = Reshape(x, spread...); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	for i, r := range reps {
+		if retVal, err = repeatAxis(retVal, 2*i, r); err != nil {
+			return nil, errors.Wrap(err, operationError)
+		}
+	}
+	return Reshape(retVal, tiled...)
+}
+
+// Repeat repeats each element of x along the axis, like Numpy's repeat. Either there is one number of repeats for every element along the axis,
+// or a single number of repeats that is used for all of them.
+func Repeat(x *Node, axis int, repeats ...int) (retVal *Node, err error) {
+	if x.IsScalar() || axis < 0 || axis >= x.Dims() {
+		return nil, errors.Errorf("Cannot repeat a node of shape %v along axis %d", x.shape, axis)
+	}
+	if len(repeats) != 1 && len(repeats) != x.shape[axis] {
+		return nil, errors.Errorf("Repeat expects 1 or %d repeats along axis %d of a node of shape %v. Got %v instead", x.shape[axis], axis, x.shape, repeats)
+	}
+
+	same := true
+	var total int
+	for _, r := range repeats {
+		if r < 0 {
+			return nil, errors.Errorf("Repeat expects non negative repeats. Got %v", repeats)
+		}
+		same = same && r == repeats[0]
+		total += r
+	}
+	if total == 0 {
+		return nil, errors.Errorf("Cannot repeat a node of shape %v by %v into an empty node", x.shape, repeats)
+	}
+
+	if !same {
+		op := repeatEachOp{axis: axis, repeats: make([]int, len(repeats)), shape: x.shape.Clone()}
+		copy(op.repeats, repeats)
+		return applyOp(op, x)
+	}
+
+	// repeating every element the same number of times is a repeat along a new axis of size 1 after the axis.
+	// e.g. repeating a (2, 3) twice along axis 0 repeats a (2, 1, 3) into a (2, 2, 3), which is reshaped into a (4, 3)
+	r := repeats[0]
+	spread := make(tensor.Shape, 0, x.Dims()+1)
+	spread = append(spread, x.shape[:axis+1]...)
+	spread = append(spread, 1)
+	spread = append(spread, x.shape[axis+1:]...)
+	repeated := x.shape.Clone()
+	repeated[axis] *= r
+
+	if retVal, err = Reshape(x, spread...); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	if retVal, err = repeatAxis(retVal, axis+1, r); err != nil {
+		return nil, errors.Wrap(err, operationError)
+	}
+	return Reshape(retVal, repeated...)
+}
+
+// repeatAxis repeats x, whose axis is of size 1, r times along the axis with a repeatOp.
+func repeatAxis(x *Node, axis, r int) (retVal *Node, err error) {
+	if r == 1 {
+		return x, nil
+	}
+
+	var dt tensor.Dtype
+	if dt, err = dtypeOf(x.t); err != nil {
+		return nil, errors.Wrapf(err, "Failed to determine the dtype of %T", x.t)
+	}
+
+	// the repeats are of the same Dtype as x, like the sizes used by Broadcast
+	var rep *Node
+	switch dt {
+	case Float64:
+		rep = NewConstant(float64(r))
+	case Float32:
+		rep = NewConstant(float32(r))
+	case Int:
+		rep = NewConstant(r)
+	default:
+		return nil, errors.Errorf(nyiFail, "Repeat", dt)
+	}
+
+	children := Nodes{x, rep}
+	return applyOp(newKnownRepeatOp(axes{axis}, []int{r}, children), children...)
+}
@@ -2,6 +2,7 @@ package gorgonia
 
 import (
 	"io/ioutil"
+	"math"
 	"runtime"
 	"testing"
 
@@ -344,6 +345,61 @@ func TestGt(t *testing.T) {
 
 }
 
+func TestComparisonsAndLogical(t *testing.T) {
+	assert := assert.New(t)
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewVector(g, Float64, WithShape(4), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float64{0, 1, 2, 3}), tensor.WithShape(4))))
+		y := NewVector(g, Float64, WithShape(4), WithName("y"), WithValue(tensor.New(tensor.WithBacking([]float64{3, 1, 1, 3}), tensor.WithShape(4))))
+		a := NewScalar(g, Float64, WithName("a"), WithValue(2.0))
+
+		lt := Must(Lt(x, y, false))
+		ltSame := Must(Lt(x, y, true))
+		lte := Must(Lte(x, y, false))
+		eq := Must(Eq(x, y, false))
+		ne := Must(Ne(x, y, false))
+		and := Must(And(lte, ne))
+		or := Must(Or(lt, eq))
+		not := Must(Not(eq))
+		scalarNot := Must(Not(Must(Eq(a, a, false))))
+
+		var m VM
+		if i == 0 {
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap)
+		} else {
+			m = NewLispMachine(g, ExecuteFwdOnly())
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		assert.Equal([]bool{true, false, false, false}, lt.Value().Data())
+		assert.Equal([]float64{1, 0, 0, 0}, ltSame.Value().Data())
+		assert.Equal([]bool{true, true, false, true}, lte.Value().Data())
+		assert.Equal([]bool{false, true, false, true}, eq.Value().Data())
+		assert.Equal([]bool{true, false, true, false}, ne.Value().Data())
+		assert.Equal([]bool{true, false, false, false}, and.Value().Data())
+		assert.Equal([]bool{true, true, false, true}, or.Value().Data())
+		assert.Equal([]bool{true, false, true, false}, not.Value().Data())
+		assert.Equal(false, scalarNot.Value().Data())
+	}
+
+	g := NewGraph()
+	x := NewVector(g, Float64, WithShape(4), WithName("x"))
+	b := NewVector(g, Bool, WithShape(4), WithName("b"))
+	c := NewVector(g, Bool, WithShape(3), WithName("c"))
+	if _, err := And(x, b); err == nil {
+		t.Error("Expected an error when And is used on a non Bool")
+	}
+	if _, err := Or(b, c); err == nil {
+		t.Error("Expected an error when Or is used on Bools of different shapes")
+	}
+}
+
 func TestSoftMax(t *testing.T) {
 	defer runtime.GC()
 	assert := assert.New(t)
@@ -405,6 +461,97 @@ func TestSoftMax(t *testing.T) {
 	assert.Equal(xG, x2G)
 }
 
+var activationTests = []struct {
+	name string
+	fn   func(*Node) (*Node, error)
+	f    func(float64) float64
+}{
+	{"leakyRelu(0.2)", func(a *Node) (*Node, error) { return LeakyRelu(a, 0.2) }, func(x float64) float64 { return math.Max(x, 0.2*x) }},
+	{"elu", Elu, func(x float64) float64 { return math.Max(x, math.Exp(math.Min(x, 0))-1) }},
+	{"selu", Selu, func(x float64) float64 {
+		if x > 0 {
+			return 1.0507009873554805 * x
+		}
+		return 1.0507009873554805 * 1.6732632423543772 * (math.Exp(x) - 1)
+	}},
+	{"gelu", Gelu, func(x float64) float64 { return 0.5 * x * (1 + math.Erf(x/math.Sqrt2)) }},
+	{"geluTanh", GeluTanh, func(x float64) float64 {
+		return 0.5 * x * (1 + math.Tanh(math.Sqrt(2/math.Pi)*(x+0.044715*x*x*x)))
+	}},
+	{"swish", Swish, func(x float64) float64 { return x / (1 + math.Exp(-x)) }},
+	{"mish", Mish, func(x float64) float64 { return x * math.Tanh(math.Log(1+math.Exp(x))) }},
+	{"hardSigmoid", HardSigmoid, func(x float64) float64 { return math.Min(math.Max(x/6+0.5, 0), 1) }},
+}
+
+func TestActivations(t *testing.T) {
+	assert := assert.New(t)
+	xBack := []float64{-4, -2.5, -1, -0.3, 0.4, 1.2, 2.7, 5}
+	for _, at := range activationTests {
+		correct := make([]float64, len(xBack))
+		correctGrad := make([]float64, len(xBack))
+		h := 1e-6
+		for i, v := range xBack {
+			correct[i] = at.f(v)
+			correctGrad[i] = (at.f(v+h) - at.f(v-h)) / (2 * h)
+		}
+
+		for i := 0; i < 2; i++ {
+			for _, dt := range []tensor.Dtype{Float64, Float32} {
+				g := NewGraph()
+				xT, err := valueFromFloat64s(dt, tensor.Shape{len(xBack)}, xBack)
+				if err != nil {
+					t.Fatal(err)
+				}
+				x := NewVector(g, dt, WithShape(len(xBack)), WithName("x"), WithValue(xT))
+				y, err := at.fn(x)
+				if err != nil {
+					t.Fatalf("%v: %+v", at.name, err)
+				}
+				cost := Must(Sum(y))
+
+				var m VM
+				if i == 0 {
+					if _, err = Grad(cost, x); err != nil {
+						t.Fatalf("%v: %+v", at.name, err)
+					}
+					prog, locMap, err := Compile(g)
+					if err != nil {
+						t.Fatalf("%v: %+v", at.name, err)
+					}
+					m = NewTapeMachine(prog, locMap, BindDualValues())
+				} else {
+					m = NewLispMachine(g)
+				}
+				if err = m.RunAll(); err != nil {
+					t.Fatalf("%v: %+v", at.name, err)
+				}
+
+				delta := 1e-6
+				if dt == Float32 {
+					delta = 1e-3
+				}
+				// the tape machine may reuse the register of y when computing the gradients
+				if i == 1 {
+					ys, _ := float64sOf(y.Value())
+					assert.InDeltaSlice(correct, ys, delta, "%v %v", at.name, dt)
+				}
+				grad, err := x.Grad()
+				if err != nil {
+					t.Fatalf("%v: %+v", at.name, err)
+				}
+				grads, _ := float64sOf(grad)
+				assert.InDeltaSlice(correctGrad, grads, delta, "%v %v", at.name, dt)
+			}
+		}
+	}
+
+	// leaky ReLUs with different slopes are different nodes
+	g := NewGraph()
+	x := NewVector(g, Float64, WithShape(2), WithName("x"))
+	assert.NotEqual(Must(LeakyRelu(x, 0.1)), Must(LeakyRelu(x, 0.2)))
+	assert.Equal(Must(LeakyRelu(x, 0.1)), Must(LeakyRelu(x, 0.1)))
+}
+
 var sliceTests = []struct {
 	name   string
 	shape  tensor.Shape
@@ -698,3 +845,279 @@ func TestMean(t *testing.T) {
 		t.Error("Expected result to be scalar")
 	}
 }
+
+func TestWhere(t *testing.T) {
+	assert := assert.New(t)
+	inf := math.Inf(1)
+
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		cond := NewMatrix(g, Bool, WithShape(2, 3), WithName("cond"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]bool{true, false, true, false, true, true}))))
+		a := NewMatrix(g, Float64, WithShape(2, 3), WithName("a"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{1, inf, 3, -inf, 5, 6}))))
+		b := NewMatrix(g, Float64, WithShape(2, 3), WithName("b"), WithValue(tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{10, 20, 30, 40, 50, 60}))))
+
+		// a 0/1 condition that is broadcast, and a scalar branch
+		mask := NewVector(g, Float64, WithShape(3), WithName("mask"), WithValue(tensor.New(tensor.WithShape(3), tensor.WithBacking([]float64{1, 0, 1}))))
+		c := NewScalar(g, Float64, WithName("c"), WithValue(7.0))
+
+		z1 := Must(Where(cond, a, b))
+		z2 := Must(Where(mask, b, c))
+		assert.Equal(tensor.Shape{2, 3}, z2.Shape())
+		cost := Must(Add(Must(Sum(z1)), Must(Sum(z2))))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, a, b, c); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		// the tape machine may reuse the registers of z1 and z2 when computing the gradients
+		if i == 1 {
+			assert.Equal([]float64{1, 20, 3, 40, 5, 6}, extractF64s(z1.Value()))
+			assert.Equal([]float64{10, 7, 30, 40, 7, 60}, extractF64s(z2.Value()))
+		}
+		assert.Equal(1+20+3+40+5+6+10+7+30+40+7+60.0, extractF64(cost.Value()))
+
+		ga, _ := a.Grad()
+		gb, _ := b.Grad()
+		gc, _ := c.Grad()
+		assert.Equal([]float64{1, 0, 1, 0, 1, 1}, extractF64s(ga))
+		assert.Equal([]float64{1, 1, 1, 2, 0, 1}, extractF64s(gb))
+		assert.Equal(2.0, extractF64(gc))
+	}
+
+	g := NewGraph()
+	cond := NewVector(g, Bool, WithShape(2), WithName("cond"))
+	a := NewMatrix(g, Float64, WithShape(2, 3), WithName("a"))
+	if _, err := Where(cond, a, a); err == nil {
+		t.Error("Expected an error when cond cannot be broadcast to the shape of a")
+	}
+	ints := NewVector(g, Int, WithShape(3), WithName("ints"))
+	if _, err := Where(cond, ints, ints); err == nil {
+		t.Error("Expected an error for Int branches")
+	}
+}
+
+func TestClamp(t *testing.T) {
+	assert := assert.New(t)
+
+	for i := 0; i < 2; i++ {
+		g := NewGraph()
+		x := NewVector(g, Float64, WithShape(6), WithName("x"), WithValue(tensor.New(tensor.WithBacking([]float64{-3, -1, 0, 0.5, 1, 4}))))
+		xst := NewVector(g, Float64, WithShape(6), WithName("xst"), WithValue(tensor.New(tensor.WithBacking([]float64{-3, -1, 0, 0.5, 1, 4}))))
+		s := NewScalar(g, Float64, WithName("s"), WithValue(5.0))
+		w := NewConstant(tensor.New(tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6})))
+
+		c := Must(Clamp(x, -1, 1.0))
+		cst := Must(ClampStraightThrough(xst, float32(-1), 1))
+		cs := Must(Clamp(s, 0, 2))
+		cost := Must(Add(Must(Sum(Must(HadamardProd(c, w)))), cs))
+		cost = Must(Add(cost, Must(Sum(Must(HadamardProd(cst, w))))))
+
+		var m VM
+		if i == 0 {
+			if _, err := Grad(cost, x, xst, s); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			prog, locMap, err := Compile(g)
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			m = NewTapeMachine(prog, locMap, BindDualValues())
+		} else {
+			m = NewLispMachine(g)
+		}
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		// the tape machine may reuse the registers of the clamps when computing the gradients
+		if i == 1 {
+			assert.Equal([]float64{-1, -1, 0, 0.5, 1, 1}, extractF64s(c.Value()))
+			assert.Equal([]float64{-1, -1, 0, 0.5, 1, 1}, extractF64s(cst.Value()))
+			assert.Equal(2.0, extractF64(cs.Value()))
+		}
+
+		gx, _ := x.Grad()
+		gxst, _ := xst.Grad()
+		gs, _ := s.Grad()
+		assert.Equal([]float64{0, 2, 3, 4, 5, 0}, extractF64s(gx))
+		assert.Equal([]float64{1, 2, 3, 4, 5, 6}, extractF64s(gxst))
+		assert.Equal(0.0, extractF64(gs))
+	}
+
+	g := NewGraph()
+	x32 := NewVector(g, Float32, WithShape(4), WithName("x32"), WithValue(tensor.New(tensor.WithBacking([]float32{-3, 0.25, 1.5, 4}))))
+	c32 := Must(Clamp(x32, 0, 1.5))
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal([]float32{0, 0.25, 1.5, 1.5}, c32.Value().Data())
+
+	x := NewVector(g, Float64, WithShape(2), WithName("x"))
+	if _, err := Clamp(x, 1, -1); err == nil {
+		t.Error("Expected an error when min > max")
+	}
+	if _, err := Clamp(x, "a", 1); err == nil {
+		t.Error("Expected an error for a string bound")
+	}
+	ints := NewVector(g, Int, WithShape(2), WithName("ints"))
+	if _, err := Clamp(ints, 0, 1); err == nil {
+		t.Error("Expected an error for Ints")
+	}
+}
+
+// naiveBatchedMatMul is the reference for BatchedMatMul
+func naiveBatchedMatMul(a, b []float64, as, bs tensor.Shape, transA, transB bool) []float64 {
+	batch, m, k, n := as[0], as[1], as[2], bs[2]
+	if transA {
+		m, k = k, m
+	}
+	if transB {
+		n = bs[1]
+	}
+	at := func(i, r, c int) float64 {
+		if transA {
+			r, c = c, r
+		}
+		return a[i*as[1]*as[2]+r*as[2]+c]
+	}
+	bt := func(i, r, c int) float64 {
+		if transB {
+			r, c = c, r
+		}
+		return b[i*bs[1]*bs[2]+r*bs[2]+c]
+	}
+
+	retVal := make([]float64, batch*m*n)
+	for i := 0; i < batch; i++ {
+		for r := 0; r < m; r++ {
+			for c := 0; c < n; c++ {
+				var sum float64
+				for j := 0; j < k; j++ {
+					sum += at(i, r, j) * bt(i, j, c)
+				}
+				retVal[i*m*n+r*n+c] = sum
+			}
+		}
+	}
+	return retVal
+}
+
+func TestBatchedMatMul(t *testing.T) {
+	assert := assert.New(t)
+	// cost is the reference for Sum(z ⊙ c)
+	cost := func(a, b, c []float64, as, bs tensor.Shape, transA, transB bool) (retVal float64) {
+		for i, v := range naiveBatchedMatMul(a, b, as, bs, transA, transB) {
+			retVal += v * c[i]
+		}
+		return
+	}
+	// numGrad is the numerical gradient of cost wrt x, which is either a or b
+	numGrad := func(x []float64, f func() float64) []float64 {
+		const h = 1e-4
+		retVal := make([]float64, len(x))
+		for i := range x {
+			orig := x[i]
+			x[i] = orig + h
+			fp := f()
+			x[i] = orig - h
+			fm := f()
+			x[i] = orig
+			retVal[i] = (fp - fm) / (2 * h)
+		}
+		return retVal
+	}
+
+	for _, trans := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+		transA, transB := trans[0], trans[1]
+		as, bs := tensor.Shape{2, 3, 4}, tensor.Shape{2, 4, 5}
+		if transA {
+			as = tensor.Shape{2, 4, 3}
+		}
+		if transB {
+			bs = tensor.Shape{2, 5, 4}
+		}
+		aBack := tensor.Range(tensor.Float64, 0, as.TotalSize()).([]float64)
+		bBack := tensor.Range(tensor.Float64, 0, bs.TotalSize()).([]float64)
+		cBack := make([]float64, 2*3*5)
+		for i := range cBack {
+			cBack[i] = float64(i%7) - 3
+		}
+		correct := naiveBatchedMatMul(aBack, bBack, as, bs, transA, transB)
+		correctCost := cost(aBack, bBack, cBack, as, bs, transA, transB)
+		correctGradA := numGrad(aBack, func() float64 { return cost(aBack, bBack, cBack, as, bs, transA, transB) })
+		correctGradB := numGrad(bBack, func() float64 { return cost(aBack, bBack, cBack, as, bs, transA, transB) })
+
+		for i := 0; i < 2; i++ {
+			g := NewGraph()
+			a := NewTensor(g, Float64, 3, WithShape(as...), WithName("a"), WithValue(tensor.New(tensor.WithShape(as...), tensor.WithBacking(aBack))))
+			b := NewTensor(g, Float64, 3, WithShape(bs...), WithName("b"), WithValue(tensor.New(tensor.WithShape(bs...), tensor.WithBacking(bBack))))
+			c := NewConstant(tensor.New(tensor.WithShape(2, 3, 5), tensor.WithBacking(cBack)), WithName("c"))
+			z := Must(BatchedMatMul(a, b, transA, transB))
+			assert.Equal(tensor.Shape{2, 3, 5}, z.Shape())
+			sum := Must(Sum(Must(HadamardProd(z, c))))
+
+			var m VM
+			if i == 0 {
+				if _, err := Grad(sum, a, b); err != nil {
+					t.Fatalf("%v: %+v", trans, err)
+				}
+				prog, locMap, err := Compile(g)
+				if err != nil {
+					t.Fatalf("%v: %+v", trans, err)
+				}
+				m = NewTapeMachine(prog, locMap, BindDualValues())
+			} else {
+				m = NewLispMachine(g)
+			}
+			if err := m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", trans, err)
+			}
+
+			if i == 1 {
+				assert.Equal(correct, extractF64s(z.Value()), "%v", trans)
+			}
+			assert.Equal(correctCost, extractF64(sum.Value()), "%v", trans)
+
+			ga, _ := a.Grad()
+			gb, _ := b.Grad()
+			assert.Equal(as, ga.Shape(), "%v", trans)
+			assert.Equal(bs, gb.Shape(), "%v", trans)
+			assert.InDeltaSlice(correctGradA, extractF64s(ga), 1e-6, "%v", trans)
+			assert.InDeltaSlice(correctGradB, extractF64s(gb), 1e-6, "%v", trans)
+		}
+	}
+
+	// Float32, via Mul
+	g := NewGraph()
+	a := NewTensor(g, Float32, 3, WithShape(2, 2, 2), WithName("a"), WithValue(tensor.New(tensor.WithShape(2, 2, 2), tensor.WithBacking([]float32{1, 2, 3, 4, 5, 6, 7, 8}))))
+	b := NewTensor(g, Float32, 3, WithShape(2, 2, 1), WithName("b"), WithValue(tensor.New(tensor.WithShape(2, 2, 1), tensor.WithBacking([]float32{1, 1, 2, 0}))))
+	z := Must(Mul(a, b))
+	m := NewLispMachine(g, ExecuteFwdOnly())
+	if err := m.RunAll(); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	assert.Equal(tensor.Shape{2, 2, 1}, z.Value().Shape())
+	assert.Equal([]float32{3, 7, 10, 14}, z.Value().Data())
+
+	c := NewTensor(g, Float32, 3, WithShape(3, 2, 2), WithName("c"))
+	if _, err := BatchedMatMul(a, c, false, false); err == nil {
+		t.Error("Expected an error for mismatched batch sizes")
+	}
+	if _, err := BatchedMatMul(a, b, false, true); err == nil {
+		t.Error("Expected an error for mismatched inner dimensions")
+	}
+}
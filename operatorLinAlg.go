@@ -12,10 +12,11 @@ import (
 type āBinaryOperator byte
 
 const (
-	matMulOperator    āBinaryOperator = iota // emits S/DGEMM BLAS calls
-	matVecMulOperator                        // emits S/DGEMV BLAS calls
-	vecDotOperator                           // emits S/DDOT BLAS calls
-	outerProdOperator                        // emits S/DGER BLAS calls
+	matMulOperator        āBinaryOperator = iota // emits S/DGEMM BLAS calls
+	matVecMulOperator                            // emits S/DGEMV BLAS calls
+	vecDotOperator                               // emits S/DDOT BLAS calls
+	outerProdOperator                            // emits S/DGER BLAS calls
+	batchedMatMulOperator                        // emits S/DGEMM BLAS calls, one for each matrix in the batch
 
 	maxĀBinaryOperator // delimits all possible linalg operators. Add above this line
 )
@@ -328,3 +329,75 @@ func outerProdDiff(transA, transB bool, x, y, z *Node) (err error) {
 	}
 	return
 }
+
+// batchedMatMulGrad describes a batched matrix multiplication that computes a gradient of a batched matrix multiplication z = x × y.
+// a and b index the operands: 0 is x, 1 is y and 2 is the gradient of z.
+type batchedMatMulGrad struct {
+	a, b           int
+	transA, transB bool
+}
+
+// batchedMatMulGrads returns the batched matrix multiplications that compute the gradients wrt x and y.
+// These are the same as the ones of matMulDiffExpr, done on every matrix of the batch.
+func batchedMatMulGrads(transA, transB bool) (dzdx, dzdy batchedMatMulGrad) {
+	switch {
+	case transA && transB:
+		// z = xᵀyᵀ: dx = yᵀgᵀ, dy = gᵀxᵀ
+		return batchedMatMulGrad{1, 2, true, true}, batchedMatMulGrad{2, 0, true, true}
+	case !transA && transB:
+		// z = xyᵀ: dx = gy, dy = gᵀx
+		return batchedMatMulGrad{2, 1, false, false}, batchedMatMulGrad{2, 0, true, false}
+	case transA && !transB:
+		// z = xᵀy: dx = ygᵀ, dy = xg
+		return batchedMatMulGrad{1, 2, false, true}, batchedMatMulGrad{0, 2, false, false}
+	default:
+		// z = xy: dx = gyᵀ, dy = xᵀg
+		return batchedMatMulGrad{2, 1, false, true}, batchedMatMulGrad{0, 2, true, false}
+	}
+}
+
+func batchedMatMulDiffExpr(transA, transB bool, x, y, z, gradZ *Node) (retVal Nodes, err error) {
+	operands := Nodes{x, y, gradZ}
+	dzdx, dzdy := batchedMatMulGrads(transA, transB)
+
+	retVal = make(Nodes, 2)
+	for i, d := range []batchedMatMulGrad{dzdx, dzdy} {
+		op := linAlgBinOp{
+			āBinaryOperator: batchedMatMulOperator,
+			transA:          d.transA,
+			transB:          d.transB,
+		}
+		if retVal[i], err = binOpNode(op, operands[d.a], operands[d.b]); err != nil {
+			return nil, errors.Wrapf(err, binOpNodeFail, op)
+		}
+	}
+	return
+}
+
+func batchedMatMulDiff(transA, transB bool, x, y, z *Node) (err error) {
+	xdv := x.boundTo.(*dualValue)
+	ydv := y.boundTo.(*dualValue)
+	zdv := z.boundTo.(*dualValue)
+
+	operands := []Value{xdv.Value, ydv.Value, zdv.d}
+	dzdx, dzdy := batchedMatMulGrads(transA, transB)
+	for i, d := range []batchedMatMulGrad{dzdx, dzdy} {
+		dv := xdv
+		if i == 1 {
+			dv = ydv
+		}
+
+		op := linAlgBinOp{
+			āBinaryOperator: batchedMatMulOperator,
+			transA:          d.transA,
+			transB:          d.transB,
+		}
+		err = op.IncrDo(dv.d, operands[d.a], operands[d.b])
+		if ver, ok := err.(Valuer); ok {
+			dv.SetDeriv(ver.Value()) // ignore errors on purpose
+		} else if err != nil {
+			return
+		}
+	}
+	return nil
+}
@@ -9,7 +9,7 @@ var āBinOpStrs = [maxĀBinaryOperator]string{
 	"×",
 	"⋅",
 	"⊗",
-	// "×××",
+	"×××",
 }
 
 var āBinOpDiffExprs = [maxĀBinaryOperator]func(tA, tB bool, x, y, z, grad *Node) (Nodes, error){
@@ -17,6 +17,7 @@ var āBinOpDiffExprs = [maxĀBinaryOperator]func(tA, tB bool, x, y, z, grad *Nod
 	matVecMulDiffExpr,
 	vecDotDiffExpr,
 	outerProdDiffExpr,
+	batchedMatMulDiffExpr,
 }
 
 var āBinOpDiffs = [maxĀBinaryOperator]func(tA, tB bool, x, y, z *Node) error{
@@ -24,6 +25,7 @@ var āBinOpDiffs = [maxĀBinaryOperator]func(tA, tB bool, x, y, z *Node) error{
 	matVecMulDiff,
 	vecDotDiff,
 	outerProdDiff,
+	batchedMatMulDiff,
 }
 
 var āBinOpTypes = [maxĀBinaryOperator]func() hm.Type{
@@ -31,6 +33,7 @@ var āBinOpTypes = [maxĀBinaryOperator]func() hm.Type{
 	matVecMulType,
 	vecDotType,
 	outerProdType,
+	batchedMatMulType,
 }
 
 /* TYPES FOR LINALG BINARY OP*/
@@ -80,3 +83,14 @@ func outerProdType() hm.Type {
 
 	return hm.NewFnType(v, v, m)
 }
+
+// batchedMatMulOp is a function with this type:
+//		batchedMatMulOp :: (Float a) ⇒ Tensor-3 a → Tensor-3 a → Tensor-3 a
+//
+// For the moment only floats are allowed
+func batchedMatMulType() hm.Type {
+	a := hm.TypeVariable('a')
+	t := newTensorType(3, a)
+
+	return hm.NewFnType(t, t, t)
+}
@@ -65,6 +65,22 @@ func (f *sf32UnaryOperator) unaryOpType() ʘUnaryOperatorType {
 		return expm1OpType
 	case &softplusf32:
 		return softplusOpType
+	case &leakyReluf32:
+		return leakyReluOpType
+	case &eluf32:
+		return eluOpType
+	case &seluf32:
+		return seluOpType
+	case &geluf32:
+		return geluOpType
+	case &geluTanhf32:
+		return geluTanhOpType
+	case &swishf32:
+		return swishOpType
+	case &mishf32:
+		return mishOpType
+	case &hardSigmoidf32:
+		return hardSigmoidOpType
 	}
 	return maxʘUnaryOperator
 }
@@ -113,6 +129,22 @@ func (f *sf64UnaryOperator) unaryOpType() ʘUnaryOperatorType {
 		return expm1OpType
 	case &softplusf64:
 		return softplusOpType
+	case &leakyReluf64:
+		return leakyReluOpType
+	case &eluf64:
+		return eluOpType
+	case &seluf64:
+		return seluOpType
+	case &geluf64:
+		return geluOpType
+	case &geluTanhf64:
+		return geluTanhOpType
+	case &swishf64:
+		return swishOpType
+	case &mishf64:
+		return mishOpType
+	case &hardSigmoidf64:
+		return hardSigmoidOpType
 	}
 
 	return maxʘUnaryOperator
@@ -924,3 +956,47 @@ func softplusDiff(x, y *Node) (err error) {
 	}
 	return
 }
+
+// activationDiffExpr returns the differentiation expression of the given activation function.
+// The derivative is computed by an activationDiffOp, which is then multiplied with gradY.
+func activationDiffExpr(which ʘUnaryOperatorType) func(x, y, gradY *Node) (*Node, error) {
+	return func(x, y, gradY *Node) (retVal *Node, err error) {
+		op := newActivationDiffOp(which, y)
+		if retVal, err = applyOp(op, x); err == nil {
+			WithGroupName(gradClust)(retVal)
+			retVal, err = HadamardProd(retVal, gradY)
+			if err != nil {
+				return nil, errors.Wrap(err, hadamardProdFail)
+			}
+			return
+		}
+		return nil, errors.Wrap(err, applyOpFail)
+	}
+}
+
+// activationDiff returns the function that differentiates the given activation function on the dual values of x and y.
+func activationDiff(which ʘUnaryOperatorType) func(x, y *Node) error {
+	return func(x, y *Node) (err error) {
+		xdv := x.boundTo.(*dualValue)
+		ydv := y.boundTo.(*dualValue)
+
+		op := newActivationDiffOp(which, y)
+
+		var d Value
+		if d, err = op.Do(xdv.Value); err != nil {
+			return errors.Wrapf(err, doFail, op)
+		}
+
+		if dT, ok := d.(tensor.Tensor); ok {
+			defer returnTensor(dT)
+		}
+
+		mul := newElemBinOp(mulOpType, x, y)
+		err = mul.IncrDo(xdv.d, d, ydv.d)
+		if ver, ok := err.(Valuer); ok {
+			xdv.SetDeriv(ver.Value()) // ignore errors on purpose
+			return nil
+		}
+		return
+	}
+}
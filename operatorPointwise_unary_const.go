@@ -39,6 +39,16 @@ var (
 	// softplus isn't necessarily only a numerical stabilization op
 	// (you can use it elsewhere), but I included it under numerical optimization
 
+	// modern activation functions
+	leakyReluf64   = sf64UnaryOperator(_leakyReluf64)
+	eluf64         = sf64UnaryOperator(_eluf64)
+	seluf64        = sf64UnaryOperator(_seluf64)
+	geluf64        = sf64UnaryOperator(_geluf64)
+	geluTanhf64    = sf64UnaryOperator(_geluTanhf64)
+	swishf64       = sf64UnaryOperator(_swishf64)
+	mishf64        = sf64UnaryOperator(_mishf64)
+	hardSigmoidf64 = sf64UnaryOperator(_hardSigmoidf64)
+
 	/* Float32 */
 
 	// non differentiable
@@ -67,6 +77,16 @@ var (
 	log1pf32    = sf32UnaryOperator(math32.Log1p)
 	expm1f32    = sf32UnaryOperator(math32.Expm1)
 	softplusf32 = sf32UnaryOperator(_softplusf32)
+
+	// modern activation functions
+	leakyReluf32   = sf32UnaryOperator(_leakyReluf32)
+	eluf32         = sf32UnaryOperator(_eluf32)
+	seluf32        = sf32UnaryOperator(_seluf32)
+	geluf32        = sf32UnaryOperator(_geluf32)
+	geluTanhf32    = sf32UnaryOperator(_geluTanhf32)
+	swishf32       = sf32UnaryOperator(_swishf32)
+	mishf32        = sf32UnaryOperator(_mishf32)
+	hardSigmoidf32 = sf32UnaryOperator(_hardSigmoidf32)
 )
 
 type ʘUnaryOperatorType byte
@@ -98,6 +118,16 @@ const (
 	expm1OpType
 	softplusOpType
 
+	// modern activation functions
+	leakyReluOpType // the slope of the negative part defaults to 0.01
+	eluOpType
+	seluOpType
+	geluOpType
+	geluTanhOpType // tanh approximation of gelu
+	swishOpType    // also known as SiLU
+	mishOpType
+	hardSigmoidOpType
+
 	maxʘUnaryOperator // delimits end of all possible unary ops
 )
 
@@ -118,6 +148,9 @@ var ʘUnaryOpStrs = [maxʘUnaryOperator]string{
 	"inv", "cube", "tanh", "sigmoid",
 
 	"log1p", "expm1", "softplus",
+
+	"leakyRelu", "elu", "selu", "gelu",
+	"geluTanh", "swish", "mish", "hardSigmoid",
 }
 
 // ʘUnaryOpDifferentiable is the array of whether a unary operator is differentiable
@@ -129,6 +162,9 @@ var ʘUnaryOpDifferentiable = [maxʘUnaryOperator]bool{
 	true, true, true, true,
 
 	true, true, true,
+
+	true, true, true, true,
+	true, true, true, true,
 }
 
 var ʘUnaryOpDiffExprs = [maxʘUnaryOperator]func(x, y, gradY *Node) (*Node, error){
@@ -138,6 +174,9 @@ var ʘUnaryOpDiffExprs = [maxʘUnaryOperator]func(x, y, gradY *Node) (*Node, err
 	inverseDiffExpr, cubeDiffExpr, tanhDiffExpr, sigmoidDiffExpr,
 
 	log1pDiffExpr, expm1DiffExpr, softplusDiffExpr,
+
+	activationDiffExpr(leakyReluOpType), activationDiffExpr(eluOpType), activationDiffExpr(seluOpType), activationDiffExpr(geluOpType),
+	activationDiffExpr(geluTanhOpType), activationDiffExpr(swishOpType), activationDiffExpr(mishOpType), activationDiffExpr(hardSigmoidOpType),
 }
 
 var ʘUnaryOpDiffFns = [maxʘUnaryOperator]func(x, y *Node) error{
@@ -147,6 +186,9 @@ var ʘUnaryOpDiffFns = [maxʘUnaryOperator]func(x, y *Node) error{
 	inverseDiff, cubeDiff, tanhDiff, sigmoidDiff,
 
 	log1pDiff, expm1Diff, softplusDiff,
+
+	activationDiff(leakyReluOpType), activationDiff(eluOpType), activationDiff(seluOpType), activationDiff(geluOpType),
+	activationDiff(geluTanhOpType), activationDiff(swishOpType), activationDiff(mishOpType), activationDiff(hardSigmoidOpType),
 }
 
 var sf64UnaryOperators = [maxʘUnaryOperator]*sf64UnaryOperator{
@@ -170,6 +212,15 @@ var sf64UnaryOperators = [maxʘUnaryOperator]*sf64UnaryOperator{
 	&log1pf64,
 	&expm1f64,
 	&softplusf64,
+
+	&leakyReluf64,
+	&eluf64,
+	&seluf64,
+	&geluf64,
+	&geluTanhf64,
+	&swishf64,
+	&mishf64,
+	&hardSigmoidf64,
 }
 
 var sf32UnaryOperators = [maxʘUnaryOperator]*sf32UnaryOperator{
@@ -193,4 +244,13 @@ var sf32UnaryOperators = [maxʘUnaryOperator]*sf32UnaryOperator{
 	&log1pf32,
 	&expm1f32,
 	&softplusf32,
+
+	&leakyReluf32,
+	&eluf32,
+	&seluf32,
+	&geluf32,
+	&geluTanhf32,
+	&swishf32,
+	&mishf32,
+	&hardSigmoidf32,
 }
@@ -301,3 +301,25 @@ func TestSoftplusDiff(t *testing.T) {
 	correct0 := sigmoidf64(-v)
 	assert.Equal([]float64{correct0, correct}, xdvd.Data())
 }
+
+func TestActivationDiff(t *testing.T) {
+	assert := assert.New(t)
+	for op := leakyReluOpType; op <= hardSigmoidOpType; op++ {
+		v, x, _, xT, _, err := unaryOpDiffTest(op)
+		if err != nil {
+			t.Errorf("%v: %v", op, err)
+			continue
+		}
+
+		// central differences of the forward function
+		fn := *(sf64UnaryOperators[op])
+		h := 1e-6
+		numDiff := func(v float64) float64 { return (fn(v+h) - fn(v-h)) / (2 * h) }
+
+		assert.InDelta(numDiff(v), extractF64(x.boundTo.(*dualValue).d), 1e-6, "%v", op)
+
+		// Tensor edition
+		xdvd := xT.boundTo.(*dualValue).d
+		assert.InDeltaSlice([]float64{numDiff(-v), numDiff(v)}, extractF64s(xdvd), 1e-6, "%v", op)
+	}
+}
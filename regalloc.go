@@ -244,6 +244,27 @@ func buildIntervals(sorted Nodes) map[*Node]*interval {
 		iv.fix()
 	}
 
+	// a view and its input share their data, so both are live until the last use of either of them
+	for changed := true; changed; {
+		changed = false
+		for _, n := range sorted {
+			if _, ok := n.op.(viewOp); !ok {
+				continue
+			}
+			view, in := intervals[n], intervals[n.children[0]]
+			switch {
+			case view.end > in.end:
+				in.addUsePositions(view.end)
+				in.fix()
+				changed = true
+			case in.end > view.end:
+				view.addUsePositions(in.end)
+				view.fix()
+				changed = true
+			}
+		}
+	}
+
 	return intervals
 }
 
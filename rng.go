@@ -0,0 +1,77 @@
+package gorgonia
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/leesper/go_rng"
+)
+
+// defaultRNG is the RNG used by the package level weight initialization functions.
+var defaultRNG = NewRNG(time.Now().UnixNano())
+
+// DefaultRNG returns the RNG that the package level weight initialization functions (such as GlorotU or Gaussian64) draw from.
+// It is seeded by the time. Seed it to get reproducible weights from the package level functions:
+//		DefaultRNG().Seed(1337)
+//		w := NewMatrix(g, Float64, WithShape(2, 2), WithInit(GlorotU(1)))
+func DefaultRNG() *RNG { return defaultRNG }
+
+// RNG is a seedable source of randomness. The random Ops of an *ExprGraph (random nodes, dropout) draw from the RNG of the graph,
+// and the weight initialization methods of an RNG draw from it. Two runs that start from the same seed and draw in the same order
+// produce identical values.
+//
+// Example Usage:
+//		g := NewGraph(WithGraphSeed(1337))
+//		w := NewMatrix(g, Float64, WithShape(2, 2), WithInit(g.RNG().GlorotU(1)))
+//
+// An RNG is safe for concurrent use, but concurrent draws happen in no particular order.
+type RNG struct {
+	mu  sync.Mutex
+	src *rand.Rand
+}
+
+// NewRNG creates a new RNG with the given seed.
+func NewRNG(seed int64) *RNG {
+	return &RNG{src: rand.New(rand.NewSource(seed))}
+}
+
+// Seed resets the RNG to the state given by the seed.
+func (r *RNG) Seed(seed int64) {
+	r.mu.Lock()
+	r.src.Seed(seed)
+	r.mu.Unlock()
+}
+
+// next draws the seed of a generator. Every draw of a random Op or an initialization function gets its own generator,
+// which is seeded from the RNG.
+func (r *RNG) next() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.src.Int63()
+}
+
+func (r *RNG) uniform() *rng.UniformGenerator   { return rng.NewUniformGenerator(r.next()) }
+func (r *RNG) gaussian() *rng.GaussianGenerator { return rng.NewGaussianGenerator(r.next()) }
+func (r *RNG) binomial() *rng.BinomialGenerator { return rng.NewBinomialGenerator(r.next()) }
+
+// WithGraphSeed is a ExprGraph construction option that seeds the RNG of the graph. Without it, the RNG is seeded by the time.
+// The package level weight initialization functions do not draw from the RNG of the graph, but from DefaultRNG. Use the methods
+// of the RNG of the graph instead, e.g. WithInit(g.RNG().GlorotU(1)), or seed DefaultRNG as well.
+func WithGraphSeed(seed int64) graphconopt {
+	f := func(g *ExprGraph) {
+		g.rng = NewRNG(seed)
+	}
+	return f
+}
+
+// WithRNG is a ExprGraph construction option that sets the RNG of the graph. Graphs may share an RNG.
+func WithRNG(r *RNG) graphconopt {
+	f := func(g *ExprGraph) {
+		g.rng = r
+	}
+	return f
+}
+
+// RNG returns the RNG that the random Ops of the graph draw from.
+func (g *ExprGraph) RNG() *RNG { return g.rng }
@@ -0,0 +1,74 @@
+package gorgonia
+
+import (
+	"testing"
+
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRNG(t *testing.T) {
+	assert := assert.New(t)
+	draw := func(r *RNG) [][]float64 {
+		f32 := r.Uniform32(-1, 1, 4)
+		f32s := make([]float64, len(f32))
+		for i, v := range f32 {
+			f32s[i] = float64(v)
+		}
+		return [][]float64{
+			r.Gaussian64(0, 1, 2, 3),
+			f32s,
+			r.Binomial64(1500, 0.5, 3),
+			r.GlorotEtAlU64(1, 3, 2),
+			r.HeEtAlN64(1, 2, 2),
+			r.Gaussian(0, 1)(Float64, 5).([]float64),
+		}
+	}
+
+	r := NewRNG(1337)
+	first := draw(r)
+	assert.Equal(first, draw(NewRNG(1337)), "RNGs with the same seed should draw the same values")
+	assert.NotEqual(first, draw(r), "an RNG should not repeat itself")
+	r.Seed(1337)
+	assert.Equal(first, draw(r), "reseeding should restart the RNG")
+	assert.NotEqual(first, draw(NewRNG(1338)))
+
+	// the package level functions draw from DefaultRNG
+	DefaultRNG().Seed(1337)
+	w := GlorotU(1)(Float64, 3, 2).([]float64)
+	DefaultRNG().Seed(1337)
+	assert.Equal(w, GlorotEtAlU64(1, 3, 2))
+	assert.Equal(w, NewRNG(1337).GlorotU(1)(Float64, 3, 2))
+}
+
+func TestWithGraphSeed(t *testing.T) {
+	assert := assert.New(t)
+	run := func(g *ExprGraph) [][]float64 {
+		w := NewMatrix(g, Float64, WithShape(3, 4), WithName("w"), WithInit(g.RNG().GlorotN(1)))
+		x := NewMatrix(g, Float64, WithShape(3, 4), WithName("x"), WithValue(tensor.Ones(Float64, 3, 4)))
+		u := UniformRandomNode(g, Float64, -1, 1, 3, 4)
+		n := GaussianRandomNode(g, Float64, 0, 1)
+		b := BinomialRandomNode(g, Float64, 10, 0.5, 4)
+		d := Must(Dropout(x, 0.5))
+
+		m := NewLispMachine(g, ExecuteFwdOnly())
+		if err := m.RunAll(); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		return [][]float64{
+			extractF64s(w.Value()),
+			extractF64s(u.Value()),
+			{extractF64(n.Value())},
+			extractF64s(b.Value()),
+			extractF64s(d.Value()),
+		}
+	}
+
+	first := run(NewGraph(WithGraphSeed(1337)))
+	assert.Equal(first, run(NewGraph(WithGraphSeed(1337))), "graphs with the same seed should draw the same values")
+	assert.NotEqual(first, run(NewGraph(WithGraphSeed(1338))))
+
+	r := NewRNG(1337)
+	assert.Equal(first, run(NewGraph(WithRNG(r))))
+	assert.NotEqual(first, run(NewGraph(WithRNG(r))), "graphs sharing an RNG should draw from the same stream")
+}
@@ -1,6 +1,9 @@
 package gorgonia
 
-import "github.com/chewxy/gorgonia/tensor"
+import (
+	"github.com/chewxy/gorgonia/tensor"
+	"github.com/pkg/errors"
+)
 
 var scalarShape = tensor.ScalarShape()
 
@@ -15,3 +18,95 @@ func transpose2D(shape tensor.Shape) tensor.Shape {
 
 	return tensor.Shape{shape[1], shape[0]}
 }
+
+// axisGroups splits the elements of a tensor of the given shape into groups along the given axes, as a reduction along the axes would.
+// For each element, it returns the index of the group (i.e. the index in the reduced tensor), and the index of the element within its group.
+func axisGroups(shape tensor.Shape, along []int) (groups, within []int, nGroups, groupSize int) {
+	reduced := make([]bool, len(shape))
+	nGroups, groupSize = 1, 1
+	for _, a := range along {
+		reduced[a] = true
+	}
+	for i, s := range shape {
+		if reduced[i] {
+			groupSize *= s
+		} else {
+			nGroups *= s
+		}
+	}
+
+	size := shape.TotalSize()
+	groups = make([]int, size)
+	within = make([]int, size)
+	coord := make([]int, len(shape))
+	for i := 0; i < size; i++ {
+		var g, p int
+		for d, c := range coord {
+			if reduced[d] {
+				p = p*shape[d] + c
+			} else {
+				g = g*shape[d] + c
+			}
+		}
+		groups[i], within[i] = g, p
+
+		// increment the coordinates, row major
+		for d := len(coord) - 1; d >= 0; d-- {
+			coord[d]++
+			if coord[d] < shape[d] {
+				break
+			}
+			coord[d] = 0
+		}
+	}
+	return
+}
+
+// shapeSize is the number of elements of a value of the given shape. Unlike Shape.TotalSize(), a scalar shape has a size of 1.
+func shapeSize(s tensor.Shape) int {
+	size := 1
+	for _, d := range s {
+		size *= d
+	}
+	return size
+}
+
+// inferReshape returns the shape that a value of shape s is reshaped into. At most one of the dimensions may be -1,
+// in which case it is inferred from the size of s.
+func inferReshape(s tensor.Shape, dims []int) (retVal tensor.Shape, err error) {
+	size := shapeSize(s)
+	retVal = make(tensor.Shape, len(dims))
+	infer := -1
+	known := 1
+	for i, d := range dims {
+		switch {
+		case d == -1 && infer < 0:
+			infer = i
+			continue
+		case d == -1:
+			return nil, errors.Errorf("Cannot reshape %v into %v: only one dimension can be inferred", s, dims)
+		case d <= 0:
+			return nil, errors.Errorf("Cannot reshape %v into %v: invalid dimension %d", s, dims, d)
+		}
+		retVal[i] = d
+		known *= d
+	}
+
+	if infer >= 0 {
+		if size%known != 0 {
+			return nil, errors.Errorf("Cannot reshape %v into %v: the size %d is not divisible by %d", s, dims, size, known)
+		}
+		retVal[infer] = size / known
+		known = size
+	}
+	if known != size {
+		return nil, errors.Errorf("Cannot reshape %v of size %d into %v", s, size, dims)
+	}
+	return retVal, nil
+}
+
+// axisLayout describes a value of shape s as a (outer, n, inner) tensor, where n is the size of the axis,
+// and outer and inner are the sizes of the axes before and after it.
+func axisLayout(s tensor.Shape, axis int) (outer, n, inner int) {
+	return shapeSize(s[:axis]), s[axis], shapeSize(s[axis+1:])
+}
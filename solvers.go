@@ -2,6 +2,7 @@ package gorgonia
 
 import (
 	"math"
+	"sort"
 
 	"github.com/chewxy/gorgonia/tensor"
 	"github.com/chewxy/math32"
@@ -197,6 +198,14 @@ func (s *RMSPropSolver) Step(model Nodes) (err error) {
 			s.cache[i] = cached
 		}
 
+		var sparse *rowSparse
+		if sparse, err = gatherRowSparse(n, dv, cached); err != nil {
+			return errors.Wrap(err, gatherRowsFail)
+		}
+		if sparse != nil {
+			dv, cached = sparse.dv, sparse.cached
+		}
+
 		grad := dv.d
 		weights := dv.Value
 
@@ -318,6 +327,11 @@ func (s *RMSPropSolver) Step(model Nodes) (err error) {
 			dv.d = zero(Float64)
 		default:
 		}
+		if sparse != nil {
+			if err = sparse.scatter(); err != nil {
+				return errors.Wrap(err, scatterRowsFail)
+			}
+		}
 		solverLogf("AFTER (%v): %+1.1s", n, n.boundTo)
 	}
 	return nil
@@ -392,6 +406,14 @@ func (s *AdamSolver) Step(model Nodes) (err error) {
 			s.cache[i] = cached
 		}
 
+		var sparse *rowSparse
+		if sparse, err = gatherRowSparse(n, dv, cached); err != nil {
+			return errors.Wrap(err, gatherRowsFail)
+		}
+		if sparse != nil {
+			dv, cached = sparse.dv, sparse.cached
+		}
+
 		grad := dv.d
 		weights := dv.Value
 
@@ -660,6 +682,11 @@ func (s *AdamSolver) Step(model Nodes) (err error) {
 			err = errors.Errorf(nyiTypeFail, "AdamSolver", cvm)
 			return
 		}
+		if sparse != nil {
+			if err = sparse.scatter(); err != nil {
+				return errors.Wrap(err, scatterRowsFail)
+			}
+		}
 
 	}
 	return
@@ -698,6 +725,14 @@ func (s *VanillaSolver) Step(model Nodes) (err error) {
 			return errors.Errorf("Expected a *dualValue in %v (%x). Got %T instead", n, n.Hashcode(), n.boundTo)
 		}
 
+		var sparse *rowSparse
+		if sparse, err = gatherRowSparse(n, dv, nil); err != nil {
+			return errors.Wrap(err, gatherRowsFail)
+		}
+		if sparse != nil {
+			dv = sparse.dv
+		}
+
 		grad := dv.d
 		weights := dv.Value
 
@@ -856,6 +891,11 @@ func (s *VanillaSolver) Step(model Nodes) (err error) {
 		default:
 			return errors.Errorf(nyiFail, "VanillaSolver.step", w)
 		}
+		if sparse != nil {
+			if err = sparse.scatter(); err != nil {
+				return errors.Wrap(err, scatterRowsFail)
+			}
+		}
 	}
 	return
 }
@@ -908,6 +948,14 @@ func (s *AdaGradSolver) Step(model Nodes) (err error) {
 			s.cache[i] = cached
 		}
 
+		var sparse *rowSparse
+		if sparse, err = gatherRowSparse(n, dv, cached); err != nil {
+			return errors.Wrap(err, gatherRowsFail)
+		}
+		if sparse != nil {
+			dv, cached = sparse.dv, sparse.cached
+		}
+
 		grad := dv.d
 		weights := dv.Value
 
@@ -1058,8 +1106,170 @@ func (s *AdaGradSolver) Step(model Nodes) (err error) {
 		default:
 			return errors.Errorf(nyiFail, "Adagrad step", cv)
 		}
+		if sparse != nil {
+			if err = sparse.scatter(); err != nil {
+				return errors.Wrap(err, scatterRowsFail)
+			}
+		}
+
+	}
+
+	return
+}
+
+// rowSparse holds the rows of a model node (and its solver cache) that have a non-zero gradient.
+//
+// Tables that are only used by Embedding have row sparse gradients. Instead of updating the entire table,
+// the solvers gather the rows that were looked up, apply their update to the gathered rows,
+// and scatter the results back into the table. Rows that were not looked up are left untouched - including
+// their regularization and the solver's cached statistics.
+type rowSparse struct {
+	rows []int
+
+	dv, cached         *dualValue // the gathered rows
+	origDV, origCached *dualValue
+}
+
+// gatherRowSparse gathers the rows of dv and cached (which may be nil) that have a non-zero gradient.
+// If n does not have a row sparse gradient, nil is returned.
+func gatherRowSparse(n *Node, dv, cached *dualValue) (retVal *rowSparse, err error) {
+	rows := sparseRows(n)
+	if rows == nil {
+		return nil, nil
+	}
+	if _, ok := dv.Value.(*tensor.Dense); !ok {
+		return nil, nil
+	}
+
+	retVal = &rowSparse{
+		rows:       rows,
+		origDV:     dv,
+		origCached: cached,
+	}
+	if retVal.dv, err = gatherDualValueRows(dv, rows); err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if retVal.cached, err = gatherDualValueRows(cached, rows); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// scatter writes the gathered rows back into the model node and the solver cache
+func (rs *rowSparse) scatter() (err error) {
+	if err = scatterDualValueRows(rs.origDV, rs.dv, rs.rows); err != nil {
+		return
+	}
+	if rs.origCached != nil {
+		return scatterDualValueRows(rs.origCached, rs.cached, rs.rows)
+	}
+	return nil
+}
+
+// sparseRows returns the rows of the gradient of n that may be non-zero. This is the case if n is only used as the table of Embedding.
+// A nil slice is returned if the gradient of n is dense.
+func sparseRows(n *Node) (retVal []int) {
+	if n.g == nil || n.Dims() != 2 {
+		return nil
+	}
+	parents := graphNodeToNode(n.g.To(n))
+	if len(parents) == 0 {
+		return nil
+	}
+
+	set := make(map[int]struct{})
+	for _, p := range parents {
+		if _, ok := p.op.(embeddingOp); !ok || p.children[0] != n || p.children[1] == n {
+			return nil
+		}
+		idx, err := intsOf(p.children[1].Value())
+		if err != nil {
+			return nil
+		}
+		for _, r := range idx {
+			set[r] = struct{}{}
+		}
+	}
 
+	retVal = make([]int, 0, len(set))
+	for r := range set {
+		retVal = append(retVal, r)
 	}
+	sort.Ints(retVal)
+	return
+}
 
+func gatherDualValueRows(dv *dualValue, rows []int) (retVal *dualValue, err error) {
+	retVal = new(dualValue)
+	if retVal.Value, err = gatherRows(dv.Value, rows); err != nil {
+		return nil, err
+	}
+	if retVal.d, err = gatherRows(dv.d, rows); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func scatterDualValueRows(dst, src *dualValue, rows []int) (err error) {
+	if err = scatterRows(dst.Value, src.Value, rows); err != nil {
+		return
+	}
+	return scatterRows(dst.d, src.d, rows)
+}
+
+// gatherRows copies the given rows of a matrix into a new matrix.
+func gatherRows(v Value, rows []int) (retVal *tensor.Dense, err error) {
+	var t *tensor.Dense
+	if t, err = contiguousDense(v); err != nil {
+		return
+	}
+	cols := t.Shape()[1]
+	switch data := t.Data().(type) {
+	case []float64:
+		backing := make([]float64, len(rows)*cols)
+		for i, r := range rows {
+			copy(backing[i*cols:(i+1)*cols], data[r*cols:(r+1)*cols])
+		}
+		retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(len(rows), cols))
+	case []float32:
+		backing := make([]float32, len(rows)*cols)
+		for i, r := range rows {
+			copy(backing[i*cols:(i+1)*cols], data[r*cols:(r+1)*cols])
+		}
+		retVal = tensor.New(tensor.WithBacking(backing), tensor.WithShape(len(rows), cols))
+	default:
+		err = errors.Errorf(nyiTypeFail, "gatherRows", data)
+	}
+	return
+}
+
+// scatterRows copies the rows of src into the given rows of dst.
+func scatterRows(dst, src Value, rows []int) (err error) {
+	d, ok := dst.(*tensor.Dense)
+	if !ok {
+		return errors.Errorf(nyiTypeFail, "scatterRows", dst)
+	}
+	var s *tensor.Dense
+	if s, err = contiguousDense(src); err != nil {
+		return
+	}
+
+	cols := d.Shape()[1]
+	switch data := d.Data().(type) {
+	case []float64:
+		sdata := s.Data().([]float64)
+		for i, r := range rows {
+			copy(data[r*cols:(r+1)*cols], sdata[i*cols:(i+1)*cols])
+		}
+	case []float32:
+		sdata := s.Data().([]float32)
+		for i, r := range rows {
+			copy(data[r*cols:(r+1)*cols], sdata[i*cols:(i+1)*cols])
+		}
+	default:
+		err = errors.Errorf(nyiTypeFail, "scatterRows", data)
+	}
 	return
 }
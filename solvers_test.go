@@ -153,3 +153,61 @@ func TestRMSPropSolver(t *testing.T) {
 	manualRMSProp32(t, s, model)
 
 }
+
+func TestSolversRowSparse(t *testing.T) {
+	assert := assert.New(t)
+	solvers := []struct {
+		name string
+		fn   func() Solver
+	}{
+		{"Vanilla", func() Solver { return NewVanillaSolver(WithLearnRate(0.1), WithL2Reg(0.01)) }},
+		{"Adam", func() Solver { return NewAdamSolver(WithLearnRate(0.1), WithL2Reg(0.01)) }},
+		{"RMSProp", func() Solver { return NewRMSPropSolver(WithLearnRate(0.1), WithL2Reg(0.01)) }},
+		{"AdaGrad", func() Solver { return NewAdaGradSolver(WithLearnRate(0.1), WithL2Reg(0.01)) }},
+	}
+
+	looked := []int{0, 1, 3}
+	for _, sol := range solvers {
+		g := NewGraph()
+		tableBack := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+		table := NewMatrix(g, Float64, WithShape(4, 3), WithName("table"), WithValue(tensor.New(tensor.WithBacking(tableBack), tensor.WithShape(4, 3))))
+		indices := NewVector(g, Int, WithShape(4), WithName("indices"), WithValue(tensor.New(tensor.WithBacking([]int{1, 3, 1, 0}), tensor.WithShape(4))))
+		Must(Sum(Must(Square(Must(Embedding(table, indices))))))
+		if _, err := Grad(g.Roots()[0], table); err != nil {
+			t.Fatalf("%v: %+v", sol.name, err)
+		}
+		prog, locMap, err := Compile(g)
+		if err != nil {
+			t.Fatalf("%v: %+v", sol.name, err)
+		}
+		m := NewTapeMachine(prog, locMap, BindDualValues())
+
+		// the reference is a dense model that only holds the rows that were looked up
+		ref := new(Node)
+		refV, _ := gatherRows(table.Value(), looked)
+		ref.boundTo = dvUnit0(refV)
+
+		s, refS := sol.fn(), sol.fn()
+		for i := 0; i < 3; i++ {
+			if err = m.RunAll(); err != nil {
+				t.Fatalf("%v: %+v", sol.name, err)
+			}
+			grad, _ := table.Grad()
+			ref.boundTo.(*dualValue).d, _ = gatherRows(grad, looked)
+
+			if err = s.Step(Nodes{table}); err != nil {
+				t.Fatalf("%v: %+v", sol.name, err)
+			}
+			if err = refS.Step(Nodes{ref}); err != nil {
+				t.Fatalf("%v: %+v", sol.name, err)
+			}
+			m.Reset()
+
+			got, _ := gatherRows(table.Value(), looked)
+			assert.True(floatsEqual64(extractF64s(ref.Value()), extractF64s(got)), "%v: %v vs %v", sol.name, ref.Value(), got)
+			assert.Equal([]float64{6, 7, 8}, extractF64s(table.Value())[6:9], "%v: rows that were not looked up should not be updated", sol.name)
+			grad, _ = table.Grad()
+			assert.Equal(make([]float64, 12), extractF64s(grad), sol.name)
+		}
+	}
+}
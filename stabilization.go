@@ -9,6 +9,7 @@ func init() {
 	unaryOpStabilizationFns[lnOpType] = []func(*Node) (*Node, error){logSigmoidStabilization, logStabilization}
 	binOpStabilizationFns[subOpType] = []func(*Node, *Node) (*Node, error){expStabilization}
 	unaryOpStabilizationFns[log1pOpType] = []func(*Node) (*Node, error){log1pExpStabilization, log1pNegSigmoidStabilization}
+	binOpStabilizationFns[mulOpType] = []func(*Node, *Node) (*Node, error){swishStabilization}
 }
 
 // logStabilization converts log(1+a) and log(a+1) to log1p(a) and log(1-a) to log1p(-a)
@@ -182,6 +183,22 @@ func log1pNegSigmoidStabilization(a *Node) (retVal *Node, err error) {
 	return nil, errors.Wrap(err, softplusFail)
 }
 
+// swishStabilization fuses x*sigmoid(x) and sigmoid(x)*x into swish(x)
+// place before mul
+func swishStabilization(a, b *Node) (retVal *Node, err error) {
+	stabLogf("Fusing x*sigmoid(x) of %v and %v", a, b)
+	enterLoggingContext()
+	defer leaveLoggingContext()
+
+	if euo, ok := b.op.(elemUnaryOp); ok && euo.unaryOpType() == sigmoidOpType && b.children[0] == a {
+		return Swish(a)
+	}
+	if euo, ok := a.op.(elemUnaryOp); ok && euo.unaryOpType() == sigmoidOpType && a.children[0] == b {
+		return Swish(b)
+	}
+	return nil, noStabilizationErr{}
+}
+
 /* Graph Optimizations */
 
 // NegNegOptimization optimizes away -(-x) to just return x
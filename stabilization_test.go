@@ -104,3 +104,18 @@ func TestLogSigmoidStabilization(t *testing.T) {
 		ioutil.WriteFile("logY.dot", []byte(logY.ToDot()), 0644)
 	}
 }
+
+func TestSwishStabilization(t *testing.T) {
+	g := NewGraph()
+	x := NewVector(g, Float64, WithName("x"), WithShape(2))
+	s := Must(Sigmoid(x))
+	for _, xs := range []*Node{Must(HadamardProd(x, s)), Must(HadamardProd(s, x))} {
+		if euo, ok := xs.op.(elemUnaryOp); !ok || euo.unaryOpType() != swishOpType {
+			t.Errorf("Expected x*sigmoid(x) to be fused into swish(x). Got %v instead", xs.op)
+			continue
+		}
+		if xs.children[0] != x {
+			t.Error("Expected swish to be applied on x")
+		}
+	}
+}
@@ -44,6 +44,15 @@ func Concat(axis int, t Tensor, others ...Tensor) (retVal Tensor, err error) {
 	panic("Unreachable")
 }
 
+// Pad pads a Tensor along each axis, filling the padding according to the mode. See (*Dense).Pad for details.
+func Pad(t Tensor, widths [][2]int, mode PadMode, value interface{}) (retVal Tensor, err error) {
+	switch T := t.(type) {
+	case *Dense:
+		return T.Pad(widths, mode, value)
+	}
+	panic("Unreachable")
+}
+
 // Copy copies a tensor to another. For *Dense views, only the relevant slots are copied.
 func Copy(dst, src Tensor) error {
 	switch st := src.(type) {
@@ -26,3 +26,21 @@ func Argmin(t Tensor, axis int) (retVal Tensor, err error) {
 	}
 	panic("Unreachable")
 }
+
+// Cumsum returns the cumulative sums of a Tensor along the axis. See (*Dense).Cumsum for the flags.
+func Cumsum(t Tensor, axis int, exclusive, reverse bool) (retVal Tensor, err error) {
+	switch T := t.(type) {
+	case *Dense:
+		return T.Cumsum(axis, exclusive, reverse)
+	}
+	panic("Unreachable")
+}
+
+// Cumprod returns the cumulative products of a Tensor along the axis. See (*Dense).Cumprod for the flags.
+func Cumprod(t Tensor, axis int, exclusive, reverse bool) (retVal Tensor, err error) {
+	switch T := t.(type) {
+	case *Dense:
+		return T.Cumprod(axis, exclusive, reverse)
+	}
+	panic("Unreachable")
+}
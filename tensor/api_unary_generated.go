@@ -744,3 +744,1248 @@ func Sign(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
 		return nil, errors.Errorf(typeNYI, "Clamp", a)
 	}
 }
+
+// the constants of SELU, from Klambauer et al. (2017) - Self-Normalizing Neural Networks
+const (
+	seluLambda = 1.0507009873554804934193349852946
+	seluAlpha  = 1.6732632423543772848170429916717
+)
+
+const (
+	sqrt2         = 1.41421356237309504880168872420969808
+	sqrt2OverPi   = 0.79788456080286535587989211986876373 // √(2/π)
+	invSqrt2Pi    = 0.39894228040143267793994605993438186 // 1/√(2π)
+	geluTanhCoeff = 0.044715
+)
+
+func leakyReluF32(x, alpha float32) float32 {
+	if x > 0 {
+		return x
+	}
+	return alpha * x
+}
+
+func leakyReluF64(x, alpha float64) float64 {
+	if x > 0 {
+		return x
+	}
+	return alpha * x
+}
+
+// LeakyRelu performs the leaky ReLU on each element of the Tensor: x where x > 0, and alpha·x elsewhere.
+// Only float Tensors are supported. The alpha provided must be the same type as the Tensor type.
+// Incr is not supported (it doesn't make sense anyway)
+func LeakyRelu(a Tensor, alphaVal interface{}, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("LeakyRelu only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				var alpha float32
+				var ok bool
+				if alpha, ok = alphaVal.(float32); !ok {
+					err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyRelu() alpha")
+					return
+				}
+				f = func(x float32) float32 { return leakyReluF32(x, alpha) }
+			case reflect.Float64:
+				var alpha float64
+				var ok bool
+				if alpha, ok = alphaVal.(float64); !ok {
+					err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyRelu() alpha")
+					return
+				}
+				f = func(x float64) float64 { return leakyReluF64(x, alpha) }
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "LeakyRelu")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			var alpha float32
+			var ok bool
+			if alpha, ok = alphaVal.(float32); !ok {
+				err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyRelu() alpha")
+				return
+			}
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = leakyReluF32(v, alpha)
+			}
+		case reflect.Float64:
+			var alpha float64
+			var ok bool
+			if alpha, ok = alphaVal.(float64); !ok {
+				err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyRelu() alpha")
+				return
+			}
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = leakyReluF64(v, alpha)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "LeakyRelu", a)
+	}
+}
+
+func eluF32(x float32) float32 {
+	if x > 0 {
+		return x
+	}
+	return math32.Expm1(x)
+}
+
+func eluF64(x float64) float64 {
+	if x > 0 {
+		return x
+	}
+	return math.Expm1(x)
+}
+
+// Elu performs the ELU on each element of the Tensor: x where x > 0, and exp(x) - 1 elsewhere.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func Elu(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("Elu only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = eluF32
+			case reflect.Float64:
+				f = eluF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "Elu")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = eluF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = eluF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "Elu", a)
+	}
+}
+
+func seluF32(x float32) float32 {
+	if x > 0 {
+		return seluLambda * x
+	}
+	return seluLambda * seluAlpha * math32.Expm1(x)
+}
+
+func seluF64(x float64) float64 {
+	if x > 0 {
+		return seluLambda * x
+	}
+	return seluLambda * seluAlpha * math.Expm1(x)
+}
+
+// Selu performs the SELU on each element of the Tensor, which is a scaled ELU: λx where x > 0, and λα(exp(x) - 1) elsewhere.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func Selu(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("Selu only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = seluF32
+			case reflect.Float64:
+				f = seluF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "Selu")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = seluF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = seluF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "Selu", a)
+	}
+}
+
+func geluF32(x float32) float32 {
+	// erfc is used instead of erf, as it is accurate for large negative x
+	return 0.5 * x * math32.Erfc(-x/sqrt2)
+}
+
+func geluF64(x float64) float64 {
+	// erfc is used instead of erf, as it is accurate for large negative x
+	return 0.5 * x * math.Erfc(-x/sqrt2)
+}
+
+// Gelu performs the GELU on each element of the Tensor: xΦ(x), where Φ is the CDF of the standard normal distribution.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func Gelu(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("Gelu only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = geluF32
+			case reflect.Float64:
+				f = geluF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "Gelu")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = geluF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = geluF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "Gelu", a)
+	}
+}
+
+func geluTanhF32(x float32) float32 {
+	return 0.5 * x * (1 + math32.Tanh(sqrt2OverPi*(x+geluTanhCoeff*x*x*x)))
+}
+
+func geluTanhF64(x float64) float64 {
+	return 0.5 * x * (1 + math.Tanh(sqrt2OverPi*(x+geluTanhCoeff*x*x*x)))
+}
+
+// GeluTanh performs the tanh approximation of the GELU on each element of the Tensor: 0.5x(1 + tanh(√(2/π)(x + 0.044715x³))).
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func GeluTanh(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("GeluTanh only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = geluTanhF32
+			case reflect.Float64:
+				f = geluTanhF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "GeluTanh")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = geluTanhF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = geluTanhF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "GeluTanh", a)
+	}
+}
+
+func swishF32(x float32) float32 {
+	return x / (1 + math32.Exp(-x))
+}
+
+func swishF64(x float64) float64 {
+	return x / (1 + math.Exp(-x))
+}
+
+// Swish performs the swish, also known as SiLU, on each element of the Tensor: x·sigmoid(x).
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func Swish(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("Swish only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = swishF32
+			case reflect.Float64:
+				f = swishF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "Swish")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = swishF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = swishF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "Swish", a)
+	}
+}
+
+func mishF32(x float32) float32 {
+	return x * math32.Tanh(math32.Log1p(math32.Exp(x)))
+}
+
+func mishF64(x float64) float64 {
+	return x * math.Tanh(math.Log1p(math.Exp(x)))
+}
+
+// Mish performs the mish on each element of the Tensor: x·tanh(softplus(x)).
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func Mish(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("Mish only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = mishF32
+			case reflect.Float64:
+				f = mishF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "Mish")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = mishF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = mishF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "Mish", a)
+	}
+}
+
+func hardSigmoidF32(x float32) float32 {
+	switch {
+	case x <= -3:
+		return 0
+	case x >= 3:
+		return 1
+	}
+	return x/6 + 0.5
+}
+
+func hardSigmoidF64(x float64) float64 {
+	switch {
+	case x <= -3:
+		return 0
+	case x >= 3:
+		return 1
+	}
+	return x/6 + 0.5
+}
+
+// HardSigmoid performs the hard sigmoid, a piecewise linear approximation of sigmoid, on each element of the Tensor: 0 where x <= -3, 1 where x >= 3, and x/6 + 0.5 in between.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func HardSigmoid(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("HardSigmoid only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = hardSigmoidF32
+			case reflect.Float64:
+				f = hardSigmoidF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "HardSigmoid")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = hardSigmoidF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = hardSigmoidF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "HardSigmoid", a)
+	}
+}
+
+func leakyReluDiffF32(x, alpha float32) float32 {
+	if x > 0 {
+		return 1
+	}
+	return alpha
+}
+
+func leakyReluDiffF64(x, alpha float64) float64 {
+	if x > 0 {
+		return 1
+	}
+	return alpha
+}
+
+// LeakyReluDiff computes the derivative of LeakyRelu at each element of the Tensor.
+// Only float Tensors are supported. The alpha provided must be the same type as the Tensor type.
+// Incr is not supported (it doesn't make sense anyway)
+func LeakyReluDiff(a Tensor, alphaVal interface{}, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("LeakyReluDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				var alpha float32
+				var ok bool
+				if alpha, ok = alphaVal.(float32); !ok {
+					err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyReluDiff() alpha")
+					return
+				}
+				f = func(x float32) float32 { return leakyReluDiffF32(x, alpha) }
+			case reflect.Float64:
+				var alpha float64
+				var ok bool
+				if alpha, ok = alphaVal.(float64); !ok {
+					err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyReluDiff() alpha")
+					return
+				}
+				f = func(x float64) float64 { return leakyReluDiffF64(x, alpha) }
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "LeakyReluDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			var alpha float32
+			var ok bool
+			if alpha, ok = alphaVal.(float32); !ok {
+				err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyReluDiff() alpha")
+				return
+			}
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = leakyReluDiffF32(v, alpha)
+			}
+		case reflect.Float64:
+			var alpha float64
+			var ok bool
+			if alpha, ok = alphaVal.(float64); !ok {
+				err = errors.Wrapf(errors.Errorf(typeMismatch, alpha, alphaVal), "LeakyReluDiff() alpha")
+				return
+			}
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = leakyReluDiffF64(v, alpha)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "LeakyReluDiff", a)
+	}
+}
+
+func eluDiffF32(x float32) float32 {
+	if x > 0 {
+		return 1
+	}
+	return math32.Exp(x)
+}
+
+func eluDiffF64(x float64) float64 {
+	if x > 0 {
+		return 1
+	}
+	return math.Exp(x)
+}
+
+// EluDiff computes the derivative of Elu at each element of the Tensor.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func EluDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("EluDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = eluDiffF32
+			case reflect.Float64:
+				f = eluDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "EluDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = eluDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = eluDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "EluDiff", a)
+	}
+}
+
+func seluDiffF32(x float32) float32 {
+	if x > 0 {
+		return seluLambda
+	}
+	return seluLambda * seluAlpha * math32.Exp(x)
+}
+
+func seluDiffF64(x float64) float64 {
+	if x > 0 {
+		return seluLambda
+	}
+	return seluLambda * seluAlpha * math.Exp(x)
+}
+
+// SeluDiff computes the derivative of Selu at each element of the Tensor.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func SeluDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("SeluDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = seluDiffF32
+			case reflect.Float64:
+				f = seluDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "SeluDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = seluDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = seluDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "SeluDiff", a)
+	}
+}
+
+func geluDiffF32(x float32) float32 {
+	return 0.5*math32.Erfc(-x/sqrt2) + x*invSqrt2Pi*math32.Exp(-0.5*x*x)
+}
+
+func geluDiffF64(x float64) float64 {
+	return 0.5*math.Erfc(-x/sqrt2) + x*invSqrt2Pi*math.Exp(-0.5*x*x)
+}
+
+// GeluDiff computes the derivative of Gelu at each element of the Tensor: Φ(x) + xφ(x).
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func GeluDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("GeluDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = geluDiffF32
+			case reflect.Float64:
+				f = geluDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "GeluDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = geluDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = geluDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "GeluDiff", a)
+	}
+}
+
+func geluTanhDiffF32(x float32) float32 {
+	t := math32.Tanh(sqrt2OverPi * (x + geluTanhCoeff*x*x*x))
+	return 0.5*(1+t) + 0.5*x*(1-t*t)*sqrt2OverPi*(1+3*geluTanhCoeff*x*x)
+}
+
+func geluTanhDiffF64(x float64) float64 {
+	t := math.Tanh(sqrt2OverPi * (x + geluTanhCoeff*x*x*x))
+	return 0.5*(1+t) + 0.5*x*(1-t*t)*sqrt2OverPi*(1+3*geluTanhCoeff*x*x)
+}
+
+// GeluTanhDiff computes the derivative of GeluTanh at each element of the Tensor.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func GeluTanhDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("GeluTanhDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = geluTanhDiffF32
+			case reflect.Float64:
+				f = geluTanhDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "GeluTanhDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = geluTanhDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = geluTanhDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "GeluTanhDiff", a)
+	}
+}
+
+func swishDiffF32(x float32) float32 {
+	s := 1 / (1 + math32.Exp(-x))
+	return s * (1 + x*(1-s))
+}
+
+func swishDiffF64(x float64) float64 {
+	s := 1 / (1 + math.Exp(-x))
+	return s * (1 + x*(1-s))
+}
+
+// SwishDiff computes the derivative of Swish at each element of the Tensor: σ(x)(1 + x(1 - σ(x))).
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func SwishDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("SwishDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = swishDiffF32
+			case reflect.Float64:
+				f = swishDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "SwishDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = swishDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = swishDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "SwishDiff", a)
+	}
+}
+
+func mishDiffF32(x float32) float32 {
+	t := math32.Tanh(math32.Log1p(math32.Exp(x)))
+	s := 1 / (1 + math32.Exp(-x))
+	return t + x*(1-t*t)*s
+}
+
+func mishDiffF64(x float64) float64 {
+	t := math.Tanh(math.Log1p(math.Exp(x)))
+	s := 1 / (1 + math.Exp(-x))
+	return t + x*(1-t*t)*s
+}
+
+// MishDiff computes the derivative of Mish at each element of the Tensor: tanh(softplus(x)) + x·sech²(softplus(x))·σ(x).
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func MishDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("MishDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = mishDiffF32
+			case reflect.Float64:
+				f = mishDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "MishDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = mishDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = mishDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "MishDiff", a)
+	}
+}
+
+func hardSigmoidDiffF32(x float32) float32 {
+	if x > -3 && x < 3 {
+		return 1.0 / 6
+	}
+	return 0
+}
+
+func hardSigmoidDiffF64(x float64) float64 {
+	if x > -3 && x < 3 {
+		return 1.0 / 6
+	}
+	return 0
+}
+
+// HardSigmoidDiff computes the derivative of HardSigmoid at each element of the Tensor.
+// Only float Tensors are supported.
+// Incr is not supported (it doesn't make sense anyway)
+func HardSigmoidDiff(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("HardSigmoidDiff only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			case reflect.Float32:
+				f = hardSigmoidDiffF32
+			case reflect.Float64:
+				f = hardSigmoidDiffF64
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "HardSigmoidDiff")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		case reflect.Float32:
+			data := ret.float32s()
+			for i, v := range data {
+				data[i] = hardSigmoidDiffF32(v)
+			}
+		case reflect.Float64:
+			data := ret.float64s()
+			for i, v := range data {
+				data[i] = hardSigmoidDiffF64(v)
+			}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "HardSigmoidDiff", a)
+	}
+}
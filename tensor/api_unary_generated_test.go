@@ -129,3 +129,114 @@ func TestSign(t *testing.T) {
 		assert.Equal(st.correct, got.Data())
 	}
 }
+
+var activationTests = []struct {
+	name string
+	fn   func(a Tensor, opts ...FuncOpt) (Tensor, error)
+	f64  func(float64) float64
+	f32  func(float32) float32
+}{
+	{"LeakyRelu", func(a Tensor, opts ...FuncOpt) (Tensor, error) {
+		if a.Dtype() == Float32 {
+			return LeakyRelu(a, float32(0.1), opts...)
+		}
+		return LeakyRelu(a, 0.1, opts...)
+	}, func(x float64) float64 { return leakyReluF64(x, 0.1) }, func(x float32) float32 { return leakyReluF32(x, 0.1) }},
+	{"Elu", Elu, eluF64, eluF32},
+	{"Selu", Selu, seluF64, seluF32},
+	{"Gelu", Gelu, geluF64, geluF32},
+	{"GeluTanh", GeluTanh, geluTanhF64, geluTanhF32},
+	{"Swish", Swish, swishF64, swishF32},
+	{"Mish", Mish, mishF64, mishF32},
+	{"HardSigmoid", HardSigmoid, hardSigmoidF64, hardSigmoidF32},
+	{"LeakyReluDiff", func(a Tensor, opts ...FuncOpt) (Tensor, error) {
+		if a.Dtype() == Float32 {
+			return LeakyReluDiff(a, float32(0.1), opts...)
+		}
+		return LeakyReluDiff(a, 0.1, opts...)
+	}, func(x float64) float64 { return leakyReluDiffF64(x, 0.1) }, func(x float32) float32 { return leakyReluDiffF32(x, 0.1) }},
+	{"EluDiff", EluDiff, eluDiffF64, eluDiffF32},
+	{"SeluDiff", SeluDiff, seluDiffF64, seluDiffF32},
+	{"GeluDiff", GeluDiff, geluDiffF64, geluDiffF32},
+	{"GeluTanhDiff", GeluTanhDiff, geluTanhDiffF64, geluTanhDiffF32},
+	{"SwishDiff", SwishDiff, swishDiffF64, swishDiffF32},
+	{"MishDiff", MishDiff, mishDiffF64, mishDiffF32},
+	{"HardSigmoidDiff", HardSigmoidDiff, hardSigmoidDiffF64, hardSigmoidDiffF32},
+}
+
+func TestActivations(t *testing.T) {
+	assert := assert.New(t)
+	backing := []float64{-4, -2.5, -1, -0.3, 0, 0.4, 1.2, 2.7, 3, 5}
+	for _, at := range activationTests {
+		for _, dt := range []Dtype{Float64, Float32} {
+			var a, reuseBacking, correct, correctView interface{}
+			switch dt {
+			case Float64:
+				f64s, r, c := make([]float64, len(backing)), make([]float64, len(backing)), make([]float64, len(backing))
+				for i, v := range backing {
+					f64s[i] = v
+					c[i] = at.f64(v)
+				}
+				a, reuseBacking, correct, correctView = f64s, r, c, c[1:5]
+			case Float32:
+				f32s, r, c := make([]float32, len(backing)), make([]float32, len(backing)), make([]float32, len(backing))
+				for i, v := range backing {
+					f32s[i] = float32(v)
+					c[i] = at.f32(float32(v))
+				}
+				a, reuseBacking, correct, correctView = f32s, r, c, c[1:5]
+			}
+			T := New(WithBacking(a))
+
+			// views
+			V, err := T.Slice(makeRS(1, 5))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := at.fn(V)
+			if err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			assert.Equal(correctView, got.Data(), "%v %v", at.name, dt)
+
+			// safe
+			if got, err = at.fn(T); err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			if got == T {
+				t.Error("expected got != T")
+				continue
+			}
+			assert.Equal(correct, got.Data(), "%v %v", at.name, dt)
+
+			// reuse
+			reuse := New(WithBacking(reuseBacking))
+			if got, err = at.fn(T, WithReuse(reuse)); err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			if got != reuse {
+				t.Error("expected got == reuse")
+				continue
+			}
+			assert.Equal(correct, got.Data(), "%v %v", at.name, dt)
+
+			// unsafe
+			if got, err = at.fn(T, UseUnsafe()); err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			if got != T {
+				t.Error("expected got == T")
+				continue
+			}
+			assert.Equal(correct, got.Data(), "%v %v", at.name, dt)
+		}
+
+		if _, err := at.fn(New(WithBacking([]int{1, 2}))); err == nil {
+			t.Errorf("%v: expected an error for a Tensor of ints", at.name)
+		}
+	}
+}
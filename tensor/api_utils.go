@@ -8,6 +8,7 @@ import (
 	"sort"
 
 	"github.com/chewxy/math32"
+	"github.com/pkg/errors"
 )
 
 // SortIndex is similar to numpy's argsort
@@ -50,6 +51,33 @@ func SortIndex(in interface{}) (out []int) {
 	return
 }
 
+// Sort returns a Tensor with the values of t sorted along the axis. See (*Dense).Sort.
+func Sort(t Tensor, axis int, descending bool) (retVal Tensor, err error) {
+	switch T := t.(type) {
+	case *Dense:
+		return T.Sort(axis, descending)
+	}
+	return nil, errors.Errorf(typeNYI, "Sort", t)
+}
+
+// Argsort returns the indices that would sort t along the axis. Unlike SortIndex, it works along any axis of a Tensor. See (*Dense).Argsort.
+func Argsort(t Tensor, axis int) (retVal Tensor, err error) {
+	switch T := t.(type) {
+	case *Dense:
+		return T.Argsort(axis)
+	}
+	return nil, errors.Errorf(typeNYI, "Argsort", t)
+}
+
+// TopK returns the k largest values of t along the axis and their indices. See (*Dense).TopK.
+func TopK(t Tensor, k, axis int) (values, indices Tensor, err error) {
+	switch T := t.(type) {
+	case *Dense:
+		return T.TopK(k, axis)
+	}
+	return nil, nil, errors.Errorf(typeNYI, "TopK", t)
+}
+
 // SampleIndex samples a slice or a Tensor.
 // TODO: tidy this up.
 func SampleIndex(in interface{}) int {
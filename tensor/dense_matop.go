@@ -1,6 +1,7 @@
 package tensor
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/pkg/errors"
@@ -412,6 +413,118 @@ func (t *Dense) Concat(axis int, Ts ...*Dense) (retVal *Dense, err error) {
 	return
 }
 
+// PadMode is the way the padding added by Pad is filled.
+type PadMode byte
+
+const (
+	// PadConstant fills the padding with a constant value.
+	PadConstant PadMode = iota
+	// PadReflect fills the padding with the values mirrored about the edge, without repeating the edge. It is Numpy's "reflect" mode.
+	PadReflect
+	// PadEdge fills the padding with the edge values.
+	PadEdge
+)
+
+func (m PadMode) String() string {
+	switch m {
+	case PadConstant:
+		return "constant"
+	case PadReflect:
+		return "reflect"
+	case PadEdge:
+		return "edge"
+	}
+	return fmt.Sprintf("PadMode(%d)", byte(m))
+}
+
+// index returns the index of the value along an axis of the given size that fills index i of the padded axis, where i is relative to
+// the start of the unpadded values. ok is false if the value is the constant.
+func (m PadMode) index(i, size int) (at int, ok bool) {
+	switch {
+	case i >= 0 && i < size:
+		return i, true
+	case m == PadReflect && i < 0:
+		return -i, true
+	case m == PadReflect:
+		return 2*(size-1) - i, true
+	case m == PadEdge && i < 0:
+		return 0, true
+	case m == PadEdge:
+		return size - 1, true
+	}
+	return 0, false
+}
+
+// Pad pads the *Dense with widths[i][0] values before and widths[i][1] values after each axis i. It is like Numpy's pad() function.
+// value is the value of the padding in PadConstant mode, and has to be of the Dtype of the *Dense. It is ignored in the other modes.
+// In PadReflect mode, the padding of an axis has to be narrower than the axis.
+//
+// A new *Dense is always returned, even if all the widths are 0.
+func (t *Dense) Pad(widths [][2]int, mode PadMode, value interface{}) (retVal *Dense, err error) {
+	shape := t.Shape()
+	if t.IsScalar() {
+		return nil, errors.Errorf(atleastDims, 1)
+	}
+	if len(widths) != len(shape) {
+		return nil, errors.Errorf(dimMismatch, len(shape), len(widths))
+	}
+
+	newShape := make(Shape, len(shape))
+	for i, w := range widths {
+		if w[0] < 0 || w[1] < 0 {
+			return nil, errors.Errorf("Invalid padding widths %v for axis %d", w, i)
+		}
+		switch mode {
+		case PadConstant:
+		case PadReflect:
+			if w[0] >= shape[i] || w[1] >= shape[i] {
+				return nil, errors.Errorf("Cannot pad axis %d of size %d by %v in reflect mode", i, shape[i], w)
+			}
+		case PadEdge:
+			if shape[i] == 0 && w != [2]int{} {
+				return nil, errors.Errorf("Cannot pad axis %d of size 0 in edge mode", i)
+			}
+		default:
+			return nil, errors.Errorf(methodNYI, "Pad", mode)
+		}
+		newShape[i] = shape[i] + w[0] + w[1]
+	}
+
+	src := t
+	if t.IsMaterializable() {
+		src = t.Materialize().(*Dense)
+	}
+	retVal = New(Of(t.t), WithShape(newShape...))
+	if mode == PadConstant {
+		if err = retVal.Memset(value); err != nil {
+			return nil, err
+		}
+	}
+
+	coord := make([]int, len(newShape))
+	for i := 0; i < retVal.len(); i++ {
+		at, ok := 0, true
+		for d, c := range coord {
+			var s int
+			if s, ok = mode.index(c-widths[d][0], shape[d]); !ok {
+				break
+			}
+			at = at*shape[d] + s
+		}
+		if ok {
+			retVal.Set(i, src.Get(at))
+		}
+
+		for d := len(coord) - 1; d >= 0; d-- {
+			if coord[d]++; coord[d] < newShape[d] {
+				break
+			}
+			coord[d] = 0
+		}
+	}
+	return retVal, nil
+}
+
 // Hstack stacks other tensors columnwise (horizontal stacking)
 func (t *Dense) Hstack(others ...*Dense) (*Dense, error) {
 	// check that everything is at least 1D
@@ -653,6 +653,72 @@ func TestDense_Concat(t *testing.T) {
 	}
 }
 
+var padTests = []struct {
+	name   string
+	a      interface{}
+	shape  Shape
+	widths [][2]int
+	mode   PadMode
+	value  interface{}
+
+	correctShape Shape
+	correctData  interface{}
+}{
+	{"vector, constant", []float64{0, 1, 2}, Shape{3}, [][2]int{{2, 1}}, PadConstant, 9.0, Shape{6}, []float64{9, 9, 0, 1, 2, 9}},
+	{"vector, reflect", []float64{0, 1, 2}, Shape{3}, [][2]int{{2, 2}}, PadReflect, nil, Shape{7}, []float64{2, 1, 0, 1, 2, 1, 0}},
+	{"vector, edge", []float64{0, 1, 2}, Shape{3}, [][2]int{{2, 1}}, PadEdge, nil, Shape{6}, []float64{0, 0, 0, 1, 2, 2}},
+	{"vector, no padding", []float32{0, 1, 2}, Shape{3}, [][2]int{{0, 0}}, PadConstant, float32(0), Shape{3}, []float32{0, 1, 2}},
+	{"matrix, constant", []float64{0, 1, 2, 3, 4, 5}, Shape{2, 3}, [][2]int{{1, 0}, {0, 1}}, PadConstant, -1.0, Shape{3, 4},
+		[]float64{-1, -1, -1, -1, 0, 1, 2, -1, 3, 4, 5, -1}},
+	{"matrix, reflect", []float64{0, 1, 2, 3, 4, 5}, Shape{2, 3}, [][2]int{{1, 1}, {1, 0}}, PadReflect, nil, Shape{4, 4},
+		[]float64{4, 3, 4, 5, 1, 0, 1, 2, 4, 3, 4, 5, 1, 0, 1, 2}},
+	{"matrix, edge", []int{0, 1, 2, 3, 4, 5}, Shape{2, 3}, [][2]int{{0, 1}, {2, 0}}, PadEdge, nil, Shape{3, 5},
+		[]int{0, 0, 0, 1, 2, 3, 3, 3, 4, 5, 3, 3, 3, 4, 5}},
+}
+
+func TestDense_Pad(t *testing.T) {
+	assert := assert.New(t)
+	for _, pts := range padTests {
+		T := New(WithShape(pts.shape...), WithBacking(pts.a))
+		T2, err := T.Pad(pts.widths, pts.mode, pts.value)
+		if err != nil {
+			t.Errorf("%v: %v", pts.name, err)
+			continue
+		}
+		assert.True(pts.correctShape.Eq(T2.Shape()), pts.name)
+		assert.Equal(pts.correctData, T2.Data(), pts.name)
+	}
+
+	// transposed
+	T := New(WithShape(2, 3), WithBacking([]float64{0, 1, 2, 3, 4, 5}))
+	T.T()
+	T2, err := Pad(T, [][2]int{{0, 0}, {1, 0}}, PadConstant, 0.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(Shape{3, 3}, T2.Shape())
+	assert.Equal([]float64{0, 0, 3, 0, 1, 4, 0, 2, 5}, T2.Data())
+
+	T = New(WithShape(2, 3), WithBacking([]float64{0, 1, 2, 3, 4, 5}))
+	bad := []struct {
+		name   string
+		widths [][2]int
+		mode   PadMode
+		value  interface{}
+	}{
+		{"reflect wider than the axis", [][2]int{{2, 0}, {0, 0}}, PadReflect, nil},
+		{"too few widths", [][2]int{{1, 1}}, PadEdge, nil},
+		{"negative widths", [][2]int{{-1, 0}, {0, 0}}, PadEdge, nil},
+		{"value of the wrong dtype", [][2]int{{1, 0}, {0, 0}}, PadConstant, 1},
+		{"unknown mode", [][2]int{{1, 0}, {0, 0}}, PadMode(100), nil},
+	}
+	for _, b := range bad {
+		if _, err = T.Pad(b.widths, b.mode, b.value); err == nil {
+			t.Errorf("Expected an error: %v", b.name)
+		}
+	}
+}
+
 var simpleStackTests = []struct {
 	name       string
 	shape      Shape
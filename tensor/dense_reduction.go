@@ -711,7 +711,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -736,7 +736,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -761,7 +761,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -786,7 +786,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -811,7 +811,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -836,7 +836,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -861,7 +861,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -886,7 +886,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -911,7 +911,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -936,7 +936,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -961,7 +961,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -986,7 +986,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1011,7 +1011,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1036,7 +1036,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1061,7 +1061,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1086,7 +1086,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1111,7 +1111,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1136,7 +1136,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1166,7 +1166,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1237,7 +1237,7 @@ func (t *Dense) sReduceI(axis int, zeroFn func(a, b []int) error, lastFn func([]
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1308,7 +1308,7 @@ func (t *Dense) sReduceI8(axis int, zeroFn func(a, b []int8) error, lastFn func(
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1379,7 +1379,7 @@ func (t *Dense) sReduceI16(axis int, zeroFn func(a, b []int16) error, lastFn fun
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1450,7 +1450,7 @@ func (t *Dense) sReduceI32(axis int, zeroFn func(a, b []int32) error, lastFn fun
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1521,7 +1521,7 @@ func (t *Dense) sReduceI64(axis int, zeroFn func(a, b []int64) error, lastFn fun
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1592,7 +1592,7 @@ func (t *Dense) sReduceU(axis int, zeroFn func(a, b []uint) error, lastFn func([
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1663,7 +1663,7 @@ func (t *Dense) sReduceU8(axis int, zeroFn func(a, b []uint8) error, lastFn func
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1734,7 +1734,7 @@ func (t *Dense) sReduceU16(axis int, zeroFn func(a, b []uint16) error, lastFn fu
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1805,7 +1805,7 @@ func (t *Dense) sReduceU32(axis int, zeroFn func(a, b []uint32) error, lastFn fu
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1876,7 +1876,7 @@ func (t *Dense) sReduceU64(axis int, zeroFn func(a, b []uint64) error, lastFn fu
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -1947,7 +1947,7 @@ func (t *Dense) sReduceF32(axis int, zeroFn func(a, b []float32) error, lastFn f
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -2018,7 +2018,7 @@ func (t *Dense) sReduceF64(axis int, zeroFn func(a, b []float64) error, lastFn f
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -2089,7 +2089,7 @@ func (t *Dense) sReduceC64(axis int, zeroFn func(a, b []complex64) error, lastFn
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -2160,7 +2160,7 @@ func (t *Dense) sReduceC128(axis int, zeroFn func(a, b []complex128) error, last
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -0,0 +1,194 @@
+package tensor
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Cumsum returns the cumulative sums of the values of the *Dense along the axis.
+// If exclusive is true, each sum leaves out the value at its own index, so the first sum is 0.
+// If reverse is true, the values are summed from the end of the axis instead.
+func (t *Dense) Cumsum(axis int, exclusive, reverse bool) (retVal *Dense, err error) {
+	return t.scan(axis, exclusive, reverse, false)
+}
+
+// Cumprod returns the cumulative products of the values of the *Dense along the axis.
+// If exclusive is true, each product leaves out the value at its own index, so the first product is 1.
+// If reverse is true, the values are multiplied from the end of the axis instead.
+func (t *Dense) Cumprod(axis int, exclusive, reverse bool) (retVal *Dense, err error) {
+	return t.scan(axis, exclusive, reverse, true)
+}
+
+// scan does a cumulative sum, or a cumulative product if prod is true. A new *Dense is always returned.
+func (t *Dense) scan(axis int, exclusive, reverse, prod bool) (retVal *Dense, err error) {
+	if t.IsScalar() {
+		return nil, errors.Errorf(atleastDims, 1)
+	}
+	if axis < 0 || axis >= t.Dims() {
+		return nil, errors.Errorf(invalidAxis, axis, t.Dims())
+	}
+
+	src := t
+	if t.IsMaterializable() {
+		src = t.Materialize().(*Dense)
+	}
+	shape := t.Shape()
+	retVal = New(Of(t.t), WithShape(shape.Clone()...))
+
+	// each lane holds the indices of the values along the axis, in the order that they are scanned
+	lanes := axisLanes(shape, axis, reverse)
+
+	switch t.t.Kind() {
+	case reflect.Int:
+		scanI(src.ints(), retVal.ints(), lanes, exclusive, prod)
+	case reflect.Int32:
+		scanI32(src.int32s(), retVal.int32s(), lanes, exclusive, prod)
+	case reflect.Int64:
+		scanI64(src.int64s(), retVal.int64s(), lanes, exclusive, prod)
+	case reflect.Float32:
+		scanF32(src.float32s(), retVal.float32s(), lanes, exclusive, prod)
+	case reflect.Float64:
+		scanF64(src.float64s(), retVal.float64s(), lanes, exclusive, prod)
+	default:
+		return nil, errors.Errorf(unsupportedDtype, t.t, "scan")
+	}
+	return retVal, nil
+}
+
+// axisLanes returns the flat indices of the values along the axis of a contiguous array of the given shape, one lane per position of the other axes.
+// If reverse is true, each lane goes from the end of the axis instead.
+func axisLanes(shape Shape, axis int, reverse bool) [][]int {
+	outer, n, inner := 1, shape[axis], 1
+	for _, d := range shape[:axis] {
+		outer *= d
+	}
+	for _, d := range shape[axis+1:] {
+		inner *= d
+	}
+	lanes := make([][]int, 0, outer*inner)
+	for o := 0; o < outer; o++ {
+		for in := 0; in < inner; in++ {
+			lane := make([]int, n)
+			for i := range lane {
+				j := i
+				if reverse {
+					j = n - 1 - i
+				}
+				lane[i] = (o*n+j)*inner + in
+			}
+			lanes = append(lanes, lane)
+		}
+	}
+	return lanes
+}
+
+func scanI(data, res []int, lanes [][]int, exclusive, prod bool) {
+	for _, lane := range lanes {
+		var acc int
+		if prod {
+			acc = 1
+		}
+		for _, i := range lane {
+			if exclusive {
+				res[i] = acc
+			}
+			if prod {
+				acc *= data[i]
+			} else {
+				acc += data[i]
+			}
+			if !exclusive {
+				res[i] = acc
+			}
+		}
+	}
+}
+
+func scanI32(data, res []int32, lanes [][]int, exclusive, prod bool) {
+	for _, lane := range lanes {
+		var acc int32
+		if prod {
+			acc = 1
+		}
+		for _, i := range lane {
+			if exclusive {
+				res[i] = acc
+			}
+			if prod {
+				acc *= data[i]
+			} else {
+				acc += data[i]
+			}
+			if !exclusive {
+				res[i] = acc
+			}
+		}
+	}
+}
+
+func scanI64(data, res []int64, lanes [][]int, exclusive, prod bool) {
+	for _, lane := range lanes {
+		var acc int64
+		if prod {
+			acc = 1
+		}
+		for _, i := range lane {
+			if exclusive {
+				res[i] = acc
+			}
+			if prod {
+				acc *= data[i]
+			} else {
+				acc += data[i]
+			}
+			if !exclusive {
+				res[i] = acc
+			}
+		}
+	}
+}
+
+func scanF32(data, res []float32, lanes [][]int, exclusive, prod bool) {
+	for _, lane := range lanes {
+		var acc float32
+		if prod {
+			acc = 1
+		}
+		for _, i := range lane {
+			if exclusive {
+				res[i] = acc
+			}
+			if prod {
+				acc *= data[i]
+			} else {
+				acc += data[i]
+			}
+			if !exclusive {
+				res[i] = acc
+			}
+		}
+	}
+}
+
+func scanF64(data, res []float64, lanes [][]int, exclusive, prod bool) {
+	for _, lane := range lanes {
+		var acc float64
+		if prod {
+			acc = 1
+		}
+		for _, i := range lane {
+			if exclusive {
+				res[i] = acc
+			}
+			if prod {
+				acc *= data[i]
+			} else {
+				acc += data[i]
+			}
+			if !exclusive {
+				res[i] = acc
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package tensor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var scanTests = []struct {
+	name      string
+	a         interface{}
+	shape     Shape
+	axis      int
+	exclusive bool
+	reverse   bool
+	prod      bool
+
+	correct interface{}
+}{
+	{"vector cumsum", []float64{1, 2, 3, 4}, Shape{4}, 0, false, false, false, []float64{1, 3, 6, 10}},
+	{"vector cumsum, exclusive", []float64{1, 2, 3, 4}, Shape{4}, 0, true, false, false, []float64{0, 1, 3, 6}},
+	{"vector cumsum, reverse", []float64{1, 2, 3, 4}, Shape{4}, 0, false, true, false, []float64{10, 9, 7, 4}},
+	{"vector cumsum, exclusive reverse", []float64{1, 2, 3, 4}, Shape{4}, 0, true, true, false, []float64{9, 7, 4, 0}},
+	{"vector cumprod", []float32{1, 2, 3, 4}, Shape{4}, 0, false, false, true, []float32{1, 2, 6, 24}},
+	{"vector cumprod, exclusive reverse", []float32{1, 2, 3, 4}, Shape{4}, 0, true, true, true, []float32{24, 12, 4, 1}},
+	{"matrix cumsum, axis 0", []int{0, 1, 2, 3, 4, 5}, Shape{2, 3}, 0, false, false, false, []int{0, 1, 2, 3, 5, 7}},
+	{"matrix cumsum, axis 1", []int{0, 1, 2, 3, 4, 5}, Shape{2, 3}, 1, false, false, false, []int{0, 1, 3, 3, 7, 12}},
+	{"matrix cumprod, axis 1, exclusive", []int64{1, 2, 3, 4, 5, 6}, Shape{2, 3}, 1, true, false, true, []int64{1, 1, 2, 1, 4, 20}},
+	{"3-tensor cumsum, axis 1, reverse", []int32{0, 1, 2, 3, 4, 5, 6, 7}, Shape{2, 2, 2}, 1, false, true, false, []int32{2, 4, 2, 3, 10, 12, 6, 7}},
+}
+
+func TestDense_Cumsum_Cumprod(t *testing.T) {
+	assert := assert.New(t)
+	for _, sts := range scanTests {
+		T := New(WithShape(sts.shape...), WithBacking(sts.a))
+		var T2 Tensor
+		var err error
+		if sts.prod {
+			T2, err = Cumprod(T, sts.axis, sts.exclusive, sts.reverse)
+		} else {
+			T2, err = Cumsum(T, sts.axis, sts.exclusive, sts.reverse)
+		}
+		if err != nil {
+			t.Errorf("%v: %v", sts.name, err)
+			continue
+		}
+		assert.True(sts.shape.Eq(T2.Shape()), sts.name)
+		assert.Equal(sts.correct, T2.Data(), sts.name)
+	}
+
+	// transposed
+	T := New(WithShape(2, 3), WithBacking([]float64{0, 1, 2, 3, 4, 5}))
+	T.T()
+	T2, err := T.Cumsum(1, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal([]float64{0, 3, 1, 5, 2, 7}, T2.Data())
+
+	if _, err = T.Cumsum(2, false, false); err == nil {
+		t.Error("Expected an error for an axis out of range")
+	}
+	if _, err = New(WithShape(2), WithBacking([]bool{true, false})).Cumprod(0, false, false); err == nil {
+		t.Error("Expected an error for an unsupported Dtype")
+	}
+}
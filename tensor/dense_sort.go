@@ -0,0 +1,170 @@
+package tensor
+
+import (
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Sort returns a new *Dense with the values sorted along the axis, in ascending order, or in descending order if descending is true.
+// The sort is stable. NaNs are treated as the largest values.
+func (t *Dense) Sort(axis int, descending bool) (retVal *Dense, err error) {
+	var src *Dense
+	var perms [][]int
+	if src, perms, err = t.sortLanes(axis, descending); err != nil {
+		return nil, errors.Wrapf(err, opFail, "Sort")
+	}
+
+	retVal = New(Of(t.t), WithShape(t.Shape().Clone()...))
+	lanes := axisLanes(t.Shape(), axis, false)
+	for l, lane := range lanes {
+		for i, j := range perms[l] {
+			retVal.Set(lane[i], src.Get(j))
+		}
+	}
+	return retVal, nil
+}
+
+// Argsort returns the indices that would sort the values along the axis in ascending order, as a *Dense of ints of the same shape.
+// The sort is stable, so equal values keep their order. NaNs are treated as the largest values.
+func (t *Dense) Argsort(axis int) (retVal *Dense, err error) {
+	var perms [][]int
+	if _, perms, err = t.sortLanes(axis, false); err != nil {
+		return nil, errors.Wrapf(err, opFail, "Argsort")
+	}
+
+	shape := t.Shape()
+	retVal = New(Of(Int), WithShape(shape.Clone()...))
+	res := retVal.ints()
+	lanes := axisLanes(shape, axis, false)
+	for l, lane := range lanes {
+		for i, j := range perms[l] {
+			res[lane[i]] = laneIndex(shape, axis, j)
+		}
+	}
+	return retVal, nil
+}
+
+// TopK returns the k largest values along the axis, in descending order, and their indices along the axis.
+// Both have the shape of the *Dense, except that the axis has size k. Equal values are returned in the order of their indices.
+func (t *Dense) TopK(k, axis int) (values, indices *Dense, err error) {
+	var src *Dense
+	var perms [][]int
+	if src, perms, err = t.sortLanes(axis, true); err != nil {
+		return nil, nil, errors.Wrapf(err, opFail, "TopK")
+	}
+
+	shape := t.Shape()
+	if k < 1 || k > shape[axis] {
+		return nil, nil, errors.Errorf("TopK expects k to be between 1 and %d. Got %d instead", shape[axis], k)
+	}
+	topShape := shape.Clone()
+	topShape[axis] = k
+	values = New(Of(t.t), WithShape(topShape...))
+	indices = New(Of(Int), WithShape(topShape.Clone()...))
+	idx := indices.ints()
+	lanes := axisLanes(topShape, axis, false)
+	for l, lane := range lanes {
+		for i, at := range lane {
+			j := perms[l][i]
+			values.Set(at, src.Get(j))
+			idx[at] = laneIndex(shape, axis, j)
+		}
+	}
+	return values, indices, nil
+}
+
+// sortLanes sorts each lane along the axis. It returns the contiguous data that was sorted, and for each lane (in the order of axisLanes),
+// the flat indices of its values in sorted order.
+func (t *Dense) sortLanes(axis int, descending bool) (src *Dense, perms [][]int, err error) {
+	if t.IsScalar() {
+		return nil, nil, errors.Errorf(atleastDims, 1)
+	}
+	if axis < 0 || axis >= t.Dims() {
+		return nil, nil, errors.Errorf(invalidAxis, axis, t.Dims())
+	}
+
+	src = t
+	if t.IsMaterializable() {
+		src = t.Materialize().(*Dense)
+	}
+
+	var less func(i, j int) bool
+	if less = lessFn(src); less == nil {
+		return nil, nil, errors.Errorf(unsupportedDtype, t.t, "sort")
+	}
+	cmp := less
+	if descending {
+		cmp = func(i, j int) bool { return less(j, i) }
+	}
+
+	perms = axisLanes(t.Shape(), axis, false)
+	for _, perm := range perms {
+		sort.SliceStable(perm, func(a, b int) bool { return cmp(perm[a], perm[b]) })
+	}
+	return src, perms, nil
+}
+
+// laneIndex returns the index along the axis of the flat index i of a contiguous array of the given shape.
+func laneIndex(shape Shape, axis, i int) int {
+	inner := 1
+	for _, d := range shape[axis+1:] {
+		inner *= d
+	}
+	return (i / inner) % shape[axis]
+}
+
+// lessFn returns the function that compares the values at two flat indices of the data of t, or nil if the Dtype is not ordered.
+// NaNs are larger than every other value.
+func lessFn(t *Dense) func(i, j int) bool {
+	switch t.t.Kind() {
+	case reflect.Int:
+		data := t.ints()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Int8:
+		data := t.int8s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Int16:
+		data := t.int16s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Int32:
+		data := t.int32s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Int64:
+		data := t.int64s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Uint:
+		data := t.uints()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Uint8:
+		data := t.uint8s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Uint16:
+		data := t.uint16s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Uint32:
+		data := t.uint32s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Uint64:
+		data := t.uint64s()
+		return func(i, j int) bool { return data[i] < data[j] }
+	case reflect.Float32:
+		data := t.float32s()
+		return func(i, j int) bool {
+			a, b := float64(data[i]), float64(data[j])
+			return a < b || (math.IsNaN(b) && !math.IsNaN(a))
+		}
+	case reflect.Float64:
+		data := t.float64s()
+		return func(i, j int) bool {
+			a, b := data[i], data[j]
+			return a < b || (math.IsNaN(b) && !math.IsNaN(a))
+		}
+	case reflect.String:
+		data := t.strings()
+		return func(i, j int) bool { return data[i] < data[j] }
+	}
+	return nil
+}
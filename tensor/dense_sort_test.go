@@ -0,0 +1,120 @@
+package tensor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sortTests = []struct {
+	name       string
+	a          interface{}
+	shape      Shape
+	axis       int
+	descending bool
+
+	correct     interface{}
+	correctArgs []int
+}{
+	{"vector", []float64{3, 1, 2, 0}, Shape{4}, 0, false, []float64{0, 1, 2, 3}, []int{3, 1, 2, 0}},
+	{"vector, descending", []float64{3, 1, 2, 0}, Shape{4}, 0, true, []float64{3, 2, 1, 0}, []int{3, 1, 2, 0}},
+	{"vector with NaN", []float64{2, math.NaN(), -1}, Shape{3}, 0, false, []float64{-1, 2, math.NaN()}, []int{2, 0, 1}},
+	{"stable", []int{1, 0, 1, 0}, Shape{4}, 0, false, []int{0, 0, 1, 1}, []int{1, 3, 0, 2}},
+	{"matrix, axis 0", []float32{4, 1, 2, 3, 0, 5}, Shape{2, 3}, 0, false, []float32{3, 0, 2, 4, 1, 5}, []int{1, 1, 0, 0, 0, 1}},
+	{"matrix, axis 1", []float32{4, 1, 2, 3, 0, 5}, Shape{2, 3}, 1, false, []float32{1, 2, 4, 0, 3, 5}, []int{1, 2, 0, 1, 0, 2}},
+	{"matrix, axis 1, descending", []int64{4, 1, 2, 3, 0, 5}, Shape{2, 3}, 1, true, []int64{4, 2, 1, 5, 3, 0}, []int{1, 2, 0, 1, 0, 2}},
+	{"3-tensor, axis 1", []uint8{3, 0, 1, 2, 5, 7, 6, 4}, Shape{2, 2, 2}, 1, false, []uint8{1, 0, 3, 2, 5, 4, 6, 7}, []int{1, 0, 0, 1, 0, 1, 1, 0}},
+	{"strings", []string{"b", "c", "a"}, Shape{3}, 0, false, []string{"a", "b", "c"}, []int{2, 0, 1}},
+}
+
+func TestDense_Sort_Argsort(t *testing.T) {
+	assert := assert.New(t)
+	for _, sts := range sortTests {
+		T := New(WithShape(sts.shape...), WithBacking(sts.a))
+		T2, err := Sort(T, sts.axis, sts.descending)
+		if err != nil {
+			t.Errorf("%v: %v", sts.name, err)
+			continue
+		}
+		assert.True(sts.shape.Eq(T2.Shape()), sts.name)
+		if correct, ok := sts.correct.([]float64); ok {
+			// NaNs aren't equal to themselves
+			data := T2.Data().([]float64)
+			for i := range correct {
+				assert.True(correct[i] == data[i] || (math.IsNaN(correct[i]) && math.IsNaN(data[i])), sts.name)
+			}
+		} else {
+			assert.Equal(sts.correct, T2.Data(), sts.name)
+		}
+
+		if sts.descending {
+			continue
+		}
+		args, err := Argsort(T, sts.axis)
+		if err != nil {
+			t.Errorf("%v: %v", sts.name, err)
+			continue
+		}
+		assert.True(sts.shape.Eq(args.Shape()), sts.name)
+		assert.Equal(sts.correctArgs, args.Data(), sts.name)
+	}
+
+	// transposed
+	T := New(WithShape(2, 3), WithBacking([]float64{0, 5, 1, 4, 2, 3}))
+	T.T()
+	T2, err := T.Sort(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal([]float64{0, 4, 2, 5, 1, 3}, T2.Data())
+	assert.Equal([]float64{0, 5, 1, 4, 2, 3}, T.Data(), "the *Dense should not be sorted in place")
+
+	if _, err = T.Sort(2, false); err == nil {
+		t.Error("Expected an error for an invalid axis")
+	}
+	if _, err = New(WithShape(2), WithBacking([]bool{true, false})).Argsort(0); err == nil {
+		t.Error("Expected an error for Bools")
+	}
+}
+
+var topKTests = []struct {
+	name  string
+	a     interface{}
+	shape Shape
+	k     int
+	axis  int
+
+	correctShape   Shape
+	correct        interface{}
+	correctIndices []int
+}{
+	{"vector", []float64{3, 1, 4, 1, 5}, Shape{5}, 2, 0, Shape{2}, []float64{5, 4}, []int{4, 2}},
+	{"vector, ties", []int{1, 3, 1, 3}, Shape{4}, 3, 0, Shape{3}, []int{3, 3, 1}, []int{1, 3, 0}},
+	{"matrix, axis 1", []float32{0, 5, 2, 7, 1, 3}, Shape{2, 3}, 2, 1, Shape{2, 2}, []float32{5, 2, 7, 3}, []int{1, 2, 0, 2}},
+	{"matrix, axis 0", []float32{0, 5, 2, 7, 1, 3}, Shape{2, 3}, 1, 0, Shape{1, 3}, []float32{7, 5, 3}, []int{1, 0, 1}},
+}
+
+func TestDense_TopK(t *testing.T) {
+	assert := assert.New(t)
+	for _, tkt := range topKTests {
+		T := New(WithShape(tkt.shape...), WithBacking(tkt.a))
+		values, indices, err := TopK(T, tkt.k, tkt.axis)
+		if err != nil {
+			t.Errorf("%v: %v", tkt.name, err)
+			continue
+		}
+		assert.True(tkt.correctShape.Eq(values.Shape()), tkt.name)
+		assert.True(tkt.correctShape.Eq(indices.Shape()), tkt.name)
+		assert.Equal(tkt.correct, values.Data(), tkt.name)
+		assert.Equal(tkt.correctIndices, indices.Data(), tkt.name)
+	}
+
+	T := New(WithShape(2, 3), WithBacking([]float64{0, 1, 2, 3, 4, 5}))
+	if _, _, err := T.TopK(4, 1); err == nil {
+		t.Error("Expected an error when k is larger than the axis")
+	}
+	if _, _, err := T.TopK(0, 1); err == nil {
+		t.Error("Expected an error when k is 0")
+	}
+}
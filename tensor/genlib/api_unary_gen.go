@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"reflect"
 	"text/template"
 )
 
@@ -193,18 +195,231 @@ func Sign(a Tensor, opts ...FuncOpt) (retVal Tensor, err error) {
 }
 `
 
+// activationConstsRaw are the constants used by the activation functions
+const activationConstsRaw = `// the constants of SELU, from Klambauer et al. (2017) - Self-Normalizing Neural Networks
+const (
+	seluLambda = 1.0507009873554804934193349852946
+	seluAlpha  = 1.6732632423543772848170429916717
+)
+
+const (
+	sqrt2         = 1.41421356237309504880168872420969808
+	sqrt2OverPi   = 0.79788456080286535587989211986876373 // √(2/π)
+	invSqrt2Pi    = 0.39894228040143267793994605993438186 // 1/√(2π)
+	geluTanhCoeff = 0.044715
+)
+
+`
+
+// unaryKernelRaw generates the scalar kernels of an activation function (or its derivative), and the function that applies them
+// on a Tensor. Only float Tensors are supported.
+const unaryKernelRaw = `{{$k := . -}}
+{{range .Kinds -}}
+func {{$k.Fn}}{{short .Kind}}(x{{if $k.Param}}, {{$k.Param}}{{end}} {{asType .Kind}}) {{asType .Kind}} {
+	{{.Body}}
+}
+
+{{end -}}
+// {{.Name}} {{.Doc}}
+// Only float Tensors are supported.{{if .Param}} The {{.Param}} provided must be the same type as the Tensor type.{{end}}
+// Incr is not supported (it doesn't make sense anyway)
+func {{.Name}}(a Tensor, {{if .Param}}{{.Param}}Val interface{}, {{end}}opts ...FuncOpt) (retVal Tensor, err error) {
+	switch t := a.(type) {
+	case *Dense:
+		if !isFloat(t.t) {
+			err = errors.Errorf("{{.Name}} only works on floats")
+			return
+		}
+
+		if t.IsMaterializable() {
+			var f interface{}
+			switch t.t.Kind() {
+			{{range .Kinds -}}
+			case reflect.{{reflectKind .Kind}}:
+				{{if $k.Param -}}
+				var {{$k.Param}} {{asType .Kind}}
+				var ok bool
+				if {{$k.Param}}, ok = {{$k.Param}}Val.({{asType .Kind}}); !ok {
+					err = errors.Wrapf(errors.Errorf(typeMismatch, {{$k.Param}}, {{$k.Param}}Val), "{{$k.Name}}() {{$k.Param}}")
+					return
+				}
+				f = func(x {{asType .Kind}}) {{asType .Kind}} { return {{$k.Fn}}{{short .Kind}}(x, {{$k.Param}}) }
+				{{else -}}
+				f = {{$k.Fn}}{{short .Kind}}
+				{{end -}}
+			{{end -}}
+			}
+			return t.Apply(f, opts...)
+		}
+
+		// otherwise, we have optimizations for this (basically remove the repeated function calls)
+		var reuse *Dense
+		var safe, toReuse, incr bool
+		if reuse, safe, toReuse, incr, err = prepUnaryDense(t, opts...); err != nil {
+			err = errors.Wrapf(err, opFail, "{{.Name}}")
+			return
+		}
+
+		var ret *Dense
+		switch {
+		case incr:
+			fallthrough
+		case toReuse:
+			copyDense(reuse, t)
+			ret = reuse
+		case safe:
+			ret = t.Clone().(*Dense)
+		case !safe:
+			ret = t
+		}
+
+		switch t.t.Kind() {
+		{{range .Kinds -}}
+		case reflect.{{reflectKind .Kind}}:
+			{{if $k.Param -}}
+			var {{$k.Param}} {{asType .Kind}}
+			var ok bool
+			if {{$k.Param}}, ok = {{$k.Param}}Val.({{asType .Kind}}); !ok {
+				err = errors.Wrapf(errors.Errorf(typeMismatch, {{$k.Param}}, {{$k.Param}}Val), "{{$k.Name}}() {{$k.Param}}")
+				return
+			}
+			{{end -}}
+			data := ret.{{sliceOf .Kind}}
+			for i, v := range data {
+				data[i] = {{$k.Fn}}{{short .Kind}}(v{{if $k.Param}}, {{$k.Param}}{{end}})
+			}
+		{{end -}}
+		}
+		retVal = ret
+		return
+	default:
+		return nil, errors.Errorf(typeNYI, "{{.Name}}", a)
+	}
+}
+
+`
+
+// unaryKernel is an elementwise function of floats, for which a Tensor function is generated
+type unaryKernel struct {
+	Name  string // name of the generated Tensor function
+	Fn    string // name of the scalar kernels, which is suffixed with the short name of the Kind
+	Param string // name of the parameter of the function, if it has one
+	Doc   string
+	Body  string // body of the scalar kernel, which is a template of the Kind. x is the input of the kernel.
+}
+
+// unaryKernelKind is the scalar kernel of a unaryKernel for one Kind
+type unaryKernelKind struct {
+	Kind reflect.Kind
+	Body string
+}
+
+// activations are the activation functions of neural networks, followed by their derivatives with respect to x
+var activations = []unaryKernel{
+	{"LeakyRelu", "leakyRelu", "alpha", "performs the leaky ReLU on each element of the Tensor: x where x > 0, and alpha·x elsewhere.",
+		`if x > 0 {
+			return x
+		}
+		return alpha * x`},
+	{"Elu", "elu", "", "performs the ELU on each element of the Tensor: x where x > 0, and exp(x) - 1 elsewhere.",
+		`if x > 0 {
+			return x
+		}
+		return {{mathPkg .}}Expm1(x)`},
+	{"Selu", "selu", "", "performs the SELU on each element of the Tensor, which is a scaled ELU: λx where x > 0, and λα(exp(x) - 1) elsewhere.",
+		`if x > 0 {
+			return seluLambda * x
+		}
+		return seluLambda * seluAlpha * {{mathPkg .}}Expm1(x)`},
+	{"Gelu", "gelu", "", "performs the GELU on each element of the Tensor: xΦ(x), where Φ is the CDF of the standard normal distribution.",
+		`// erfc is used instead of erf, as it is accurate for large negative x
+		return 0.5 * x * {{mathPkg .}}Erfc(-x/sqrt2)`},
+	{"GeluTanh", "geluTanh", "", "performs the tanh approximation of the GELU on each element of the Tensor: 0.5x(1 + tanh(√(2/π)(x + 0.044715x³))).",
+		`return 0.5 * x * (1 + {{mathPkg .}}Tanh(sqrt2OverPi*(x+geluTanhCoeff*x*x*x)))`},
+	{"Swish", "swish", "", "performs the swish, also known as SiLU, on each element of the Tensor: x·sigmoid(x).",
+		`return x / (1 + {{mathPkg .}}Exp(-x))`},
+	{"Mish", "mish", "", "performs the mish on each element of the Tensor: x·tanh(softplus(x)).",
+		`return x * {{mathPkg .}}Tanh({{mathPkg .}}Log1p({{mathPkg .}}Exp(x)))`},
+	{"HardSigmoid", "hardSigmoid", "", "performs the hard sigmoid, a piecewise linear approximation of sigmoid, on each element of the Tensor: 0 where x <= -3, 1 where x >= 3, and x/6 + 0.5 in between.",
+		`switch {
+		case x <= -3:
+			return 0
+		case x >= 3:
+			return 1
+		}
+		return x/6 + 0.5`},
+
+	{"LeakyReluDiff", "leakyReluDiff", "alpha", "computes the derivative of LeakyRelu at each element of the Tensor.",
+		`if x > 0 {
+			return 1
+		}
+		return alpha`},
+	{"EluDiff", "eluDiff", "", "computes the derivative of Elu at each element of the Tensor.",
+		`if x > 0 {
+			return 1
+		}
+		return {{mathPkg .}}Exp(x)`},
+	{"SeluDiff", "seluDiff", "", "computes the derivative of Selu at each element of the Tensor.",
+		`if x > 0 {
+			return seluLambda
+		}
+		return seluLambda * seluAlpha * {{mathPkg .}}Exp(x)`},
+	{"GeluDiff", "geluDiff", "", "computes the derivative of Gelu at each element of the Tensor: Φ(x) + xφ(x).",
+		`return 0.5*{{mathPkg .}}Erfc(-x/sqrt2) + x*invSqrt2Pi*{{mathPkg .}}Exp(-0.5*x*x)`},
+	{"GeluTanhDiff", "geluTanhDiff", "", "computes the derivative of GeluTanh at each element of the Tensor.",
+		`t := {{mathPkg .}}Tanh(sqrt2OverPi * (x + geluTanhCoeff*x*x*x))
+		return 0.5*(1+t) + 0.5*x*(1-t*t)*sqrt2OverPi*(1+3*geluTanhCoeff*x*x)`},
+	{"SwishDiff", "swishDiff", "", "computes the derivative of Swish at each element of the Tensor: σ(x)(1 + x(1 - σ(x))).",
+		`s := 1 / (1 + {{mathPkg .}}Exp(-x))
+		return s * (1 + x*(1-s))`},
+	{"MishDiff", "mishDiff", "", "computes the derivative of Mish at each element of the Tensor: tanh(softplus(x)) + x·sech²(softplus(x))·σ(x).",
+		`t := {{mathPkg .}}Tanh({{mathPkg .}}Log1p({{mathPkg .}}Exp(x)))
+		s := 1 / (1 + {{mathPkg .}}Exp(-x))
+		return t + x*(1-t*t)*s`},
+	{"HardSigmoidDiff", "hardSigmoidDiff", "", "computes the derivative of HardSigmoid at each element of the Tensor.",
+		`if x > -3 && x < 3 {
+			return 1.0 / 6
+		}
+		return 0`},
+}
+
 var (
-	clamp *template.Template
-	sign  *template.Template
+	clamp     *template.Template
+	sign      *template.Template
+	unaryKern *template.Template
 )
 
 func init() {
 	clamp = template.Must(template.New("clamp").Funcs(funcs).Parse(clampRaw))
 	sign = template.Must(template.New("sign").Funcs(funcs).Parse(signRaw))
+	unaryKern = template.Must(template.New("unaryKernel").Funcs(funcs).Parse(unaryKernelRaw))
+}
+
+// kinds returns the scalar kernels of the unaryKernel, for the float Kinds
+func (k unaryKernel) kinds(generic *ManyKinds) (retVal []unaryKernelKind) {
+	body := template.Must(template.New(k.Fn).Funcs(funcs).Parse(k.Body))
+	for _, kind := range generic.Kinds {
+		if kind != reflect.Float32 && kind != reflect.Float64 {
+			continue
+		}
+		var buf bytes.Buffer
+		body.Execute(&buf, kind)
+		retVal = append(retVal, unaryKernelKind{Kind: kind, Body: buf.String()})
+	}
+	return
 }
 
 func generateUnaryAPIFuncs(f io.Writer, generic *ManyKinds) {
 	clamp.Execute(f, generic)
 	fmt.Fprint(f, "\n")
 	sign.Execute(f, generic)
+	fmt.Fprint(f, "\n")
+
+	fmt.Fprint(f, activationConstsRaw)
+	for _, k := range activations {
+		unaryKern.Execute(f, struct {
+			unaryKernel
+			Kinds []unaryKernelKind
+		}{k, k.kinds(generic)})
+	}
 }
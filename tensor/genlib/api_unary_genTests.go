@@ -123,17 +123,118 @@ func TestSign(t *testing.T){
 }
 `
 
+const activationTestsRaw = `var activationTests = []struct {
+	name string
+	fn   func(a Tensor, opts ...FuncOpt) (Tensor, error)
+	f64  func(float64) float64
+	f32  func(float32) float32
+}{
+	{{range . -}}
+	{{if .Param -}}
+	{"{{.Name}}", func(a Tensor, opts ...FuncOpt) (Tensor, error) {
+		if a.Dtype() == Float32 {
+			return {{.Name}}(a, float32(0.1), opts...)
+		}
+		return {{.Name}}(a, 0.1, opts...)
+	}, func(x float64) float64 { return {{.Fn}}F64(x, 0.1) }, func(x float32) float32 { return {{.Fn}}F32(x, 0.1) }},
+	{{else -}}
+	{"{{.Name}}", {{.Name}}, {{.Fn}}F64, {{.Fn}}F32},
+	{{end -}}
+	{{end -}}
+}
+
+func TestActivations(t *testing.T) {
+	assert := assert.New(t)
+	backing := []float64{-4, -2.5, -1, -0.3, 0, 0.4, 1.2, 2.7, 3, 5}
+	for _, at := range activationTests {
+		for _, dt := range []Dtype{Float64, Float32} {
+			var a, reuseBacking, correct, correctView interface{}
+			switch dt {
+			case Float64:
+				f64s, r, c := make([]float64, len(backing)), make([]float64, len(backing)), make([]float64, len(backing))
+				for i, v := range backing {
+					f64s[i] = v
+					c[i] = at.f64(v)
+				}
+				a, reuseBacking, correct, correctView = f64s, r, c, c[1:5]
+			case Float32:
+				f32s, r, c := make([]float32, len(backing)), make([]float32, len(backing)), make([]float32, len(backing))
+				for i, v := range backing {
+					f32s[i] = float32(v)
+					c[i] = at.f32(float32(v))
+				}
+				a, reuseBacking, correct, correctView = f32s, r, c, c[1:5]
+			}
+			T := New(WithBacking(a))
+
+			// views
+			V, err := T.Slice(makeRS(1, 5))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := at.fn(V)
+			if err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			assert.Equal(correctView, got.Data(), "%v %v", at.name, dt)
+
+			// safe
+			if got, err = at.fn(T); err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			if got == T {
+				t.Error("expected got != T")
+				continue
+			}
+			assert.Equal(correct, got.Data(), "%v %v", at.name, dt)
+
+			// reuse
+			reuse := New(WithBacking(reuseBacking))
+			if got, err = at.fn(T, WithReuse(reuse)); err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			if got != reuse {
+				t.Error("expected got == reuse")
+				continue
+			}
+			assert.Equal(correct, got.Data(), "%v %v", at.name, dt)
+
+			// unsafe
+			if got, err = at.fn(T, UseUnsafe()); err != nil {
+				t.Errorf("%v %v: %v", at.name, dt, err)
+				continue
+			}
+			if got != T {
+				t.Error("expected got == T")
+				continue
+			}
+			assert.Equal(correct, got.Data(), "%v %v", at.name, dt)
+		}
+
+		if _, err := at.fn(New(WithBacking([]int{1, 2}))); err == nil {
+			t.Errorf("%v: expected an error for a Tensor of ints", at.name)
+		}
+	}
+}
+`
+
 var (
-	clampTest *template.Template
-	signTest  *template.Template
+	clampTest      *template.Template
+	signTest       *template.Template
+	activationTest *template.Template
 )
 
 func init() {
 	clampTest = template.Must(template.New("clampTest").Funcs(funcs).Parse(clampTestsRaw))
 	signTest = template.Must(template.New("signTest").Funcs(funcs).Parse(signTestsRaw))
+	activationTest = template.Must(template.New("activationTest").Funcs(funcs).Parse(activationTestsRaw))
 }
 
 func generateUnaryTests(f io.Writer, generic *ManyKinds) {
 	clampTest.Execute(f, generic)
 	signTest.Execute(f, generic)
+	activationTest.Execute(f, activations)
 }
@@ -247,7 +247,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -279,7 +279,7 @@ func (t *Dense) reduceDefault(retVal *Dense, axis int, fn interface{}) error {
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -352,7 +352,7 @@ func (t *Dense) sReduce{{short .}}(axis int, zeroFn func(a, b []{{asType .}}) er
 				strideTrack++
 				if strideTrack >= stride {
 					strideTrack = 0
-					innerStart += stride
+					innerStart += (size - 1) * stride
 				}
 				innerStart++
 			}
@@ -23,6 +23,7 @@ const testDenseSumRaw = `var sumTests = []struct {
 	{"A.Sum(0,1) for {{.}}", {{asType . | title}}, Shape{2,3},[]int{0, 1}, ScalarShape(), {{asType .}}(15)},
 	{"A.Sum(1,0) for {{.}}", {{asType . | title}},  Shape{2,3},[]int{1, 0}, ScalarShape(), {{asType .}}(15)},
 	{"3T.Sum(1,2) for {{.}}", {{asType . | title}}, Shape{2,3,4}, []int{1,2}, Shape{2}, []{{asType .}}{66, {{if eq .String "int8"}}-46{{else}}210{{end}} }},
+	{"4T.Sum(2) for {{.}}", {{asType . | title}}, Shape{1,2,3,3}, []int{2}, Shape{1,2,3}, []{{asType .}}{9, 12, 15, 36, 39, 42}},
 	{{end -}}
 	{{end -}}
 }
@@ -65,6 +66,7 @@ const testDenseMaxRaw = `var maxTests = []struct {
 	{"A.Max(0,1)", {{asType . | title}}, Shape{2,3},[]int{0, 1}, ScalarShape(), {{asType .}}(5)},
 	{"A.Max(1,0)", {{asType . | title}}, Shape{2,3},[]int{1, 0}, ScalarShape(), {{asType .}}(5)},
 	{"3T.Max(1,2)", {{asType . | title}}, Shape{2,3,4}, []int{1,2}, Shape{2}, []{{asType .}}{11, 23} },
+	{"4T.Max(2)", {{asType . | title}}, Shape{1,2,3,3}, []int{2}, Shape{1,2,3}, []{{asType .}}{6, 7, 8, 15, 16, 17} },
 	{{end -}}
 	{{end -}}
 	{{end -}}
@@ -108,6 +110,7 @@ const testDenseMinRaw = `var minTests = []struct {
 	{"A.Min(0,1)", {{asType .|title}}, Shape{2,3}, []int{0, 1}, ScalarShape(), {{asType .}}(0)},
 	{"A.Min(1,0)", {{asType .|title}}, Shape{2,3}, []int{1, 0}, ScalarShape(), {{asType .}}(0)},
 	{"3T.Min(1,2)", {{asType . | title}}, Shape{2,3,4}, []int{1,2}, Shape{2}, []{{asType .}}{0,12} },
+	{"4T.Min(2)", {{asType . | title}}, Shape{1,2,3,3}, []int{2}, Shape{1,2,3}, []{{asType .}}{0, 1, 2, 9, 10, 11} },
 	{{end -}}
 	{{end -}}
 	{{end -}}
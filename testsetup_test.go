@@ -95,6 +95,21 @@ func extractF32(v Value) float32 {
 	panic(fmt.Sprintf("Unhandled types! Got %v of %T instead", v, v))
 }
 
+// dataAsF64s extracts the backing data of v as a []float64, converting float32s if necessary
+func dataAsF64s(v Value) []float64 {
+	switch data := v.Data().(type) {
+	case []float64:
+		return data
+	case []float32:
+		retVal := make([]float64, len(data))
+		for i, d := range data {
+			retVal[i] = float64(d)
+		}
+		return retVal
+	}
+	panic(fmt.Sprintf("Unhandled types! Got %v of %T instead", v, v))
+}
+
 func f64sTof32s(f []float64) []float32 {
 	retVal := make([]float32, len(f))
 	for i, v := range f {
@@ -48,6 +48,14 @@ func newTensorType(dims int, typ hm.Type) TensorType {
 	}
 }
 
+// shapedType returns the type of a value of the given shape: a Tensor type, or of itself if the shape has no dimensions.
+func shapedType(s tensor.Shape, of hm.Type) hm.Type {
+	if len(s) == 0 {
+		return of
+	}
+	return newTensorType(len(s), of)
+}
+
 // Name returns the name of the type, which will always be "Tensor". Satisfies the hm.Type interface.
 func (t TensorType) Name() string { return "Tensor" }
 
@@ -203,3 +203,143 @@ func checkArity(op arityer, inputs int) error {
 	}
 	return nil
 }
+
+// contiguousDense returns v as a *tensor.Dense whose backing data is laid out contiguously in row-major order.
+// If v is a view or has been transposed, a materialized copy is returned instead.
+func contiguousDense(v Value) (*tensor.Dense, error) {
+	t, ok := v.(*tensor.Dense)
+	if !ok {
+		return nil, errors.Errorf(nyiTypeFail, "contiguousDense", v)
+	}
+	if t.IsMaterializable() {
+		return t.Materialize().(*tensor.Dense), nil
+	}
+	return t, nil
+}
+
+// float64sBacking returns the backing slice of a contiguous Float64 *tensor.Dense. Unlike Data(), a slice is returned for
+// tensors of a single element as well, so the result may always be written into.
+func float64sBacking(t *tensor.Dense) []float64 {
+	if t.Shape().TotalSize() == 1 {
+		return (*[1]float64)(t.Pointer())[:]
+	}
+	return t.Data().([]float64)
+}
+
+// float32sBacking returns the backing slice of a contiguous Float32 *tensor.Dense. Unlike Data(), a slice is returned for
+// tensors of a single element as well, so the result may always be written into.
+func float32sBacking(t *tensor.Dense) []float32 {
+	if t.Shape().TotalSize() == 1 {
+		return (*[1]float32)(t.Pointer())[:]
+	}
+	return t.Data().([]float32)
+}
+
+// float64sOf returns the data of v as a []float64. float32 data is converted, in which case a copy is returned.
+// float64 data is returned as is, so care has to be taken not to write into the returned slice.
+func float64sOf(v Value) ([]float64, error) {
+	switch vt := v.(type) {
+	case *F64:
+		return []float64{float64(*vt)}, nil
+	case *F32:
+		return []float64{float64(*vt)}, nil
+	}
+
+	t, err := contiguousDense(v)
+	if err != nil {
+		return nil, err
+	}
+	switch data := t.Data().(type) {
+	case []float64:
+		return data, nil
+	case []float32:
+		retVal := make([]float64, len(data))
+		for i, d := range data {
+			retVal[i] = float64(d)
+		}
+		return retVal, nil
+	case float64:
+		return []float64{data}, nil
+	case float32:
+		return []float64{float64(data)}, nil
+	default:
+		return nil, errors.Errorf(nyiTypeFail, "float64sOf", data)
+	}
+}
+
+// valueFromFloat64s creates a tensor of the given Dtype and shape, converting data if necessary.
+// A scalar is created if the shape is a scalar shape with no dimensions.
+func valueFromFloat64s(dt tensor.Dtype, shape tensor.Shape, data []float64) (Value, error) {
+	if len(shape) == 0 {
+		switch dt {
+		case Float64:
+			return newF64(data[0]), nil
+		case Float32:
+			return newF32(float32(data[0])), nil
+		}
+	}
+
+	switch dt {
+	case Float64:
+		return tensor.New(tensor.WithBacking(data), tensor.WithShape(shape.Clone()...)), nil
+	case Float32:
+		backing := make([]float32, len(data))
+		for i, d := range data {
+			backing[i] = float32(d)
+		}
+		return tensor.New(tensor.WithBacking(backing), tensor.WithShape(shape.Clone()...)), nil
+	default:
+		return nil, errors.Errorf(nyiFail, "valueFromFloat64s", dt)
+	}
+}
+
+// float64sOfValues is float64sOf for many values.
+func float64sOfValues(vals []Value) (retVal [][]float64, err error) {
+	retVal = make([][]float64, len(vals))
+	for i, v := range vals {
+		if retVal[i], err = float64sOf(v); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// intsOf returns the data of v as a []int. v is expected to be an Int scalar or tensor.
+func intsOf(v Value) ([]int, error) {
+	if i, ok := v.(*I); ok {
+		return []int{i.any()}, nil
+	}
+
+	t, err := contiguousDense(v)
+	if err != nil {
+		return nil, err
+	}
+	switch data := t.Data().(type) {
+	case []int:
+		return data, nil
+	case int:
+		return []int{data}, nil
+	default:
+		return nil, errors.Errorf(nyiTypeFail, "intsOf", data)
+	}
+}
+
+// boolsOf returns the data of v as a []bool. v is expected to be a Bool scalar or tensor.
+func boolsOf(v Value) ([]bool, error) {
+	if b, ok := v.(*B); ok {
+		return []bool{b.any()}, nil
+	}
+
+	t, err := contiguousDense(v)
+	if err != nil {
+		return nil, err
+	}
+	switch data := t.Data().(type) {
+	case []bool:
+		return data, nil
+	case bool:
+		return []bool{data}, nil
+	default:
+		return nil, errors.Errorf(nyiTypeFail, "boolsOf", data)
+	}
+}
@@ -248,3 +248,34 @@ func BindDualValues(nodes ...*Node) VMOpt {
 	}
 	return f
 }
+
+// EvalMode sets m to execute the graph in inference (evaluation) mode. Ops that behave differently
+// when training (for example batch normalization, dropout, IfTraining and random nodes marked with MeanInEvalMode) will use their inference behaviour.
+//
+// By default VMs are created in training mode. An error is returned if m does not support modes.
+func EvalMode(m VM) error {
+	switch v := m.(type) {
+	case *lispMachine:
+		v.evalMode = true
+	case *tapeMachine:
+		v.evalMode = true
+	default:
+		return nyi("EvalMode", v)
+	}
+	return nil
+}
+
+// TrainMode sets m to execute the graph in training mode. This is the default mode of all VMs.
+// It's mostly useful to switch a VM back to training mode after calling EvalMode.
+// An error is returned if m does not support modes.
+func TrainMode(m VM) error {
+	switch v := m.(type) {
+	case *lispMachine:
+		v.evalMode = false
+	case *tapeMachine:
+		v.evalMode = false
+	default:
+		return nyi("TrainMode", v)
+	}
+	return nil
+}
@@ -30,6 +30,7 @@ type lispMachine struct {
 
 	runFlags     byte // supposed to go into state stuff.  Placed here for better compacting of struct
 	checkedRoots bool // supposed to go into state stuff.
+	evalMode     bool // when true, TrainModeOps are executed in inference mode
 }
 
 // NewLispMachine creates a VM that executes the graph as it is traversed. Depending on the VMOpts passed in
@@ -128,6 +129,9 @@ func (m *lispMachine) forward() (err error) {
 			return
 		case n.isRandom():
 			machineLogf("binding value of random node")
+			if tm, ok := n.op.(TrainModeOp); ok {
+				tm.SetTraining(!m.evalMode)
+			}
 
 			var v Value
 			if v, err = n.op.Do(); err != nil {
@@ -150,6 +154,10 @@ func (m *lispMachine) forward() (err error) {
 	op := n.op
 	var output *dualValue
 
+	if tm, ok := op.(TrainModeOp); ok {
+		tm.SetTraining(!m.evalMode)
+	}
+
 	inputs := make([]*dualValue, len(n.children))
 	for i, child := range n.children {
 		dv := child.boundTo.(*dualValue)
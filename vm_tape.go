@@ -36,6 +36,7 @@ type tapeMachine struct {
 	logFlags    byte
 
 	runFlags byte //  spare2: trace(copy values and put into nodes)
+	evalMode bool // when true, TrainModeOps are executed in inference mode
 }
 
 // NewTapeMachine creates a VM that executes a pre-compiled graph.
@@ -509,6 +510,10 @@ func (instr execOp) exec(m *tapeMachine) (err error) {
 	}
 	m.leaveLoggingContext()
 
+	if tm, ok := instr.op.(TrainModeOp); ok {
+		tm.SetTraining(!m.evalMode)
+	}
+
 	// Execute
 	var v Value
 	switch {
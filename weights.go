@@ -2,15 +2,16 @@ package gorgonia
 
 import (
 	"math"
-	"time"
 
 	"github.com/chewxy/gorgonia/tensor"
-	"github.com/leesper/go_rng"
 	"github.com/pkg/errors"
 )
 
 // This file provides several weight initialization utility functions.
-// It uses the rng package by leesper
+// It uses the rng package by leesper.
+//
+// The package level functions draw from DefaultRNG, which is seeded by the time. To get reproducible weights, seed DefaultRNG, or use
+// the methods of a seeded *RNG instead (such as the RNG of a graph built with WithGraphSeed).
 
 // InitWFn is a type of helper function to help initialize weights vector/matrices.
 // It generates the backing required for the tensors.
@@ -50,13 +51,16 @@ func RangedFrom(start int) InitWFn {
 // Example Usage:
 //		w := NewMatrix(g, Float64, WithName("w"), WithShape(2,2), WithInit(Gaussian(0, 1)))
 // This will create a backing slice of []float64, with the length of 4, and its values are drawn from a gaussian distro
-func Gaussian(mean, stdev float64) InitWFn {
+func Gaussian(mean, stdev float64) InitWFn { return defaultRNG.Gaussian(mean, stdev) }
+
+// Gaussian creates a InitWFn that draws from the RNG. See the package level Gaussian.
+func (r *RNG) Gaussian(mean, stdev float64) InitWFn {
 	f := func(dt tensor.Dtype, s ...int) interface{} {
 		switch dt {
 		case tensor.Float64:
-			return Gaussian64(mean, stdev, s...)
+			return r.Gaussian64(mean, stdev, s...)
 		case tensor.Float32:
-			return Gaussian32(mean, stdev, s...)
+			return r.Gaussian32(mean, stdev, s...)
 		default:
 			err := errors.Errorf(nyiTypeFail, "Gaussian init", dt)
 			panic(err)
@@ -69,13 +73,16 @@ func Gaussian(mean, stdev float64) InitWFn {
 // Example Usage:
 //		w := NewMatrix(g, Float64, WithName("w"), WithShape(2,2), WithInit(Uniform(-1, 1)))
 // This will create a backing slice of []float64, with the length of 4, and its values are drawn from a uniform distro
-func Uniform(low, high float64) InitWFn {
+func Uniform(low, high float64) InitWFn { return defaultRNG.Uniform(low, high) }
+
+// Uniform creates a InitWFn that draws from the RNG. See the package level Uniform.
+func (r *RNG) Uniform(low, high float64) InitWFn {
 	f := func(dt tensor.Dtype, s ...int) interface{} {
 		switch dt {
 		case tensor.Float64:
-			return Uniform64(low, high, s...)
+			return r.Uniform64(low, high, s...)
 		case tensor.Float32:
-			return Uniform32(low, high, s...)
+			return r.Uniform32(low, high, s...)
 		default:
 			err := errors.Errorf(nyiTypeFail, "Uniform init", dt)
 			panic(err)
@@ -85,13 +92,16 @@ func Uniform(low, high float64) InitWFn {
 }
 
 // GlorotN creates a InitWFn that populates a Value with weights normally sampled using Glorot et al.'s algorithm
-func GlorotN(gain float64) InitWFn {
+func GlorotN(gain float64) InitWFn { return defaultRNG.GlorotN(gain) }
+
+// GlorotN creates a InitWFn that draws from the RNG. See the package level GlorotN.
+func (r *RNG) GlorotN(gain float64) InitWFn {
 	f := func(dt tensor.Dtype, s ...int) interface{} {
 		switch dt {
 		case tensor.Float64:
-			return GlorotEtAlN64(gain, s...)
+			return r.GlorotEtAlN64(gain, s...)
 		case tensor.Float32:
-			return GlorotEtAlN32(gain, s...)
+			return r.GlorotEtAlN32(gain, s...)
 		default:
 			err := errors.Errorf(nyiTypeFail, "GlorotN", dt)
 			panic(err)
@@ -101,13 +111,16 @@ func GlorotN(gain float64) InitWFn {
 }
 
 // GlorotU creates a InitWFn that populates a Value with weights uniformly sampled using Glorot et al.'s algorithm
-func GlorotU(gain float64) InitWFn {
+func GlorotU(gain float64) InitWFn { return defaultRNG.GlorotU(gain) }
+
+// GlorotU creates a InitWFn that draws from the RNG. See the package level GlorotU.
+func (r *RNG) GlorotU(gain float64) InitWFn {
 	f := func(dt tensor.Dtype, s ...int) interface{} {
 		switch dt {
 		case tensor.Float64:
-			return GlorotEtAlU64(gain, s...)
+			return r.GlorotEtAlU64(gain, s...)
 		case tensor.Float32:
-			return GlorotEtAlU32(gain, s...)
+			return r.GlorotEtAlU32(gain, s...)
 		default:
 			err := errors.Errorf(nyiTypeFail, "GlorotU", dt)
 			panic(err)
@@ -118,9 +131,14 @@ func GlorotU(gain float64) InitWFn {
 
 // Gaussian64 returns a []float64 drawn from a gaussian distribution as defined by the mean and stdev
 func Gaussian64(mean, stdev float64, s ...int) []float64 {
+	return defaultRNG.Gaussian64(mean, stdev, s...)
+}
+
+// Gaussian64 is like the package level Gaussian64, but draws from the RNG.
+func (r *RNG) Gaussian64(mean, stdev float64, s ...int) []float64 {
 	size := tensor.Shape(s).TotalSize()
 
-	rand := rng.NewGaussianGenerator(time.Now().UnixNano())
+	rand := r.gaussian()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = rand.Gaussian(mean, stdev)
@@ -130,9 +148,14 @@ func Gaussian64(mean, stdev float64, s ...int) []float64 {
 
 // Gaussian32 returns a []float32 drawn from a gaussian distribution as defined by the mean and stdev
 func Gaussian32(mean, stdev float64, s ...int) []float32 {
+	return defaultRNG.Gaussian32(mean, stdev, s...)
+}
+
+// Gaussian32 is like the package level Gaussian32, but draws from the RNG.
+func (r *RNG) Gaussian32(mean, stdev float64, s ...int) []float32 {
 	size := tensor.Shape(s).TotalSize()
 
-	rand := rng.NewGaussianGenerator(time.Now().UnixNano())
+	rand := r.gaussian()
 	retVal := make([]float32, size)
 	for i := range retVal {
 		retVal[i] = float32(rand.Gaussian(mean, stdev))
@@ -141,10 +164,13 @@ func Gaussian32(mean, stdev float64, s ...int) []float32 {
 }
 
 // Uniform64 returns a []float64 drawn from a uniform distribution between [low, high) that is provided
-func Uniform64(low, high float64, s ...int) []float64 {
+func Uniform64(low, high float64, s ...int) []float64 { return defaultRNG.Uniform64(low, high, s...) }
+
+// Uniform64 is like the package level Uniform64, but draws from the RNG.
+func (r *RNG) Uniform64(low, high float64, s ...int) []float64 {
 	size := tensor.Shape(s).TotalSize()
 
-	rand := rng.NewUniformGenerator(time.Now().UnixNano())
+	rand := r.uniform()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = rand.Float64Range(low, high)
@@ -153,12 +179,15 @@ func Uniform64(low, high float64, s ...int) []float64 {
 }
 
 // Uniform32 returns a []float64 drawn from a uniform distribution between [low, high) that is provided
-func Uniform32(low, high float64, s ...int) []float32 {
+func Uniform32(low, high float64, s ...int) []float32 { return defaultRNG.Uniform32(low, high, s...) }
+
+// Uniform32 is like the package level Uniform32, but draws from the RNG.
+func (r *RNG) Uniform32(low, high float64, s ...int) []float32 {
 	size := tensor.Shape(s).TotalSize()
 	l := float32(low)
 	h := float32(high)
 
-	rand := rng.NewUniformGenerator(time.Now().UnixNano())
+	rand := r.uniform()
 	retVal := make([]float32, size)
 	for i := range retVal {
 		retVal[i] = rand.Float32Range(l, h)
@@ -168,10 +197,15 @@ func Uniform32(low, high float64, s ...int) []float32 {
 
 // Binomial64 returns a []float64 drawn from a binomial distribution given the trial and probability parameters.
 func Binomial64(trials, prob float64, s ...int) []float64 {
+	return defaultRNG.Binomial64(trials, prob, s...)
+}
+
+// Binomial64 is like the package level Binomial64, but draws from the RNG.
+func (r *RNG) Binomial64(trials, prob float64, s ...int) []float64 {
 	size := tensor.Shape(s).TotalSize()
 	t := int64(trials)
 
-	rand := rng.NewBinomialGenerator(time.Now().UnixNano())
+	rand := r.binomial()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = float64(rand.Binomial(t, prob))
@@ -181,10 +215,15 @@ func Binomial64(trials, prob float64, s ...int) []float64 {
 
 // Binomial32 returns a []float32 drawn from a binomial distribution given the trial and probability parameters.
 func Binomial32(trials, prob float64, s ...int) []float32 {
+	return defaultRNG.Binomial32(trials, prob, s...)
+}
+
+// Binomial32 is like the package level Binomial32, but draws from the RNG.
+func (r *RNG) Binomial32(trials, prob float64, s ...int) []float32 {
 	size := tensor.Shape(s).TotalSize()
 	t := int64(trials)
 
-	rand := rng.NewBinomialGenerator(time.Now().UnixNano())
+	rand := r.binomial()
 	retVal := make([]float32, size)
 	for i := range retVal {
 		retVal[i] = float32(rand.Binomial(t, prob))
@@ -197,7 +236,10 @@ func Binomial32(trials, prob float64, s ...int) []float32 {
 // GlorotEtAlN64 returns float64 weights sampled from a normal distribution
 // using the methods specified in Glorot et. al (2010).
 // See also: http://jmlr.org/proceedings/papers/v9/glorot10a/glorot10a.pdf
-func GlorotEtAlN64(gain float64, s ...int) []float64 {
+func GlorotEtAlN64(gain float64, s ...int) []float64 { return defaultRNG.GlorotEtAlN64(gain, s...) }
+
+// GlorotEtAlN64 is like the package level GlorotEtAlN64, but draws from the RNG.
+func (r *RNG) GlorotEtAlN64(gain float64, s ...int) []float64 {
 	var n1, n2 int
 	fieldSize := 1
 	switch len(s) {
@@ -219,7 +261,7 @@ func GlorotEtAlN64(gain float64, s ...int) []float64 {
 
 	stdev := gain * math.Sqrt(2.0/fanIn)
 
-	rand := rng.NewGaussianGenerator(time.Now().UnixNano())
+	rand := r.gaussian()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = rand.Gaussian(0.0, stdev)
@@ -230,8 +272,11 @@ func GlorotEtAlN64(gain float64, s ...int) []float64 {
 // GlorotEtAlN32 returns float32 weights sampled from a normal distribution
 // using the methods specified in Glorot et. al (2010).
 // See also: http://jmlr.org/proceedings/papers/v9/glorot10a/glorot10a.pdf
-func GlorotEtAlN32(gain float64, s ...int) []float32 {
-	f64 := GlorotEtAlN64(gain, s...)
+func GlorotEtAlN32(gain float64, s ...int) []float32 { return defaultRNG.GlorotEtAlN32(gain, s...) }
+
+// GlorotEtAlN32 is like the package level GlorotEtAlN32, but draws from the RNG.
+func (r *RNG) GlorotEtAlN32(gain float64, s ...int) []float32 {
+	f64 := r.GlorotEtAlN64(gain, s...)
 	retVal := make([]float32, len(f64))
 	for i, v := range f64 {
 		retVal[i] = float32(v)
@@ -247,7 +292,10 @@ func GlorotEtAlN32(gain float64, s ...int) []float32 {
 // 		1.0 for gain for weights that will be used in linear and/or sigmoid units
 //		math.Sqrt(2.0) for gain for weights that will be used in ReLU units
 //		math.Sqrt(2.0 / (1+alpha*alpha)) for ReLU that are leaky with alpha
-func GlorotEtAlU64(gain float64, s ...int) []float64 {
+func GlorotEtAlU64(gain float64, s ...int) []float64 { return defaultRNG.GlorotEtAlU64(gain, s...) }
+
+// GlorotEtAlU64 is like the package level GlorotEtAlU64, but draws from the RNG.
+func (r *RNG) GlorotEtAlU64(gain float64, s ...int) []float64 {
 	var n1, n2 int
 	fieldSize := 1
 	switch len(s) {
@@ -271,7 +319,7 @@ func GlorotEtAlU64(gain float64, s ...int) []float64 {
 	lo := 0.0 - math.Sqrt(3.0)*stdev
 	hi := 0.0 + math.Sqrt(3.0)*stdev
 
-	rand := rng.NewUniformGenerator(time.Now().UnixNano())
+	rand := r.uniform()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = rand.Float64Range(lo, hi)
@@ -287,8 +335,11 @@ func GlorotEtAlU64(gain float64, s ...int) []float64 {
 // 		1.0 for gain for weights that will be used in linear and/or sigmoid units
 //		math.Sqrt(2.0) for gain for weights that will be used in ReLU units
 //		math.Sqrt(2.0 / (1+alpha*alpha)) for ReLU that are leaky with alpha
-func GlorotEtAlU32(gain float64, s ...int) []float32 {
-	f64 := GlorotEtAlN64(gain, s...)
+func GlorotEtAlU32(gain float64, s ...int) []float32 { return defaultRNG.GlorotEtAlU32(gain, s...) }
+
+// GlorotEtAlU32 is like the package level GlorotEtAlU32, but draws from the RNG.
+func (r *RNG) GlorotEtAlU32(gain float64, s ...int) []float32 {
+	f64 := r.GlorotEtAlN64(gain, s...)
 	retVal := make([]float32, len(f64))
 	for i, v := range f64 {
 		retVal[i] = float32(v)
@@ -305,7 +356,10 @@ func GlorotEtAlU32(gain float64, s ...int) []float32 {
 // 		1.0 for gain for weights that will be used in linear and/or sigmoid units
 //		math.Sqrt(2.0) for gain for weights that will be used in ReLU units
 //		math.Sqrt(2.0 / (1+alpha*alpha)) for ReLU that are leaky with alpha
-func HeEtAlN64(gain float64, s ...int) []float64 {
+func HeEtAlN64(gain float64, s ...int) []float64 { return defaultRNG.HeEtAlN64(gain, s...) }
+
+// HeEtAlN64 is like the package level HeEtAlN64, but draws from the RNG.
+func (r *RNG) HeEtAlN64(gain float64, s ...int) []float64 {
 	var fanIn float64
 
 	switch len(s) {
@@ -323,7 +377,7 @@ func HeEtAlN64(gain float64, s ...int) []float64 {
 	size := tensor.Shape(s).TotalSize()
 	stdev := gain * math.Sqrt(1.0/fanIn)
 
-	rand := rng.NewGaussianGenerator(time.Now().UnixNano())
+	rand := r.gaussian()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = rand.Gaussian(0.0, stdev)
@@ -340,7 +394,10 @@ func HeEtAlN64(gain float64, s ...int) []float64 {
 // 		1.0 for gain for weights that will be used in linear and/or sigmoid units
 //		math.Sqrt(2.0) for gain for weights that will be used in ReLU units
 //		math.Sqrt(2.0 / (1+alpha*alpha)) for ReLU that are leaky with alpha
-func HeEtAlU64(gain float64, s ...int) []float64 {
+func HeEtAlU64(gain float64, s ...int) []float64 { return defaultRNG.HeEtAlU64(gain, s...) }
+
+// HeEtAlU64 is like the package level HeEtAlU64, but draws from the RNG.
+func (r *RNG) HeEtAlU64(gain float64, s ...int) []float64 {
 	var fanIn float64
 
 	switch len(s) {
@@ -361,7 +418,7 @@ func HeEtAlU64(gain float64, s ...int) []float64 {
 	lo := 0.0 - math.Sqrt(3.0)*stdev
 	hi := 0.0 + math.Sqrt(3.0)*stdev
 
-	rand := rng.NewUniformGenerator(time.Now().UnixNano())
+	rand := r.uniform()
 	retVal := make([]float64, size)
 	for i := range retVal {
 		retVal[i] = rand.Float64Range(lo, hi)